@@ -6,6 +6,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/gflarity/bls_agent/internal/codec"
 	"github.com/gflarity/bls_agent/internal/workflows/arxiv"
 	"github.com/joho/godotenv"
 	"go.temporal.io/sdk/client"
@@ -35,10 +36,17 @@ func main() {
 		taskQueue = "bls-agent" // default
 	}
 
+	// Encrypt payloads so release text and drafts aren't stored in plain text
+	dataConverter, err := codec.DataConverterFromEnv()
+	if err != nil {
+		log.Fatalln("Unable to create data converter", err)
+	}
+
 	// Create Temporal client
 	c, err := client.Dial(client.Options{
-		HostPort:  temporalHostPort,
-		Namespace: temporalNamespace,
+		HostPort:      temporalHostPort,
+		Namespace:     temporalNamespace,
+		DataConverter: dataConverter,
 	})
 	if err != nil {
 		log.Fatalln("Unable to create Temporal client", err)
@@ -59,9 +67,14 @@ func main() {
 	workflowParams := arxiv.PaperOfTheDayWorkflowParams{
 		Date: targetDate,
 		// OpenAI configuration
-		OpenAIAPIKey:  os.Getenv("OPENAI_API_KEY"),
 		OpenAIBaseURL: os.Getenv("OPENAI_BASE_URL"),
 		OpenAIModel:   os.Getenv("OPENAI_MODEL"),
+		// Model that filters papers, DeepSeek R1 unless set
+		ArxivModel: os.Getenv("ARXIV_MODEL"),
+		// Worker-side credential profile
+		CredentialProfile: os.Getenv("CREDENTIAL_PROFILE"),
+		// Keep the model's reasoning with each paper's decision
+		KeepReasoning: os.Getenv("KEEP_REASONING") == "true",
 	}
 
 	// Create workflow options
@@ -8,6 +8,8 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/gflarity/bls_agent/internal/codec"
+	"github.com/gflarity/bls_agent/internal/credentials"
 	"github.com/gflarity/bls_agent/internal/workflows/arxiv"
 	"github.com/joho/godotenv"
 
@@ -50,17 +52,31 @@ func main() {
 	// Create Temporal logger from slog
 	temporalLogger := temporallog.NewStructuredLogger(slogLogger)
 
+	// Encrypt payloads so release text and drafts aren't stored in plain text
+	dataConverter, err := codec.DataConverterFromEnv()
+	if err != nil {
+		panic(fmt.Errorf("unable to create data converter: %w", err))
+	}
+
 	// Create Temporal client with custom logger
 	c, err := client.Dial(client.Options{
-		HostPort:  temporalHostPort,
-		Namespace: temporalNamespace,
-		Logger:    temporalLogger,
+		HostPort:      temporalHostPort,
+		Namespace:     temporalNamespace,
+		Logger:        temporalLogger,
+		DataConverter: dataConverter,
 	})
 	if err != nil {
 		panic(fmt.Errorf("unable to create Temporal client: %w", err))
 	}
 	defer c.Close()
 
+	// Resolve secrets on the worker so they never appear in workflow inputs
+	credentialProvider, err := credentials.FromEnv()
+	if err != nil {
+		panic(fmt.Errorf("unable to create credential provider: %w", err))
+	}
+	arxiv.SetCredentialProvider(credentialProvider)
+
 	// Create worker
 	w := worker.New(c, taskQueue, worker.Options{})
 
@@ -71,7 +87,7 @@ func main() {
 	w.RegisterActivity(arxiv.GetArxivIdsForDateActivity)
 	w.RegisterActivity(arxiv.GetArxivAbstractActivity)
 	w.RegisterActivity(arxiv.ExtractPaperTextActivity)
-	w.RegisterActivity(arxiv.CompleteWithSchemaActivity)
+	w.RegisterActivity(arxiv.CompleteWithReasoningActivity)
 
 	// Start worker
 	sigChan := make(chan os.Signal, 1)
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gflarity/bls_agent/internal/codec"
+	"github.com/gflarity/bls_agent/internal/workflows/bls"
+	"github.com/joho/godotenv"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+)
+
+const usage = `Usage:
+  approver list                       List drafts waiting for approval
+  approver approve <workflow-id>      Post the draft as is
+  approver edit <workflow-id> <text>  Post the given text instead of the draft
+  approver reject <workflow-id>       Drop the draft without posting`
+
+func main() {
+	// Load environment variables from .env file
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Warning: Could not load .env file: %v", err)
+		// Continue execution as environment variables might be set elsewhere
+	}
+
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	// Encrypt payloads so release text and drafts aren't stored in plain text
+	dataConverter, err := codec.DataConverterFromEnv()
+	if err != nil {
+		log.Fatalln("Unable to create data converter", err)
+	}
+
+	// Create Temporal client
+	c, err := client.Dial(client.Options{
+		HostPort:      os.Getenv("TEMPORAL_HOST_PORT"),
+		Namespace:     os.Getenv("TEMPORAL_NAMESPACE"),
+		DataConverter: dataConverter,
+	})
+	if err != nil {
+		log.Fatalln("Unable to create Temporal client", err)
+	}
+	defer c.Close()
+
+	// Reviewer name recorded with each decision
+	reviewer := os.Getenv("APPROVER_NAME")
+	if reviewer == "" {
+		reviewer = os.Getenv("USER")
+	}
+
+	ctx := context.Background()
+	args := os.Args[2:]
+
+	switch os.Args[1] {
+	case "list":
+		err = listPending(ctx, c)
+	case "approve":
+		err = sendDecision(ctx, c, args, 1, bls.ApprovalDecision{Action: bls.ApprovalApprove, Reviewer: reviewer})
+	case "edit":
+		err = sendDecision(ctx, c, args, 2, bls.ApprovalDecision{Action: bls.ApprovalEdit, Text: strings.Join(args[min(1, len(args)):], " "), Reviewer: reviewer})
+	case "reject":
+		err = sendDecision(ctx, c, args, 1, bls.ApprovalDecision{Action: bls.ApprovalReject, Reviewer: reviewer})
+	default:
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// listPending prints every running event workflow whose draft is waiting for approval.
+func listPending(ctx context.Context, c client.Client) error {
+	var nextPageToken []byte
+	found := 0
+	for {
+		resp, err := c.ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+			Query:         "WorkflowType = 'BLSEventSummaryWorkflow' AND ExecutionStatus = 'Running'",
+			NextPageToken: nextPageToken,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list workflows: %w", err)
+		}
+
+		for _, info := range resp.Executions {
+			execution := info.GetExecution()
+			value, err := c.QueryWorkflow(ctx, execution.GetWorkflowId(), execution.GetRunId(), bls.DraftQuery)
+			if err != nil {
+				log.Printf("Warning: failed to query %s: %v", execution.GetWorkflowId(), err)
+				continue
+			}
+
+			var draft bls.Draft
+			if err := value.Get(&draft); err != nil {
+				log.Printf("Warning: failed to decode draft for %s: %v", execution.GetWorkflowId(), err)
+				continue
+			}
+			if draft.Status != bls.DraftStatusPending {
+				continue
+			}
+
+			found++
+			fmt.Printf("%s\n", execution.GetWorkflowId())
+			fmt.Printf("  Event:    %s\n", draft.Event.Summary)
+			fmt.Printf("  Deadline: %s (in %s)\n", draft.ApprovalDeadline.Format(time.RFC3339), time.Until(draft.ApprovalDeadline).Round(time.Minute))
+			fmt.Printf("  Draft:    %s\n\n", draft.Text)
+		}
+
+		nextPageToken = resp.NextPageToken
+		if len(nextPageToken) == 0 {
+			break
+		}
+	}
+
+	if found == 0 {
+		fmt.Println("No drafts waiting for approval.")
+	}
+	return nil
+}
+
+// sendDecision signals the decision to the workflow named by args[0].
+func sendDecision(ctx context.Context, c client.Client, args []string, wantArgs int, decision bls.ApprovalDecision) error {
+	if len(args) < wantArgs {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	workflowID := args[0]
+	if err := c.SignalWorkflow(ctx, workflowID, "", bls.ApprovalSignal, decision); err != nil {
+		return fmt.Errorf("failed to signal %s: %w", workflowID, err)
+	}
+
+	log.Printf("Sent %s for %s\n", decision.Action, workflowID)
+	return nil
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gflarity/bls_agent/internal/codec"
+	"github.com/gflarity/bls_agent/internal/workflows/bls"
+	blspkg "github.com/gflarity/bls_agent/pkg/bls"
+	"github.com/joho/godotenv"
+	"go.temporal.io/sdk/client"
+)
+
+// result is one line of the BACKFILL_OUTPUT file.
+type result struct {
+	Key     string    `json:"key"`
+	Summary string    `json:"summary"`
+	Start   time.Time `json:"start"`
+	Tweet   string    `json:"tweet,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// backfill replays past calendar events through BLSEventSummaryWorkflow in dry
+// run mode, fetching each release from the bls.gov archive, so prompts can be
+// evaluated on real history. Events come from the release calendar, so only
+// the past events it still lists can be replayed. They run one at a time, oldest
+// first, so each release is compared with the one before it.
+func main() {
+	// Load environment variables from .env file
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Warning: Could not load .env file: %v", err)
+		// Continue execution as environment variables might be set elsewhere
+	}
+
+	// BACKFILL_FROM is required, BACKFILL_TO defaults to now
+	from, err := time.Parse("2006-01-02", os.Getenv("BACKFILL_FROM"))
+	if err != nil {
+		log.Fatalf("Invalid BACKFILL_FROM '%s', want YYYY-MM-DD: %v", os.Getenv("BACKFILL_FROM"), err)
+	}
+	to := time.Now()
+	if toStr := os.Getenv("BACKFILL_TO"); toStr != "" {
+		day, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			log.Fatalf("Invalid BACKFILL_TO '%s', want YYYY-MM-DD: %v", toStr, err)
+		}
+		// Include the whole day, but nothing that hasn't been released yet
+		if end := day.AddDate(0, 0, 1); end.Before(to) {
+			to = end
+		}
+	}
+
+	blsClient := blspkg.NewClient()
+	if baseURL := os.Getenv("BLS_BASE_URL"); baseURL != "" {
+		blsClient.BaseURL = baseURL
+	}
+	registry := blspkg.DefaultRegistry()
+	if path := os.Getenv("BLS_RELEASE_REGISTRY"); path != "" {
+		registry, err = blspkg.LoadRegistryFile(path)
+		if err != nil {
+			log.Fatalf("Invalid release registry: %v", err)
+		}
+		blsClient.Registry = registry
+	}
+
+	// Optionally only replay some releases, e.g. "Consumer Price Index,Employment Situation"
+	var only []blspkg.Release
+	if releasesStr := os.Getenv("BACKFILL_RELEASES"); releasesStr != "" {
+		for _, summary := range strings.Split(releasesStr, ",") {
+			release, ok := registry.Lookup(summary)
+			if !ok {
+				log.Fatalf("Unknown release in BACKFILL_RELEASES: %q", strings.TrimSpace(summary))
+			}
+			only = append(only, release)
+		}
+	}
+
+	ctx := context.Background()
+	allEvents, err := blsClient.GetAllEvents(ctx)
+	if err != nil {
+		log.Fatalf("Error getting all events: %v", err)
+	}
+
+	var events []blspkg.Event
+	for _, event := range blspkg.EventsBetween(allEvents, from.Add(-time.Nanosecond), to) {
+		release, ok := registry.Lookup(event.Summary)
+		if !ok {
+			log.Printf("Skipping %s: no release for event", event.Summary)
+			continue
+		}
+		if len(only) > 0 && !contains(only, release) {
+			continue
+		}
+		events = append(events, event)
+	}
+	log.Printf("Replaying %d events from %s to %s", len(events), from.Format("2006-01-02"), to.Format("2006-01-02 15:04"))
+
+	// Write one JSON line per event, e.g. to compare prompts side by side
+	var output *json.Encoder
+	if path := os.Getenv("BACKFILL_OUTPUT"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			log.Fatalf("Unable to create BACKFILL_OUTPUT: %v", err)
+		}
+		defer f.Close()
+		output = json.NewEncoder(f)
+	}
+
+	// Create workflow parameters. Replays are always dry runs, and secrets are
+	// resolved by the worker from the credential profile.
+	workflowParams := bls.WorkflowParams{
+		// OpenAI configuration
+		OpenAIBaseURL: os.Getenv("OPENAI_BASE_URL"),
+		OpenAIModel:   os.Getenv("OPENAI_MODEL"),
+		// Worker-side credential profile
+		CredentialProfile: os.Getenv("CREDENTIAL_PROFILE"),
+
+		FromArchive: true,
+	}
+
+	// Encrypt payloads so release text and drafts aren't stored in plain text
+	dataConverter, err := codec.DataConverterFromEnv()
+	if err != nil {
+		log.Fatalln("Unable to create data converter", err)
+	}
+
+	// Create Temporal client
+	c, err := client.Dial(client.Options{
+		HostPort:      os.Getenv("TEMPORAL_HOST_PORT"),
+		Namespace:     os.Getenv("TEMPORAL_NAMESPACE"),
+		DataConverter: dataConverter,
+	})
+	if err != nil {
+		log.Fatalln("Unable to create Temporal client", err)
+	}
+	defer c.Close()
+
+	failed := 0
+	for i, event := range events {
+		// Replays get their own IDs so they never block or skip a real run
+		workflowOptions := client.StartWorkflowOptions{
+			ID:        "bls-backfill-" + event.Key(),
+			TaskQueue: os.Getenv("TEMPORAL_TASK_QUEUE"),
+		}
+
+		r := result{Key: event.Key(), Summary: event.Summary, Start: *event.Start}
+		we, err := c.ExecuteWorkflow(ctx, workflowOptions, bls.BLSEventSummaryWorkflow, bls.EventWorkflowParams{
+			WorkflowParams: workflowParams,
+			Event:          event,
+		})
+		if err == nil {
+			err = we.Get(ctx, &r.Tweet)
+		}
+		if err != nil {
+			r.Error = err.Error()
+			failed++
+		}
+
+		fmt.Printf("%d/%d %s %s\n", i+1, len(events), event.Start.Format("2006-01-02"), event.Summary)
+		if r.Error != "" {
+			fmt.Printf("  error: %s\n", r.Error)
+		} else {
+			fmt.Printf("  %s\n", r.Tweet)
+		}
+		if output != nil {
+			if err := output.Encode(r); err != nil {
+				log.Fatalf("Unable to write BACKFILL_OUTPUT: %v", err)
+			}
+		}
+	}
+
+	fmt.Printf("\n%d events replayed, %d failed\n", len(events), failed)
+}
+
+// contains reports whether releases includes release.
+func contains(releases []blspkg.Release, release blspkg.Release) bool {
+	for _, r := range releases {
+		if r.Summary == release.Summary {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gflarity/bls_agent/internal/codec"
+	"github.com/gflarity/bls_agent/internal/workflows/bls"
+	"github.com/gflarity/bls_agent/pkg/calendar"
+	"github.com/joho/godotenv"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/client"
+)
+
+const (
+	// defaultAgencies are covered unless CALENDAR_AGENCIES is set. BLS releases
+	// are left to the scheduler workflow.
+	defaultAgencies = "BEA,Census,FOMC"
+	// defaultWindow is how far ahead events are started unless CALENDAR_WINDOW is set.
+	defaultWindow = 24 * time.Hour
+	// startDelay gives the agency a moment to publish before the release is fetched.
+	startDelay = 2 * time.Minute
+)
+
+// calendar_starter reads the release schedules of other agencies and starts a
+// BLSEventSummaryWorkflow for every release in the coming window, delayed until
+// just after the release. Run it at least once per window, e.g. daily from
+// cron; events that were already started are left alone.
+func main() {
+	// Load environment variables from .env file
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Warning: Could not load .env file: %v", err)
+		// Continue execution as environment variables might be set elsewhere
+	}
+
+	window := defaultWindow
+	if windowStr := os.Getenv("CALENDAR_WINDOW"); windowStr != "" {
+		var err error
+		window, err = time.ParseDuration(windowStr)
+		if err != nil {
+			log.Fatalf("Invalid CALENDAR_WINDOW '%s': %v", windowStr, err)
+		}
+	}
+
+	agencies := os.Getenv("CALENDAR_AGENCIES")
+	if agencies == "" {
+		agencies = defaultAgencies
+	}
+	var sources []calendar.Source
+	for _, agency := range strings.Split(agencies, ",") {
+		switch strings.TrimSpace(agency) {
+		case calendar.AgencyBLS:
+			sources = append(sources, &calendar.BLSSource{})
+		case calendar.AgencyBEA:
+			sources = append(sources, &calendar.BEASource{})
+		case calendar.AgencyCensus:
+			sources = append(sources, &calendar.CensusSource{})
+		case calendar.AgencyFOMC:
+			sources = append(sources, &calendar.FOMCSource{})
+		default:
+			log.Fatalf("Invalid CALENDAR_AGENCIES '%s', unknown agency '%s'", agencies, agency)
+		}
+	}
+
+	// Create workflow parameters. Secrets are resolved by the worker from the
+	// credential profile, so they are never written into Temporal history.
+	workflowParams := bls.WorkflowParams{
+		// OpenAI configuration
+		OpenAIBaseURL: os.Getenv("OPENAI_BASE_URL"),
+		OpenAIModel:   os.Getenv("OPENAI_MODEL"),
+		// Worker-side credential profile
+		CredentialProfile: os.Getenv("CREDENTIAL_PROFILE"),
+
+		// Tweet For Real
+		TweetForReal: os.Getenv("TWEET_FOR_REAL") == "true",
+
+		// Hold drafts for review before posting
+		RequireApproval: os.Getenv("REQUIRE_APPROVAL") == "true",
+	}
+
+	// A source that fails doesn't stop the others
+	events, err := calendar.Collect(context.Background(), sources...)
+	if err != nil {
+		log.Printf("Warning: Could not read every calendar: %v", err)
+	}
+
+	// Encrypt payloads so release text and drafts aren't stored in plain text
+	dataConverter, err := codec.DataConverterFromEnv()
+	if err != nil {
+		log.Fatalln("Unable to create data converter", err)
+	}
+
+	// Create Temporal client
+	c, err := client.Dial(client.Options{
+		HostPort:      os.Getenv("TEMPORAL_HOST_PORT"),
+		Namespace:     os.Getenv("TEMPORAL_NAMESPACE"),
+		DataConverter: dataConverter,
+	})
+	if err != nil {
+		log.Fatalln("Unable to create Temporal client", err)
+	}
+	defer c.Close()
+
+	now := time.Now()
+	started := 0
+	for _, event := range events {
+		if !event.Start.After(now) || event.Start.After(now.Add(window)) {
+			continue
+		}
+		if event.ReleaseURL == "" {
+			log.Printf("Skipping %s %s, no release URL", event.Agency, event.Series)
+			continue
+		}
+
+		// The event's workflow ID makes starting it again a no-op
+		blsEvent := event.BLSEvent()
+		workflowOptions := client.StartWorkflowOptions{
+			ID:                    bls.EventWorkflowID(blsEvent, workflowParams.TweetForReal),
+			TaskQueue:             os.Getenv("TEMPORAL_TASK_QUEUE"),
+			WorkflowIDReusePolicy: enumspb.WORKFLOW_ID_REUSE_POLICY_REJECT_DUPLICATE,
+			StartDelay:            event.Start.Sub(now) + startDelay,
+		}
+		we, err := c.ExecuteWorkflow(context.Background(), workflowOptions, bls.BLSEventSummaryWorkflow, bls.EventWorkflowParams{
+			WorkflowParams: workflowParams,
+			Event:          blsEvent,
+			Agency:         calendar.AgencyName(event.Agency),
+		})
+		if err != nil {
+			log.Printf("Unable to start workflow for %s %s: %v", event.Agency, event.Series, err)
+			continue
+		}
+		log.Printf("Started BLSEventSummaryWorkflow for %s %s at %s: %s\n", event.Agency, event.Title, event.Start.Format(time.RFC3339), we.GetID())
+		started++
+	}
+
+	log.Printf("Started %d calendar events\n", started)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gflarity/bls_agent/internal/feed"
+	"github.com/gflarity/bls_agent/internal/ledger"
+	"github.com/gflarity/bls_agent/pkg/bls"
+	"github.com/joho/godotenv"
+)
+
+// feedPath is where the calendar is served, e.g. http://localhost:8080/bls.ics.
+const feedPath = "/bls.ics"
+
+// ics_server serves an iCalendar feed of the releases the bot covers, with each
+// release's page and, once posted, a link to the post, so the team can
+// subscribe to it in their calendars.
+func main() {
+	// Load environment variables from .env file
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Warning: Could not load .env file: %v", err)
+	}
+
+	client := bls.NewClient()
+	if baseURL := os.Getenv("BLS_BASE_URL"); baseURL != "" {
+		client.BaseURL = baseURL
+	}
+	if path := os.Getenv("BLS_RELEASE_REGISTRY"); path != "" {
+		registry, err := bls.LoadRegistryFile(path)
+		if err != nil {
+			log.Fatalf("Invalid release registry: %v", err)
+		}
+		client.Registry = registry
+	}
+
+	// Keep the last good calendar on disk so subscribers don't hammer bls.gov
+	calendarDir := os.Getenv("CALENDAR_CACHE_DIR")
+	if calendarDir == "" {
+		calendarDir = "data/calendar" // default
+	}
+	calendar, err := bls.NewCalendarCache(client, calendarDir)
+	if err != nil {
+		log.Fatalf("Unable to open calendar cache: %v", err)
+	}
+
+	// The worker's publication ledger links each release to its post
+	ledgerDir := os.Getenv("PUBLICATION_LEDGER_DIR")
+	if ledgerDir == "" {
+		ledgerDir = "data/ledger" // default
+	}
+	publications, err := ledger.NewFileStore(ledgerDir)
+	if err != nil {
+		log.Fatalf("Unable to open publication ledger: %v", err)
+	}
+
+	releaseFeed := &feed.Feed{
+		Client:       client,
+		Calendar:     calendar,
+		Publications: publications,
+		Name:         os.Getenv("FEED_NAME"),
+	}
+	if families := os.Getenv("FEED_FAMILIES"); families != "" {
+		releaseFeed.Families = strings.Split(families, ",")
+	}
+
+	addr := os.Getenv("FEED_ADDR")
+	if addr == "" {
+		addr = ":8080" // default
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(feedPath, releaseFeed)
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("Serving release calendar on %s%s", addr, feedPath)
+	if err := server.ListenAndServe(); err != nil {
+		log.Fatalf("Server stopped: %v", err)
+	}
+}
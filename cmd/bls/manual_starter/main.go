@@ -6,6 +6,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/gflarity/bls_agent/internal/codec"
 	"github.com/gflarity/bls_agent/internal/workflows/bls"
 	"github.com/joho/godotenv"
 	"go.temporal.io/sdk/client"
@@ -18,36 +19,45 @@ func main() {
 		// Continue execution as environment variables might be set elsewhere
 	}
 
-	// Create workflow parameters with credentials from environment
+	// Create workflow parameters. Secrets are resolved by the worker from the
+	// credential profile, so they are never written into Temporal history.
 	workflowParams := bls.WorkflowParams{
 		Mins: 3 * 1440.0, // 1440. = 24 hours in minutes
 		// OpenAI configuration
-		OpenAIAPIKey:  os.Getenv("OPENAI_API_KEY"),
 		OpenAIBaseURL: os.Getenv("OPENAI_BASE_URL"),
 		OpenAIModel:   os.Getenv("OPENAI_MODEL"),
-		// Twitter credentials
-		TwitterAPIKey:       os.Getenv("X_API_KEY"),
-		TwitterAPISecret:    os.Getenv("X_API_SECRET"),
-		TwitterAccessToken:  os.Getenv("X_ACCESS_TOKEN"),
-		TwitterAccessSecret: os.Getenv("X_ACCESS_TOKEN_SECRET"),
+		// Worker-side credential profile
+		CredentialProfile: os.Getenv("CREDENTIAL_PROFILE"),
 
 		// Tweet For Real
 		TweetForReal: os.Getenv("TWEET_FOR_REAL") == "true",
+
+		// Hold drafts for review before posting
+		RequireApproval: os.Getenv("REQUIRE_APPROVAL") == "true",
+
+		// Keep the model's reasoning in the publication record
+		KeepReasoning: os.Getenv("KEEP_REASONING") == "true",
 	}
 
-	// Validate required environment variables
-	if workflowParams.OpenAIAPIKey == "" {
-		log.Fatalln("OPENAI_API_KEY environment variable is required")
+	if timeoutStr := os.Getenv("APPROVAL_TIMEOUT"); timeoutStr != "" {
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil {
+			log.Fatalf("Invalid APPROVAL_TIMEOUT '%s': %v", timeoutStr, err)
+		}
+		workflowParams.ApprovalTimeout = timeout
 	}
-	if workflowParams.TwitterAPIKey == "" || workflowParams.TwitterAPISecret == "" ||
-		workflowParams.TwitterAccessToken == "" || workflowParams.TwitterAccessSecret == "" {
-		log.Fatalln("All Twitter credentials (X_API_KEY, X_API_SECRET, X_ACCESS_TOKEN, X_ACCESS_TOKEN_SECRET) are required")
+
+	// Encrypt payloads so release text and drafts aren't stored in plain text
+	dataConverter, err := codec.DataConverterFromEnv()
+	if err != nil {
+		log.Fatalln("Unable to create data converter", err)
 	}
 
 	// Create Temporal client
 	c, err := client.Dial(client.Options{
-		HostPort:  os.Getenv("TEMPORAL_HOST_PORT"),
-		Namespace: os.Getenv("TEMPORAL_NAMESPACE"),
+		HostPort:      os.Getenv("TEMPORAL_HOST_PORT"),
+		Namespace:     os.Getenv("TEMPORAL_NAMESPACE"),
+		DataConverter: dataConverter,
 	})
 	if err != nil {
 		log.Fatalln("Unable to create Temporal client", err)
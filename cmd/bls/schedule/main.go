@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gflarity/bls_agent/pkg/bls"
+	"github.com/joho/godotenv"
+)
+
+// defaultWindow is how far ahead the schedule looks unless SCHEDULE_WINDOW is set.
+const defaultWindow = 7 * 24 * time.Hour
+
+// scheduledRelease is how an event is written in the JSON format.
+type scheduledRelease struct {
+	Summary string     `json:"summary"`
+	Release string     `json:"release,omitempty"`
+	Family  string     `json:"family,omitempty"`
+	Start   time.Time  `json:"start"`
+	End     *time.Time `json:"end,omitempty"`
+	URL     string     `json:"url,omitempty"`
+}
+
+// schedule prints the upcoming BLS releases, by default for the next week, so
+// coverage can be planned. SCHEDULE_FORMAT picks a table (the default), JSON or
+// an ICS calendar, and SCHEDULE_FAMILIES limits the schedule to some release
+// families, e.g. "prices,employment".
+func main() {
+	// Load environment variables from .env file
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Warning: Could not load .env file: %v", err)
+	}
+
+	window := defaultWindow
+	if windowStr := os.Getenv("SCHEDULE_WINDOW"); windowStr != "" {
+		var err error
+		window, err = time.ParseDuration(windowStr)
+		if err != nil {
+			log.Fatalf("Invalid SCHEDULE_WINDOW '%s': %v", windowStr, err)
+		}
+	}
+
+	format := os.Getenv("SCHEDULE_FORMAT")
+	if format == "" {
+		format = "table"
+	}
+	if format != "table" && format != "json" && format != "ics" {
+		log.Fatalf("Invalid SCHEDULE_FORMAT '%s', want table, json or ics", format)
+	}
+
+	client := bls.NewClient()
+	if baseURL := os.Getenv("BLS_BASE_URL"); baseURL != "" {
+		client.BaseURL = baseURL
+	}
+	registry := bls.DefaultRegistry()
+	if path := os.Getenv("BLS_RELEASE_REGISTRY"); path != "" {
+		var err error
+		registry, err = bls.LoadRegistryFile(path)
+		if err != nil {
+			log.Fatalf("Invalid release registry: %v", err)
+		}
+		client.Registry = registry
+	}
+
+	var families []string
+	if familiesStr := os.Getenv("SCHEDULE_FAMILIES"); familiesStr != "" {
+		families = strings.Split(familiesStr, ",")
+	}
+
+	events, err := client.FindUpcomingEvents(context.Background(), window)
+	if err != nil {
+		log.Fatalf("Error getting upcoming events: %v", err)
+	}
+	events = registry.InFamilies(events, families...)
+
+	// Point each event at its release page
+	for i, event := range events {
+		if url, err := client.ReleaseURL(event); err == nil {
+			events[i].URL = url
+		}
+	}
+
+	switch format {
+	case "json":
+		releases := make([]scheduledRelease, len(events))
+		for i, event := range events {
+			releases[i] = scheduledRelease{Summary: event.Summary, Start: *event.Start, End: event.End, URL: event.URL}
+			if release, ok := registry.Lookup(event.Summary); ok {
+				releases[i].Release = release.Summary
+				releases[i].Family = release.Family
+			}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(releases); err != nil {
+			log.Fatalf("Error writing JSON: %v", err)
+		}
+	case "ics":
+		if err := bls.WriteCalendar(os.Stdout, "BLS release schedule", events); err != nil {
+			log.Fatalf("Error writing calendar: %v", err)
+		}
+	default:
+		printTable(registry, events, window)
+	}
+}
+
+// printTable prints the events in release time, which is Eastern time.
+func printTable(registry *bls.Registry, events []bls.Event, window time.Duration) {
+	eastern, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		eastern = time.UTC
+	}
+
+	fmt.Printf("%d releases in the next %s\n\n", len(events), window)
+	if len(events) == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "DATE\tTIME (%s)\tRELEASE\tFAMILY\n", eastern)
+	for _, event := range events {
+		family := "-"
+		if release, ok := registry.Lookup(event.Summary); ok && release.Family != "" {
+			family = release.Family
+		}
+		start := event.Start.In(eastern)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", start.Format("Mon Jan 2"), start.Format("3:04 PM"), event.Summary, family)
+	}
+	w.Flush()
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/gflarity/bls_agent/internal/codec"
+	"github.com/gflarity/bls_agent/internal/workflows/bls"
+	"github.com/joho/godotenv"
+	"go.temporal.io/sdk/client"
+)
+
+// schedulerWorkflowID is fixed so only one scheduler runs per namespace.
+const schedulerWorkflowID = "bls-release-scheduler"
+
+func main() {
+	// Load environment variables from .env file
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Warning: Could not load .env file: %v", err)
+		// Continue execution as environment variables might be set elsewhere
+	}
+
+	// Create scheduler parameters. Secrets are resolved by the worker from the
+	// credential profile, so they are never written into Temporal history.
+	schedulerParams := bls.SchedulerParams{
+		WorkflowParams: bls.WorkflowParams{
+			// OpenAI configuration
+			OpenAIBaseURL: os.Getenv("OPENAI_BASE_URL"),
+			OpenAIModel:   os.Getenv("OPENAI_MODEL"),
+			// Worker-side credential profile
+			CredentialProfile: os.Getenv("CREDENTIAL_PROFILE"),
+
+			// Tweet For Real
+			TweetForReal: os.Getenv("TWEET_FOR_REAL") == "true",
+
+			// Hold drafts for review before posting
+			RequireApproval: os.Getenv("REQUIRE_APPROVAL") == "true",
+		},
+	}
+
+	if timeoutStr := os.Getenv("APPROVAL_TIMEOUT"); timeoutStr != "" {
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil {
+			log.Fatalf("Invalid APPROVAL_TIMEOUT '%s': %v", timeoutStr, err)
+		}
+		schedulerParams.ApprovalTimeout = timeout
+	}
+
+	if intervalStr := os.Getenv("CALENDAR_CHECK_INTERVAL"); intervalStr != "" {
+		interval, err := time.ParseDuration(intervalStr)
+		if err != nil {
+			log.Fatalf("Invalid CALENDAR_CHECK_INTERVAL '%s': %v", intervalStr, err)
+		}
+		schedulerParams.CheckInterval = interval
+	}
+
+	// Encrypt payloads so release text and drafts aren't stored in plain text
+	dataConverter, err := codec.DataConverterFromEnv()
+	if err != nil {
+		log.Fatalln("Unable to create data converter", err)
+	}
+
+	// Create Temporal client
+	c, err := client.Dial(client.Options{
+		HostPort:      os.Getenv("TEMPORAL_HOST_PORT"),
+		Namespace:     os.Getenv("TEMPORAL_NAMESPACE"),
+		DataConverter: dataConverter,
+	})
+	if err != nil {
+		log.Fatalln("Unable to create Temporal client", err)
+	}
+	defer c.Close()
+
+	// The scheduler runs indefinitely, so we start it and return without waiting
+	workflowOptions := client.StartWorkflowOptions{
+		ID:        schedulerWorkflowID,
+		TaskQueue: os.Getenv("TEMPORAL_TASK_QUEUE"),
+	}
+
+	log.Println("Starting BLSReleaseSchedulerWorkflow...")
+	we, err := c.ExecuteWorkflow(context.Background(), workflowOptions, bls.BLSReleaseSchedulerWorkflow, schedulerParams)
+	if err != nil {
+		log.Fatalln("Unable to execute BLSReleaseSchedulerWorkflow", err)
+	}
+
+	log.Printf("Started BLSReleaseSchedulerWorkflow: %s, RunID: %s\n", we.GetID(), we.GetRunID())
+	log.Println("The scheduler will start a summary workflow for each BLS release as it is published")
+}
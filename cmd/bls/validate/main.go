@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gflarity/bls_agent/pkg/bls"
+	"github.com/joho/godotenv"
+)
+
+// requestInterval spaces out the release page checks so bls.gov doesn't block us.
+const requestInterval = time.Second
+
+// validate checks the release registry against bls.gov. It reports calendar
+// events the registry has no release for and releases whose pages don't
+// resolve, and exits non-zero if it finds either.
+func main() {
+	// Load environment variables from .env file
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Warning: Could not load .env file: %v", err)
+	}
+
+	client := bls.NewClient()
+	if baseURL := os.Getenv("BLS_BASE_URL"); baseURL != "" {
+		client.BaseURL = baseURL
+	}
+
+	registry := bls.DefaultRegistry()
+	if path := os.Getenv("BLS_RELEASE_REGISTRY"); path != "" {
+		var err error
+		registry, err = bls.LoadRegistryFile(path)
+		if err != nil {
+			log.Fatalf("Invalid release registry: %v", err)
+		}
+		client.Registry = registry
+	}
+
+	ctx := context.Background()
+	problems := 0
+
+	fmt.Println("Checking calendar events...")
+	events, err := client.GetAllEvents(ctx)
+	if err != nil {
+		log.Fatalf("Error getting all events: %v", err)
+	}
+	unmapped := registry.Unmapped(events)
+	for _, summary := range unmapped {
+		fmt.Printf("  no release for event: %s\n", summary)
+	}
+	fmt.Printf("%d events, %d summaries without a release\n", len(events), len(unmapped))
+	problems += len(unmapped)
+
+	fmt.Println("\nChecking release pages...")
+	releases := registry.Releases()
+	for i, release := range releases {
+		if i > 0 {
+			time.Sleep(requestInterval)
+		}
+		status, err := client.CheckPath(ctx, release.Path)
+		switch {
+		case err != nil:
+			fmt.Printf("  %s: %v\n", release.Summary, err)
+			problems++
+		case status != http.StatusOK:
+			fmt.Printf("  %s: %s returned %d\n", release.Summary, client.PathURL(release.Path), status)
+			problems++
+		}
+	}
+	fmt.Printf("%d releases checked\n", len(releases))
+
+	if problems > 0 {
+		fmt.Printf("\n%d problems found\n", problems)
+		os.Exit(1)
+	}
+	fmt.Println("\nRegistry is valid")
+}
@@ -8,7 +8,12 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/gflarity/bls_agent/internal/codec"
+	"github.com/gflarity/bls_agent/internal/credentials"
+	"github.com/gflarity/bls_agent/internal/ledger"
+	"github.com/gflarity/bls_agent/internal/releases"
 	"github.com/gflarity/bls_agent/internal/workflows/bls"
+	blspkg "github.com/gflarity/bls_agent/pkg/bls"
 
 	"go.temporal.io/sdk/client"
 	temporallog "go.temporal.io/sdk/log"
@@ -27,29 +32,107 @@ func main() {
 	// Create Temporal logger from slog
 	temporalLogger := temporallog.NewStructuredLogger(slogLogger)
 
+	// Encrypt payloads so release text and drafts aren't stored in plain text
+	dataConverter, err := codec.DataConverterFromEnv()
+	if err != nil {
+		panic(fmt.Errorf("Unable to create data converter: %w", err))
+	}
+
 	// Create Temporal client with custom logger
 	c, err := client.Dial(client.Options{
-		HostPort:  os.Getenv("TEMPORAL_HOST_PORT"),
-		Namespace: os.Getenv("TEMPORAL_NAMESPACE"),
-		Logger:    temporalLogger,
+		HostPort:      os.Getenv("TEMPORAL_HOST_PORT"),
+		Namespace:     os.Getenv("TEMPORAL_NAMESPACE"),
+		Logger:        temporalLogger,
+		DataConverter: dataConverter,
 	})
 	if err != nil {
 		panic(fmt.Errorf("Unable to create Temporal client: %w", err))
 	}
 	defer c.Close()
 
+	// Open the publication ledger used to avoid posting a release twice
+	ledgerDir := os.Getenv("PUBLICATION_LEDGER_DIR")
+	if ledgerDir == "" {
+		ledgerDir = "data/ledger" // default
+	}
+	store, err := ledger.NewFileStore(ledgerDir)
+	if err != nil {
+		panic(fmt.Errorf("Unable to open publication ledger: %w", err))
+	}
+	bls.SetPublicationStore(store)
+
+	// Keep every fetched release so the next one can be compared with it
+	releaseDir := os.Getenv("RELEASE_STORE_DIR")
+	if releaseDir == "" {
+		releaseDir = "data/releases" // default
+	}
+	releaseStore, err := releases.NewFileStore(releaseDir)
+	if err != nil {
+		panic(fmt.Errorf("Unable to open release store: %w", err))
+	}
+	bls.SetReleaseStore(releaseStore)
+
+	// Resolve secrets on the worker so they never appear in workflow inputs
+	credentialProvider, err := credentials.FromEnv()
+	if err != nil {
+		panic(fmt.Errorf("Unable to create credential provider: %w", err))
+	}
+	bls.SetCredentialProvider(credentialProvider)
+
+	// Optionally point the activities at a bls.gov stand-in, or load the release
+	// registry from a file instead of using the embedded one
+	blsClient := blspkg.DefaultClient
+	baseURL := os.Getenv("BLS_BASE_URL")
+	registryPath := os.Getenv("BLS_RELEASE_REGISTRY")
+	if baseURL != "" || registryPath != "" {
+		blsClient = blspkg.NewClient()
+		if baseURL != "" {
+			blsClient.BaseURL = baseURL
+		}
+		if registryPath != "" {
+			registry, err := blspkg.LoadRegistryFile(registryPath)
+			if err != nil {
+				panic(fmt.Errorf("Unable to load release registry: %w", err))
+			}
+			blsClient.Registry = registry
+		}
+		bls.SetBLSClient(blsClient)
+	}
+
+	// Keep the last good calendar on disk so bls.gov outages don't stop scheduling
+	calendarDir := os.Getenv("CALENDAR_CACHE_DIR")
+	if calendarDir == "" {
+		calendarDir = "data/calendar" // default
+	}
+	calendar, err := blspkg.NewCalendarCache(blsClient, calendarDir)
+	if err != nil {
+		panic(fmt.Errorf("Unable to open calendar cache: %w", err))
+	}
+	bls.SetCalendarCache(calendar)
+
 	// Create worker
 	w := worker.New(c, os.Getenv("TEMPORAL_TASK_QUEUE"), worker.Options{})
 
 	// Register workflows
 	w.RegisterWorkflow(bls.BLSReleaseSummaryWorkflow)
+	w.RegisterWorkflow(bls.BLSEventSummaryWorkflow)
+	w.RegisterWorkflow(bls.BLSReleaseSchedulerWorkflow)
 
 	// Register activities
 	w.RegisterActivity(bls.FindEventsActivity)
+	w.RegisterActivity(bls.GetAllEventsActivity)
+	w.RegisterActivity(bls.FindScheduledEventsActivity)
 	w.RegisterActivity(bls.FetchReleaseHTMLActivity)
+	w.RegisterActivity(bls.WaitForReleaseActivity)
+	w.RegisterActivity(bls.FetchArchivedReleaseHTMLActivity)
 	w.RegisterActivity(bls.ExtractSummaryActivity)
-	w.RegisterActivity(bls.CompleteWithSchemaActivity)
+	w.RegisterActivity(bls.ExtractHeadlineActivity)
+	w.RegisterActivity(bls.CompareReleaseActivity)
+	w.RegisterActivity(bls.CompleteWithReasoningActivity)
+	w.RegisterActivity(bls.FactCheckTweetActivity)
 	w.RegisterActivity(bls.PostTweetActivity)
+	w.RegisterActivity(bls.GetPublicationActivity)
+	w.RegisterActivity(bls.RecordPublicationActivity)
 
 	// Start worker
 	sigChan := make(chan os.Signal, 1)
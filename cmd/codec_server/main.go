@@ -0,0 +1,84 @@
+package main
+
+import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gflarity/bls_agent/internal/codec"
+	"github.com/joho/godotenv"
+	"go.temporal.io/sdk/converter"
+)
+
+// withCORS lets the Temporal UI call the codec server from the browser.
+func withCORS(origin string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if origin != "" {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type,X-Namespace,Authorization")
+			w.Header().Set("Access-Control-Allow-Methods", "POST,OPTIONS")
+		}
+
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withAuth only lets through requests with an "Authorization: Bearer <token>"
+// header carrying the operator token. The Temporal CLI sends it with
+// --codec-auth "Bearer <token>". The Temporal UI's "Pass access token" option
+// forwards the signed-in user's OIDC token rather than this one, so browsers
+// reach the codec through a proxy that sets the header.
+func withAuth(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func main() {
+	// Load environment variables from .env file
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Warning: Could not load .env file: %v", err)
+		// Continue execution as environment variables might be set elsewhere
+	}
+
+	payloadCodec, err := codec.NewAESGCMCodecFromEnv()
+	if err != nil {
+		log.Fatalln("Unable to create payload codec", err)
+	}
+	if payloadCodec == nil {
+		log.Fatalln("TEMPORAL_CODEC_KEYS environment variable is required")
+	}
+
+	addr := os.Getenv("CODEC_SERVER_ADDR")
+	if addr == "" {
+		addr = ":8081" // default
+	}
+
+	// Only operators with the token may see decoded payloads
+	token := os.Getenv("CODEC_SERVER_TOKEN")
+	if token == "" {
+		log.Fatalln("CODEC_SERVER_TOKEN environment variable is required")
+	}
+
+	var handler http.Handler = converter.NewPayloadCodecHTTPHandler(payloadCodec)
+	handler = withAuth(token, handler)
+	// CORS wraps auth so preflight requests, which carry no token, still succeed
+	handler = withCORS(os.Getenv("CODEC_SERVER_CORS_ORIGIN"), handler)
+
+	log.Printf("Codec server listening on %s", addr)
+	if err := http.ListenAndServe(addr, handler); err != nil {
+		log.Fatalln("Codec server stopped", err)
+	}
+}
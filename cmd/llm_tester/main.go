@@ -64,25 +64,24 @@ func main() {
 	fmt.Printf("Using Base URL: %s\n", baseURL)
 	fmt.Printf("Using Model: %s\n", model)
 
-	cont, reas, err := llm.CompleteWithSchema(
-		context.Background(),
-		apiKey,
-		baseURL,
-		schemaStr, // Using the JSON schema string
-		"You are a helpful assistant that extracts information and returns it in JSON format.",
-		"Extract the following information: Jane Smith is 25 years old, is a student, and is taking 'Computer Science' and 'Physics'.",
-		model,
-	)
+	completer := llm.NewOpenAICompleter(apiKey, baseURL)
+	resp, err := completer.Complete(context.Background(), llm.Request{
+		Schema:       schemaStr, // Using the JSON schema string
+		SystemPrompt: "You are a helpful assistant that extracts information and returns it in JSON format.",
+		UserPrompt:   "Extract the following information: Jane Smith is 25 years old, is a student, and is taking 'Computer Science' and 'Physics'.",
+		Options:      llm.Options{Model: model},
+	})
+	cont, reas := resp.Content, resp.Reasoning
 
 	if err != nil {
-		fmt.Printf("❌ CompleteWithSchema API call failed: %v\n", err)
+		fmt.Printf("❌ Complete API call failed: %v\n", err)
 	} else {
 		// Try to unmarshal the response to validate it matches our schema
 		var person Person
 		if unmarshalErr := json.Unmarshal([]byte(cont), &person); unmarshalErr != nil {
 			fmt.Printf("❌ API call succeeded but response is not valid JSON for Person struct: %v\n", unmarshalErr)
 		} else {
-			fmt.Printf("✅ CompleteWithSchema API call successful!\n")
+			fmt.Printf("✅ Complete API call successful!\n")
 			fmt.Printf("✅ Response successfully unmarshaled into Person struct:\n")
 		}
 		fmt.Printf("Content: %s\n", cont)
@@ -0,0 +1,169 @@
+// Package codec provides an encrypting Temporal payload codec, so release text,
+// drafts and LLM output are stored encrypted in workflow history.
+package codec
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// MetadataEncodingEncrypted marks payloads encrypted by AESGCMCodec.
+	MetadataEncodingEncrypted = "binary/encrypted"
+	// MetadataEncryptionKeyID records which key encrypted a payload, so old
+	// payloads can still be decoded after the current key is rotated.
+	MetadataEncryptionKeyID = "encryption-key-id"
+)
+
+// AESGCMCodec is a converter.PayloadCodec that encrypts payloads with AES-GCM.
+// New payloads are encrypted with the current key, and any known key can decrypt.
+type AESGCMCodec struct {
+	currentKeyID string
+	aeads        map[string]cipher.AEAD
+}
+
+// NewAESGCMCodec returns a codec for the given keys, which must be 16, 24 or 32
+// bytes long. currentKeyID selects the key used for encryption.
+func NewAESGCMCodec(keys map[string][]byte, currentKeyID string) (*AESGCMCodec, error) {
+	if _, ok := keys[currentKeyID]; !ok {
+		return nil, fmt.Errorf("current key %q is not among the configured keys", currentKeyID)
+	}
+
+	aeads := make(map[string]cipher.AEAD, len(keys))
+	for id, key := range keys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GCM for key %q: %w", id, err)
+		}
+		aeads[id] = aead
+	}
+
+	return &AESGCMCodec{currentKeyID: currentKeyID, aeads: aeads}, nil
+}
+
+// Encode implements converter.PayloadCodec.
+func (c *AESGCMCodec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	aead := c.aeads[c.currentKeyID]
+
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, p := range payloads {
+		plaintext, err := proto.Marshal(p)
+		if err != nil {
+			return payloads, fmt.Errorf("failed to marshal payload: %w", err)
+		}
+
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return payloads, fmt.Errorf("failed to generate nonce: %w", err)
+		}
+
+		// The key ID is authenticated so it can't be swapped on a stored payload
+		result[i] = &commonpb.Payload{
+			Metadata: map[string][]byte{
+				converter.MetadataEncoding: []byte(MetadataEncodingEncrypted),
+				MetadataEncryptionKeyID:    []byte(c.currentKeyID),
+			},
+			Data: aead.Seal(nonce, nonce, plaintext, []byte(c.currentKeyID)),
+		}
+	}
+	return result, nil
+}
+
+// Decode implements converter.PayloadCodec. Payloads that aren't encrypted are
+// passed through, so history written before encryption was enabled still decodes.
+func (c *AESGCMCodec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, p := range payloads {
+		if string(p.Metadata[converter.MetadataEncoding]) != MetadataEncodingEncrypted {
+			result[i] = p
+			continue
+		}
+
+		keyID := string(p.Metadata[MetadataEncryptionKeyID])
+		aead, ok := c.aeads[keyID]
+		if !ok {
+			return payloads, fmt.Errorf("unknown encryption key %q", keyID)
+		}
+
+		if len(p.Data) < aead.NonceSize() {
+			return payloads, fmt.Errorf("encrypted payload is too short")
+		}
+		nonce, ciphertext := p.Data[:aead.NonceSize()], p.Data[aead.NonceSize():]
+
+		plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(keyID))
+		if err != nil {
+			return payloads, fmt.Errorf("failed to decrypt payload with key %q: %w", keyID, err)
+		}
+
+		result[i] = &commonpb.Payload{}
+		if err := proto.Unmarshal(plaintext, result[i]); err != nil {
+			return payloads, fmt.Errorf("failed to unmarshal decrypted payload: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// NewAESGCMCodecFromEnv builds a codec from TEMPORAL_CODEC_KEYS, a comma
+// separated list of id:base64-key pairs, and TEMPORAL_CODEC_KEY_ID, the key used
+// for encryption (defaults to the first listed key). To rotate, put the new key
+// first and keep the old ones listed until their payloads have aged out.
+// It returns nil if TEMPORAL_CODEC_KEYS isn't set.
+func NewAESGCMCodecFromEnv() (*AESGCMCodec, error) {
+	spec := os.Getenv("TEMPORAL_CODEC_KEYS")
+	if spec == "" {
+		return nil, nil
+	}
+
+	keys := make(map[string][]byte)
+	var firstID string
+	for _, entry := range strings.Split(spec, ",") {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("invalid TEMPORAL_CODEC_KEYS entry, expected id:base64-key")
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 for key %q: %w", id, err)
+		}
+		keys[id] = key
+		if firstID == "" {
+			firstID = id
+		}
+	}
+
+	currentKeyID := os.Getenv("TEMPORAL_CODEC_KEY_ID")
+	if currentKeyID == "" {
+		currentKeyID = firstID
+	}
+	return NewAESGCMCodec(keys, currentKeyID)
+}
+
+// DataConverterFromEnv returns the default data converter wrapped with the
+// codec from NewAESGCMCodecFromEnv. Without configured keys it logs a warning
+// and returns the default converter, so payloads are stored unencrypted.
+func DataConverterFromEnv() (converter.DataConverter, error) {
+	codec, err := NewAESGCMCodecFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if codec == nil {
+		log.Println("Warning: TEMPORAL_CODEC_KEYS is not set, Temporal payloads will not be encrypted")
+		return converter.GetDefaultDataConverter(), nil
+	}
+	return converter.NewCodecDataConverter(converter.GetDefaultDataConverter(), codec), nil
+}
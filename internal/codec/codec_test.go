@@ -0,0 +1,109 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+)
+
+func TestAESGCMCodec(t *testing.T) {
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	newKey := bytes.Repeat([]byte{2}, 32)
+
+	oldCodec, err := NewAESGCMCodec(map[string][]byte{"k1": oldKey}, "k1")
+	if err != nil {
+		t.Fatalf("NewAESGCMCodec() returned an error: %v", err)
+	}
+	rotated, err := NewAESGCMCodec(map[string][]byte{"k1": oldKey, "k2": newKey}, "k2")
+	if err != nil {
+		t.Fatalf("NewAESGCMCodec() returned an error: %v", err)
+	}
+
+	payload, err := converter.GetDefaultDataConverter().ToPayload("CPI rose 0.4% in December")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payloads := []*commonpb.Payload{payload}
+
+	t.Run("Round trip after rotation", func(t *testing.T) {
+		encoded, err := oldCodec.Encode(payloads)
+		if err != nil {
+			t.Fatalf("Encode() returned an error: %v", err)
+		}
+		if bytes.Contains(encoded[0].Data, []byte("CPI rose")) {
+			t.Error("Encoded payload contains the plaintext")
+		}
+		if got := string(encoded[0].Metadata[MetadataEncryptionKeyID]); got != "k1" {
+			t.Errorf("Expected key id k1, got %q", got)
+		}
+
+		decoded, err := rotated.Decode(encoded)
+		if err != nil {
+			t.Fatalf("Decode() returned an error: %v", err)
+		}
+		var got string
+		if err := converter.GetDefaultDataConverter().FromPayload(decoded[0], &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != "CPI rose 0.4% in December" {
+			t.Errorf("Decoded %q, want the original text", got)
+		}
+	})
+
+	t.Run("Unknown key", func(t *testing.T) {
+		encoded, err := rotated.Encode(payloads)
+		if err != nil {
+			t.Fatalf("Encode() returned an error: %v", err)
+		}
+		if _, err := oldCodec.Decode(encoded); err == nil {
+			t.Error("Expected an error decoding with a codec that lacks the key")
+		}
+	})
+
+	t.Run("Unencrypted payloads pass through", func(t *testing.T) {
+		decoded, err := oldCodec.Decode(payloads)
+		if err != nil {
+			t.Fatalf("Decode() returned an error: %v", err)
+		}
+		if decoded[0] != payloads[0] {
+			t.Error("Expected the unencrypted payload to be returned as is")
+		}
+	})
+
+	t.Run("Tampered key id", func(t *testing.T) {
+		encoded, err := rotated.Encode(payloads)
+		if err != nil {
+			t.Fatalf("Encode() returned an error: %v", err)
+		}
+		encoded[0].Metadata[MetadataEncryptionKeyID] = []byte("k1")
+		if _, err := rotated.Decode(encoded); err == nil {
+			t.Error("Expected an error when the key id was changed")
+		}
+	})
+}
+
+func TestNewAESGCMCodecFromEnv(t *testing.T) {
+	t.Setenv("TEMPORAL_CODEC_KEYS", "")
+	codec, err := NewAESGCMCodecFromEnv()
+	if err != nil || codec != nil {
+		t.Fatalf("Expected no codec without keys, got %v, %v", codec, err)
+	}
+
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{3}, 32))
+	t.Setenv("TEMPORAL_CODEC_KEYS", "new:"+key+", old:"+key)
+	codec, err = NewAESGCMCodecFromEnv()
+	if err != nil {
+		t.Fatalf("NewAESGCMCodecFromEnv() returned an error: %v", err)
+	}
+	if codec.currentKeyID != "new" {
+		t.Errorf("Expected the first key to be current, got %q", codec.currentKeyID)
+	}
+
+	t.Setenv("TEMPORAL_CODEC_KEYS", "broken")
+	if _, err := NewAESGCMCodecFromEnv(); err == nil {
+		t.Error("Expected an error for a malformed key list")
+	}
+}
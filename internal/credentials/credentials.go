@@ -0,0 +1,190 @@
+// Package credentials resolves secrets on the worker, so workflows only need to
+// carry the name of a credential profile instead of the secrets themselves.
+package credentials
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DefaultProfile is used when a workflow doesn't name a profile.
+const DefaultProfile = "default"
+
+// Secret names understood by the helpers below. They match the environment
+// variables the project has always used.
+const (
+	OpenAIAPIKey        = "OPENAI_API_KEY"
+	TwitterAPIKey       = "X_API_KEY"
+	TwitterAPISecret    = "X_API_SECRET"
+	TwitterAccessToken  = "X_ACCESS_TOKEN"
+	TwitterAccessSecret = "X_ACCESS_TOKEN_SECRET"
+)
+
+// ErrNotFound is returned when a provider has no value for a secret.
+var ErrNotFound = errors.New("credential not found")
+
+// Provider resolves a named secret for a credential profile.
+type Provider interface {
+	Lookup(profile, name string) (string, error)
+}
+
+// OpenAI holds the credentials for an OpenAI-compatible API.
+type OpenAI struct {
+	APIKey string
+}
+
+// Twitter holds the OAuth1 credentials for the X/Twitter API.
+type Twitter struct {
+	APIKey       string
+	APISecret    string
+	AccessToken  string
+	AccessSecret string
+}
+
+// LoadOpenAI resolves the OpenAI credentials for a profile.
+func LoadOpenAI(p Provider, profile string) (OpenAI, error) {
+	apiKey, err := p.Lookup(profile, OpenAIAPIKey)
+	if err != nil {
+		return OpenAI{}, err
+	}
+	return OpenAI{APIKey: apiKey}, nil
+}
+
+// LoadTwitter resolves the X/Twitter credentials for a profile.
+func LoadTwitter(p Provider, profile string) (Twitter, error) {
+	var creds Twitter
+	fields := []struct {
+		name string
+		dst  *string
+	}{
+		{TwitterAPIKey, &creds.APIKey},
+		{TwitterAPISecret, &creds.APISecret},
+		{TwitterAccessToken, &creds.AccessToken},
+		{TwitterAccessSecret, &creds.AccessSecret},
+	}
+	for _, f := range fields {
+		value, err := p.Lookup(profile, f.name)
+		if err != nil {
+			return Twitter{}, err
+		}
+		*f.dst = value
+	}
+	return creds, nil
+}
+
+// FromEnv builds the provider selected by CREDENTIALS_PROVIDER ("env", "file"
+// or "dir"). The file and dir providers read their location from CREDENTIALS_PATH.
+func FromEnv() (Provider, error) {
+	kind := os.Getenv("CREDENTIALS_PROVIDER")
+	path := os.Getenv("CREDENTIALS_PATH")
+
+	switch kind {
+	case "", "env":
+		return EnvProvider{}, nil
+	case "file":
+		if path == "" {
+			return nil, fmt.Errorf("CREDENTIALS_PATH is required for the file credential provider")
+		}
+		return NewFileProvider(path)
+	case "dir":
+		if path == "" {
+			return nil, fmt.Errorf("CREDENTIALS_PATH is required for the dir credential provider")
+		}
+		return DirProvider{Dir: path}, nil
+	default:
+		return nil, fmt.Errorf("unknown credential provider: %s", kind)
+	}
+}
+
+// normalizeProfile maps the empty profile to DefaultProfile.
+func normalizeProfile(profile string) string {
+	if profile == "" {
+		return DefaultProfile
+	}
+	return profile
+}
+
+// envUnsafeChars matches characters that can't appear in an environment variable name.
+var envUnsafeChars = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// EnvProvider reads secrets from environment variables. The default profile
+// uses the plain name (OPENAI_API_KEY), other profiles are prefixed with the
+// upper-cased profile name (STAGING_OPENAI_API_KEY).
+type EnvProvider struct{}
+
+// Lookup implements Provider.
+func (EnvProvider) Lookup(profile, name string) (string, error) {
+	key := name
+	if profile = normalizeProfile(profile); profile != DefaultProfile {
+		key = envUnsafeChars.ReplaceAllString(strings.ToUpper(profile), "_") + "_" + name
+	}
+
+	value := os.Getenv(key)
+	if value == "" {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return value, nil
+}
+
+// FileProvider reads secrets from a JSON file mapping profiles to secrets, e.g.
+// {"default": {"OPENAI_API_KEY": "..."}}.
+type FileProvider struct {
+	profiles map[string]map[string]string
+}
+
+// NewFileProvider loads the credentials file at path.
+func NewFileProvider(path string) (*FileProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	var profiles map[string]map[string]string
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file: %w", err)
+	}
+	return &FileProvider{profiles: profiles}, nil
+}
+
+// Lookup implements Provider.
+func (p *FileProvider) Lookup(profile, name string) (string, error) {
+	profile = normalizeProfile(profile)
+	value := p.profiles[profile][name]
+	if value == "" {
+		return "", fmt.Errorf("%w: %s/%s", ErrNotFound, profile, name)
+	}
+	return value, nil
+}
+
+// DirProvider reads secrets from a mounted secrets directory laid out as
+// <Dir>/<profile>/<name>, one secret per file.
+type DirProvider struct {
+	Dir string
+}
+
+// Lookup implements Provider.
+func (p DirProvider) Lookup(profile, name string) (string, error) {
+	profile = normalizeProfile(profile)
+	if strings.ContainsAny(profile, `/\`) || profile == ".." {
+		return "", fmt.Errorf("invalid credential profile: %s", profile)
+	}
+
+	data, err := os.ReadFile(filepath.Join(p.Dir, profile, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: %s/%s", ErrNotFound, profile, name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read credential %s/%s: %w", profile, name, err)
+	}
+
+	value := strings.TrimSpace(string(data))
+	if value == "" {
+		return "", fmt.Errorf("%w: %s/%s", ErrNotFound, profile, name)
+	}
+	return value, nil
+}
@@ -0,0 +1,74 @@
+package credentials
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProviders(t *testing.T) {
+	t.Setenv(OpenAIAPIKey, "env-default-key")
+	t.Setenv("STAGING_"+OpenAIAPIKey, "env-staging-key")
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "credentials.json")
+	err := os.WriteFile(file, []byte(`{"default": {"OPENAI_API_KEY": "file-default-key"}, "staging": {"OPENAI_API_KEY": "file-staging-key"}}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileProvider, err := NewFileProvider(file)
+	if err != nil {
+		t.Fatalf("NewFileProvider() returned an error: %v", err)
+	}
+
+	secrets := filepath.Join(dir, "secrets")
+	for profile, key := range map[string]string{"default": "dir-default-key", "staging": "dir-staging-key"} {
+		if err := os.MkdirAll(filepath.Join(secrets, profile), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(secrets, profile, OpenAIAPIKey), []byte(key+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		provider Provider
+		profile  string
+		want     string
+	}{
+		{name: "Env - empty profile", provider: EnvProvider{}, profile: "", want: "env-default-key"},
+		{name: "Env - named profile", provider: EnvProvider{}, profile: "staging", want: "env-staging-key"},
+		{name: "File - default profile", provider: fileProvider, profile: "default", want: "file-default-key"},
+		{name: "File - named profile", provider: fileProvider, profile: "staging", want: "file-staging-key"},
+		{name: "Dir - empty profile", provider: DirProvider{Dir: secrets}, profile: "", want: "dir-default-key"},
+		{name: "Dir - named profile", provider: DirProvider{Dir: secrets}, profile: "staging", want: "dir-staging-key"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := LoadOpenAI(tc.provider, tc.profile)
+			if err != nil {
+				t.Fatalf("LoadOpenAI() returned an error: %v", err)
+			}
+			if got.APIKey != tc.want {
+				t.Errorf("LoadOpenAI() = %q, want %q", got.APIKey, tc.want)
+			}
+		})
+	}
+
+	t.Run("Missing secrets", func(t *testing.T) {
+		for _, provider := range []Provider{EnvProvider{}, fileProvider, DirProvider{Dir: secrets}} {
+			if _, err := LoadTwitter(provider, "missing"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Expected ErrNotFound from %T, got %v", provider, err)
+			}
+		}
+	})
+
+	t.Run("Dir - rejects path traversal", func(t *testing.T) {
+		if _, err := (DirProvider{Dir: secrets}).Lookup("../secrets/default", OpenAIAPIKey); err == nil {
+			t.Error("Expected an error for a profile containing a path separator")
+		}
+	})
+}
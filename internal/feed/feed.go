@@ -0,0 +1,125 @@
+// Package feed serves an iCalendar feed of the releases the bot covers, so the
+// team can subscribe to the schedule and follow each release to its post.
+package feed
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gflarity/bls_agent/internal/ledger"
+	"github.com/gflarity/bls_agent/pkg/bls"
+)
+
+// DefaultName is the calendar title shown by calendar apps.
+const DefaultName = "BLS releases"
+
+// PostURL returns the link to a published tweet.
+func PostURL(tweetID string) string {
+	return "https://x.com/i/web/status/" + tweetID
+}
+
+// Feed builds the calendar of covered releases. Client is required, the other
+// fields are optional.
+type Feed struct {
+	// Client fetches the release calendar and resolves release URLs.
+	Client *bls.Client
+	// Calendar, when set, is read instead of fetching the calendar through
+	// Client on every request.
+	Calendar *bls.CalendarCache
+	// Publications links published releases to their posts.
+	Publications ledger.Store
+	// Families limits the feed to some release families.
+	Families []string
+	// Name is the calendar title. Empty means DefaultName.
+	Name string
+}
+
+// Events returns the covered events, those with a release in the registry,
+// with their release URL and, once published, a link to the post.
+func (f *Feed) Events(ctx context.Context) ([]bls.Event, error) {
+	var events []bls.Event
+	if f.Calendar != nil {
+		snapshot, err := f.Calendar.Fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		events = snapshot.Events
+	} else {
+		var err error
+		events, err = f.Client.GetAllEvents(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var covered []bls.Event
+	for _, event := range events {
+		url, err := f.Client.ReleaseURL(event)
+		if err != nil {
+			continue
+		}
+		event.URL = url
+
+		// Calendar apps rarely show the URL property, so the links go in the
+		// description too
+		lines := []string{"News release: " + url}
+		if f.Publications != nil {
+			record, err := f.Publications.Get(event.Key())
+			if err != nil {
+				return nil, fmt.Errorf("failed to check publication ledger: %w", err)
+			}
+			if record != nil && record.TweetID != "" {
+				lines = append(lines, "Our post: "+PostURL(record.TweetID))
+			}
+		}
+		if event.Description != "" {
+			lines = append([]string{event.Description, ""}, lines...)
+		}
+		event.Description = strings.Join(lines, "\n")
+
+		covered = append(covered, event)
+	}
+	return registry(f.Client).InFamilies(covered, f.Families...), nil
+}
+
+// ServeHTTP writes the feed as an iCalendar file.
+func (f *Feed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	events, err := f.Events(r.Context())
+	if err != nil {
+		log.Printf("Failed to build release feed: %v", err)
+		http.Error(w, "calendar unavailable", http.StatusBadGateway)
+		return
+	}
+
+	name := f.Name
+	if name == "" {
+		name = DefaultName
+	}
+	var buf bytes.Buffer
+	if err := bls.WriteCalendar(&buf, name, events); err != nil {
+		log.Printf("Failed to write release feed: %v", err)
+		http.Error(w, "failed to write calendar", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+// registry returns the registry the client looks releases up in.
+func registry(client *bls.Client) *bls.Registry {
+	if client.Registry != nil {
+		return client.Registry
+	}
+	return bls.DefaultRegistry()
+}
@@ -0,0 +1,99 @@
+package feed
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gflarity/bls_agent/internal/ledger"
+	"github.com/gflarity/bls_agent/pkg/bls"
+)
+
+func TestFeed(t *testing.T) {
+	at := func(day int) *time.Time {
+		t := time.Date(2025, 1, day, 13, 30, 0, 0, time.UTC)
+		return &t
+	}
+	calendar := []bls.Event{
+		{Summary: "Employment Situation", UID: "empsit@bls.gov", Start: at(10)},
+		{Summary: "Consumer Price Index", UID: "cpi@bls.gov", Start: at(15)},
+		{Summary: "County Business Patterns", UID: "cbp@bls.gov", Start: at(16)},
+	}
+	blsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bls.WriteCalendar(w, "", calendar)
+	}))
+	defer blsServer.Close()
+
+	client := bls.NewClient()
+	client.HTTPClient = blsServer.Client()
+	client.BaseURL = blsServer.URL
+
+	publications := ledger.NewMemoryStore()
+	publications.Put(ledger.Record{Key: calendar[0].Key(), TweetID: "1234"})
+
+	testCases := []struct {
+		name     string
+		families []string
+		want     []string
+	}{
+		{"Covered releases", nil, []string{"Employment Situation", "Consumer Price Index"}},
+		{"Family filter", []string{"prices"}, []string{"Consumer Price Index"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(&Feed{Client: client, Publications: publications, Families: tc.families})
+			defer server.Close()
+
+			resp, err := http.Get(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/calendar") {
+				t.Fatalf("Got %s with content type %q", resp.Status, resp.Header.Get("Content-Type"))
+			}
+
+			events, err := bls.ParseCalendar(resp.Body)
+			if err != nil {
+				t.Fatalf("Feed isn't a valid calendar: %v", err)
+			}
+			var got []string
+			for _, event := range events {
+				got = append(got, event.Summary)
+			}
+			if strings.Join(got, "|") != strings.Join(tc.want, "|") {
+				t.Fatalf("Feed has %v, want %v", got, tc.want)
+			}
+
+			for _, event := range events {
+				if !strings.HasPrefix(event.URL, blsServer.URL+"/news.release/") {
+					t.Errorf("%s URL = %q, want its release page", event.Summary, event.URL)
+				}
+				published := strings.Contains(event.Description, "Our post: "+PostURL("1234"))
+				if published != (event.Summary == "Employment Situation") {
+					t.Errorf("%s description = %q", event.Summary, event.Description)
+				}
+			}
+		})
+	}
+}
+
+func TestFeedCalendarUnavailable(t *testing.T) {
+	blsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Access Denied", http.StatusForbidden)
+	}))
+	defer blsServer.Close()
+
+	client := bls.NewClient()
+	client.HTTPClient = blsServer.Client()
+	client.BaseURL = blsServer.URL
+
+	recorder := httptest.NewRecorder()
+	(&Feed{Client: client}).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/bls.ics", nil))
+	if recorder.Code != http.StatusBadGateway {
+		t.Errorf("Got status %d, want %d", recorder.Code, http.StatusBadGateway)
+	}
+}
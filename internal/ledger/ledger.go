@@ -0,0 +1,142 @@
+// Package ledger keeps a durable record of what has already been published, so a
+// release is never posted twice no matter how its workflow was triggered.
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// ErrAlreadyRecorded is returned by Put when a record with the same key exists.
+var ErrAlreadyRecorded = errors.New("publication already recorded")
+
+// Record describes a single published post.
+type Record struct {
+	Key         string    `json:"key"`
+	Summary     string    `json:"summary"`
+	Start       time.Time `json:"start"`
+	TweetID     string    `json:"tweet_id"`
+	Text        string    `json:"text"`
+	WorkflowID  string    `json:"workflow_id"`
+	PublishedAt time.Time `json:"published_at"`
+	// Reasoning is the model's reasoning for the post, kept when the workflow
+	// was asked to.
+	Reasoning string `json:"reasoning,omitempty"`
+}
+
+// Store persists publication records keyed by Record.Key.
+type Store interface {
+	// Get returns the record for key, or nil if nothing was published under it.
+	Get(key string) (*Record, error)
+	// Put stores a new record. It returns ErrAlreadyRecorded if the key is taken.
+	Put(record Record) error
+}
+
+// unsafeChars matches anything we don't want in a file name.
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// FileStore is a Store that keeps one JSON file per record in a directory.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a FileStore rooted at dir, creating it if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create ledger directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) path(key string) string {
+	return filepath.Join(s.dir, unsafeChars.ReplaceAllString(key, "_")+".json")
+}
+
+// Get implements Store.
+func (s *FileStore) Get(key string) (*Record, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ledger record: %w", err)
+	}
+
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to parse ledger record: %w", err)
+	}
+	return &record, nil
+}
+
+// Put implements Store. The record is written to a temporary file and then
+// linked into place, so concurrent writers can't both succeed.
+func (s *FileStore) Put(record Record) error {
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal ledger record: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".record-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary ledger file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write ledger record: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write ledger record: %w", err)
+	}
+
+	if err := os.Link(tmp.Name(), s.path(record.Key)); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return ErrAlreadyRecorded
+		}
+		return fmt.Errorf("failed to store ledger record: %w", err)
+	}
+	return nil
+}
+
+// MemoryStore is an in-memory Store, mostly useful for tests.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record)}
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(key string) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[key]
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+// Put implements Store.
+func (s *MemoryStore) Put(record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.records[record.Key]; ok {
+		return ErrAlreadyRecorded
+	}
+	s.records[record.Key] = record
+	return nil
+}
@@ -0,0 +1,56 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStores(t *testing.T) {
+	fileStore, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore() returned an error: %v", err)
+	}
+
+	stores := map[string]Store{
+		"FileStore":   fileStore,
+		"MemoryStore": NewMemoryStore(),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			key := "20250115T083000-cpi@bls.gov-20250115T133000Z"
+
+			got, err := store.Get(key)
+			if err != nil {
+				t.Fatalf("Get() returned an error: %v", err)
+			}
+			if got != nil {
+				t.Fatalf("Expected no record before Put, got %+v", got)
+			}
+
+			record := Record{
+				Key:         key,
+				Summary:     "Consumer Price Index",
+				TweetID:     "12345",
+				Text:        "CPI rose 0.4% in December.",
+				PublishedAt: time.Date(2025, 1, 15, 13, 35, 0, 0, time.UTC),
+			}
+			if err := store.Put(record); err != nil {
+				t.Fatalf("Put() returned an error: %v", err)
+			}
+
+			got, err = store.Get(key)
+			if err != nil {
+				t.Fatalf("Get() returned an error: %v", err)
+			}
+			if got == nil || got.TweetID != record.TweetID || !got.PublishedAt.Equal(record.PublishedAt) {
+				t.Errorf("Get() = %+v, want %+v", got, record)
+			}
+
+			if err := store.Put(record); !errors.Is(err, ErrAlreadyRecorded) {
+				t.Errorf("Expected ErrAlreadyRecorded on duplicate Put, got %v", err)
+			}
+		})
+	}
+}
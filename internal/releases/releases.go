@@ -0,0 +1,155 @@
+// Package releases keeps the figures parsed from every fetched news release, so
+// a release can be compared with the previous one of the same series.
+package releases
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gflarity/bls_agent/pkg/bls"
+)
+
+// Store persists release snapshots, grouped by release and keyed by
+// ReleaseSnapshot.Key.
+type Store interface {
+	// Put stores a snapshot, replacing any earlier snapshot with the same key.
+	Put(snapshot bls.ReleaseSnapshot) error
+	// Previous returns the latest snapshot of release that started before
+	// before, or nil if there is none.
+	Previous(release string, before time.Time) (*bls.ReleaseSnapshot, error)
+}
+
+// unsafeChars matches anything we don't want in a file name.
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// FileStore is a Store that keeps one JSON file per snapshot, in a directory
+// per release.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a FileStore rooted at dir, creating it if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create release store directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) releaseDir(release string) string {
+	return filepath.Join(s.dir, unsafeChars.ReplaceAllString(strings.ToLower(release), "_"))
+}
+
+// Put implements Store. The snapshot is written to a temporary file and then
+// renamed into place, so readers never see a partial file.
+func (s *FileStore) Put(snapshot bls.ReleaseSnapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal release snapshot: %w", err)
+	}
+
+	dir := s.releaseDir(snapshot.Release)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create release directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".snapshot-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary release file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write release snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write release snapshot: %w", err)
+	}
+
+	path := filepath.Join(dir, unsafeChars.ReplaceAllString(snapshot.Key, "_")+".json")
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store release snapshot: %w", err)
+	}
+	return nil
+}
+
+// Previous implements Store.
+func (s *FileStore) Previous(release string, before time.Time) (*bls.ReleaseSnapshot, error) {
+	paths, err := filepath.Glob(filepath.Join(s.releaseDir(release), "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list release snapshots: %w", err)
+	}
+
+	var snapshots []bls.ReleaseSnapshot
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read release snapshot: %w", err)
+		}
+
+		var snapshot bls.ReleaseSnapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return nil, fmt.Errorf("failed to parse release snapshot %s: %w", filepath.Base(path), err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return latestBefore(snapshots, release, before), nil
+}
+
+// MemoryStore is an in-memory Store, mostly useful for tests.
+type MemoryStore struct {
+	mu        sync.Mutex
+	snapshots map[string]bls.ReleaseSnapshot
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{snapshots: make(map[string]bls.ReleaseSnapshot)}
+}
+
+// Put implements Store.
+func (s *MemoryStore) Put(snapshot bls.ReleaseSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.snapshots[snapshot.Key] = snapshot
+	return nil
+}
+
+// Previous implements Store.
+func (s *MemoryStore) Previous(release string, before time.Time) (*bls.ReleaseSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshots := make([]bls.ReleaseSnapshot, 0, len(s.snapshots))
+	for _, snapshot := range s.snapshots {
+		snapshots = append(snapshots, snapshot)
+	}
+	return latestBefore(snapshots, release, before), nil
+}
+
+// latestBefore returns the snapshot of release with the latest start before
+// before.
+func latestBefore(snapshots []bls.ReleaseSnapshot, release string, before time.Time) *bls.ReleaseSnapshot {
+	var latest *bls.ReleaseSnapshot
+	for i, snapshot := range snapshots {
+		if snapshot.Release != release || !snapshot.Start.Before(before) {
+			continue
+		}
+		if latest == nil || snapshot.Start.After(latest.Start) {
+			latest = &snapshots[i]
+		}
+	}
+	return latest
+}
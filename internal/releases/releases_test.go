@@ -0,0 +1,77 @@
+package releases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gflarity/bls_agent/pkg/bls"
+)
+
+func TestStores(t *testing.T) {
+	fileStore, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore() returned an error: %v", err)
+	}
+
+	stores := map[string]Store{
+		"FileStore":   fileStore,
+		"MemoryStore": NewMemoryStore(),
+	}
+
+	november := time.Date(2024, 12, 11, 13, 30, 0, 0, time.UTC)
+	december := time.Date(2025, 1, 15, 13, 30, 0, 0, time.UTC)
+	january := time.Date(2025, 2, 12, 13, 30, 0, 0, time.UTC)
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			got, err := store.Previous("Consumer Price Index", january)
+			if err != nil {
+				t.Fatalf("Previous() returned an error: %v", err)
+			}
+			if got != nil {
+				t.Fatalf("Expected no snapshot before Put, got %+v", got)
+			}
+
+			snapshots := []bls.ReleaseSnapshot{
+				{Key: "cpi-2024-12", Release: "Consumer Price Index", Start: november},
+				{Key: "cpi-2025-01", Release: "Consumer Price Index", Start: december, Metrics: []bls.Metric{{Name: "CPI all items, m/m", Value: 0.4}}},
+				{Key: "ppi-2025-01", Release: "Producer Price Index", Start: december},
+			}
+			for _, snapshot := range snapshots {
+				if err := store.Put(snapshot); err != nil {
+					t.Fatalf("Put() returned an error: %v", err)
+				}
+			}
+
+			got, err = store.Previous("Consumer Price Index", january)
+			if err != nil {
+				t.Fatalf("Previous() returned an error: %v", err)
+			}
+			if got == nil || got.Key != "cpi-2025-01" || len(got.Metrics) != 1 {
+				t.Errorf("Previous() = %+v, want the December release", got)
+			}
+
+			// A release isn't its own previous release
+			got, err = store.Previous("Consumer Price Index", december)
+			if err != nil {
+				t.Fatalf("Previous() returned an error: %v", err)
+			}
+			if got == nil || got.Key != "cpi-2024-12" {
+				t.Errorf("Previous() = %+v, want the November release", got)
+			}
+
+			// Fetching a release again replaces its snapshot
+			snapshots[1].Metrics[0].Value = 0.5
+			if err := store.Put(snapshots[1]); err != nil {
+				t.Fatalf("Put() returned an error on overwrite: %v", err)
+			}
+			got, err = store.Previous("Consumer Price Index", january)
+			if err != nil {
+				t.Fatalf("Previous() returned an error: %v", err)
+			}
+			if got == nil || got.Metrics[0].Value != 0.5 {
+				t.Errorf("Previous() = %+v, want the replaced snapshot", got)
+			}
+		})
+	}
+}
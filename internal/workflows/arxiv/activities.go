@@ -5,11 +5,43 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/gflarity/bls_agent/internal/credentials"
+	"github.com/gflarity/bls_agent/internal/workflows/completion"
 	"github.com/gflarity/bls_agent/pkg/arxiv"
 	"github.com/gflarity/bls_agent/pkg/llm"
 	"go.temporal.io/sdk/activity"
 )
 
+// credentialProvider resolves secrets on the worker. It defaults to reading the
+// environment and can be replaced with SetCredentialProvider.
+var credentialProvider credentials.Provider = credentials.EnvProvider{}
+
+// SetCredentialProvider sets the provider activities use to resolve credentials.
+func SetCredentialProvider(p credentials.Provider) {
+	credentialProvider = p
+}
+
+// completer answers CompleteWithReasoningActivity. When it isn't set an
+// OpenAI-compatible API is called with the workflow's credential profile and
+// base URL.
+var completer llm.Completer
+
+// SetCompleter sets the completer the completion activity uses in place of the
+// OpenAI-compatible API, e.g. an llm.FakeCompleter in tests.
+func SetCompleter(c llm.Completer) {
+	completer = c
+}
+
+// Application error types CompleteWithReasoningActivity fails with, so workflows
+// can tell a model that gave no answer or kept breaking the schema from an API
+// that couldn't be reached or credentials that couldn't be loaded.
+const (
+	CompletionTransportErrorType   = completion.TransportErrorType
+	CompletionRefusedErrorType     = completion.RefusedErrorType
+	CompletionInvalidErrorType     = completion.InvalidErrorType
+	CompletionCredentialsErrorType = completion.CredentialsErrorType
+)
+
 // GetArxivIdsForDateActivity scrapes the Arxiv "recent" page to find all paper IDs
 // published on a specific target date for the cs.AI category.
 func GetArxivIdsForDateActivity(ctx context.Context, targetDate time.Time) ([]string, error) {
@@ -95,47 +127,54 @@ func ExtractPaperTextActivity(ctx context.Context, arxivId string) (string, erro
 	return text, nil
 }
 
-// CompleteWithSchemaActivity performs LLM completion with a specified JSON schema
-func CompleteWithSchemaActivity(
+// CompleteWithReasoningActivity performs LLM completion with a specified JSON
+// schema. The API key is resolved on the worker from the named credential
+// profile. The response carries the model's reasoning when the provider returns
+// it.
+func CompleteWithReasoningActivity(
 	ctx context.Context,
-	apiKey string,
+	credentialProfile string,
 	baseURL string,
 	schema string,
 	systemPrompt string,
 	userPrompt string,
 	model string,
-) (string, error) {
+) (llm.Response, error) {
 	// Get activity info
 	activityInfo := activity.GetInfo(ctx)
 	workflowID := activityInfo.WorkflowExecution.ID
 	runID := activityInfo.WorkflowExecution.RunID
 
 	// Log activity execution
-	activity.GetLogger(ctx).Info("Executing CompleteWithSchemaActivity",
+	activity.GetLogger(ctx).Info("Executing CompleteWithReasoningActivity",
 		"workflowID", workflowID,
 		"runID", runID,
 		"model", model,
-		"baseURL", baseURL)
-
-	// Call the LLM package function
-	content, reasoning, err := llm.CompleteWithSchema(
-		ctx,
-		apiKey,
-		baseURL,
-		schema,
-		systemPrompt,
-		userPrompt,
-		model,
-	)
+		"baseURL", baseURL,
+		"credentialProfile", credentialProfile)
+
+	c, err := completion.For(completer, credentialProvider, credentialProfile, baseURL)
+	if err != nil {
+		activity.GetLogger(ctx).Error("CompleteWithReasoningActivity failed to load credentials", "error", err)
+		return llm.Response{}, err
+	}
+
+	// Call the completer
+	resp, err := c.Complete(ctx, llm.Request{
+		Schema:       schema,
+		SystemPrompt: systemPrompt,
+		UserPrompt:   userPrompt,
+		Options:      llm.Options{Model: model},
+	})
 	if err != nil {
-		activity.GetLogger(ctx).Error("CompleteWithSchemaActivity failed", "error", err)
-		return "", fmt.Errorf("failed to complete with schema: %w", err)
+		activity.GetLogger(ctx).Error("CompleteWithReasoningActivity failed", "error", err)
+		return llm.Response{}, completion.Error(err)
 	}
 
 	// Log the results
-	activity.GetLogger(ctx).Info("CompleteWithSchemaActivity completed successfully",
-		"contentLength", len(content),
-		"reasoningLength", len(reasoning))
+	activity.GetLogger(ctx).Info("CompleteWithReasoningActivity completed successfully",
+		"contentLength", len(resp.Content),
+		"reasoningLength", len(resp.Reasoning))
 
-	return content, nil
+	return resp, nil
 }
@@ -1,20 +1,51 @@
 package arxiv
 
 import (
-	"encoding/json"
 	"fmt"
 	"time"
 
-	"github.com/gflarity/bls_agent/pkg/llm"
+	"github.com/gflarity/bls_agent/internal/workflows/completion"
 	"go.temporal.io/sdk/workflow"
 )
 
 type PaperOfTheDayWorkflowParams struct {
 	Date time.Time
 	// OpenAI configuration
-	OpenAIAPIKey  string `json:"openai_api_key"`
 	OpenAIBaseURL string `json:"openai_base_url"`
 	OpenAIModel   string `json:"openai_model"`
+
+	// CredentialProfile names the worker-side credential profile used to look up
+	// the OpenAI API key. Empty means the default profile.
+	CredentialProfile string `json:"credential_profile"`
+
+	// ArxivModel is the model that filters papers. Empty means
+	// defaultPaperModel; OpenAIModel isn't used for papers.
+	ArxivModel string `json:"arxiv_model"`
+
+	// KeepReasoning keeps the model's reasoning in each paper's decision, to
+	// audit why a paper was kept or rejected.
+	KeepReasoning bool `json:"keep_reasoning"`
+}
+
+// DecisionsQuery is the query type returning the decisions made so far, as
+// []PaperDecision.
+const DecisionsQuery = "decisions"
+
+// defaultPaperModel filters papers when ArxivModel isn't set. Its reasoning is
+// returned alongside the verdict.
+const defaultPaperModel = "deepseek/deepseek-r1-0528"
+
+// PaperDecision records whether a paper was kept, and why when the workflow
+// keeps the model's reasoning.
+type PaperDecision struct {
+	ArxivID   string `json:"arxiv_id"`
+	Keep      bool   `json:"keep"`
+	Reasoning string `json:"reasoning,omitempty"`
+}
+
+// KeepResponse is the LLM's verdict on whether a paper is worth keeping.
+type KeepResponse struct {
+	Keep bool `json:"keep" jsonschema:"description=Whether the paper should be kept,title=Keep Paper"`
 }
 
 func PaperOfTheDayWorkflow(ctx workflow.Context, params PaperOfTheDayWorkflowParams) ([]string, error) {
@@ -23,9 +54,23 @@ func PaperOfTheDayWorkflow(ctx workflow.Context, params PaperOfTheDayWorkflowPar
 		StartToCloseTimeout: 60 * time.Second,
 	})
 
+	// Expose every decision so a run can be audited while and after it runs
+	var decisions []PaperDecision
+	err := workflow.SetQueryHandler(ctx, DecisionsQuery, func() ([]PaperDecision, error) {
+		return decisions, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register decisions query: %w", err)
+	}
+
+	model := params.ArxivModel
+	if model == "" {
+		model = defaultPaperModel
+	}
+
 	// fetch arxiv ids for the date
 	var arxivIds []string
-	err := workflow.ExecuteActivity(ctx, GetArxivIdsForDateActivity, params.Date).Get(ctx, &arxivIds)
+	err = workflow.ExecuteActivity(ctx, GetArxivIdsForDateActivity, params.Date).Get(ctx, &arxivIds)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get arxiv ids: %w", err)
 	}
@@ -44,17 +89,8 @@ func PaperOfTheDayWorkflow(ctx workflow.Context, params PaperOfTheDayWorkflowPar
 		}
 
 		//   filter unwanted papers based on abstract
-		var keeper struct {
-			Keep bool `json:"keep" jsonschema:"description=Whether the paper should be kept,title=Keep Paper"`
-		}
-
-		schema, err := llm.GenerateSchema(keeper)
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate schema from type: %w", err)
-		}
-
 		// TODO just use a param struct
-		sys := fmt.Sprintf("You are an expert AI Research Analyst. Here's the json schema you need to adhere to: <schema>%s</schema>", schema)
+		sys := "You are an expert AI Research Analyst."
 		user := fmt.Sprintf(`
 Your task is to filter academic abstracts to identify groundbreaking research in AI efficiency.
 
@@ -83,13 +119,13 @@ Describes improvements to a data pipeline or MLOps process that do not change th
 
 Example 1 (Correctly identify as true)
 
-Abstract: "We introduce 'Sparse-Quant,' a novel post-training quantization algorithm that applies structured pruning to large language models. Our method reduces the memory footprint by 60% and increases inference throughput by 2.5x on standard benchmarks with less than a 1% drop in accuracy. This enables the deployment of billion-parameter models on commodity hardware, significantly reducing operational costs."
+Abstract: "We introduce 'Sparse-Quant,' a novel post-training quantization algorithm that applies structured pruning to large language models. Our method reduces the memory footprint by 60%% and increases inference throughput by 2.5x on standard benchmarks with less than a 1%% drop in accuracy. This enables the deployment of billion-parameter models on commodity hardware, significantly reducing operational costs."
 
 Your Reasoning: This abstract introduces a new algorithm (Sparse-Quant) that directly improves inference throughput and reduces memory, which are core metrics for performance-per-dollar in AI systems. The answer is true.
 
 Example 2 (Correctly identify as false)
 
-Abstract: "This paper demonstrates the application of a transformer-based LLM to optimize global supply chain routing. By analyzing historical shipping data, our model generates routes that reduce fuel consumption and operational costs by 15% compared to traditional methods. Our findings show that leveraging AI can create more sustainable and cost-effective logistics networks."
+Abstract: "This paper demonstrates the application of a transformer-based LLM to optimize global supply chain routing. By analyzing historical shipping data, our model generates routes that reduce fuel consumption and operational costs by 15%% compared to traditional methods. Our findings show that leveraging AI can create more sustainable and cost-effective logistics networks."
 
 Your Reasoning: This abstract uses an LLM to solve a logistics problem. The innovation is in the application of AI, not in making the LLM itself more efficient. The cost savings are in logistics, not in the model's training or inference. The answer is false.
 
@@ -100,18 +136,17 @@ Respond with a JSON object containing a single key is_relevant with a boolean va
 
 Abstract: %s`, abs)
 
-		model := "deepseek/deepseek-r1-0528"
-		// TODO need to implement better reasoning support for DS V3.1, in the mean time just use DSR1
-		var res string
-		err = workflow.ExecuteActivity(ctx, CompleteWithSchemaActivity, params.OpenAIAPIKey, params.OpenAIBaseURL, schema, sys, user, model).Get(ctx, &res)
+		keeper, reasoning, err := completion.Into[KeepResponse](ctx, CompleteWithReasoningActivity, params.CredentialProfile, params.OpenAIBaseURL, model, sys, user)
 		if err != nil {
 			return nil, fmt.Errorf("failed to complete with schema: %w", err)
 		}
 
-		err = json.Unmarshal([]byte(res), &keeper)
-		if err != nil {
-			return nil, fmt.Errorf("failed to unmarshal response into keeper: %w", err)
+		decision := PaperDecision{ArxivID: arxivId, Keep: keeper.Keep}
+		if params.KeepReasoning {
+			decision.Reasoning = reasoning
 		}
+		decisions = append(decisions, decision)
+		workflow.GetLogger(ctx).Info("Paper decision", "arxivId", arxivId, "keep", keeper.Keep, "reasoningLength", len(reasoning))
 
 		if keeper.Keep {
 			ids = append(ids, arxivId)
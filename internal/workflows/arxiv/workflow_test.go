@@ -0,0 +1,262 @@
+package arxiv
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gflarity/bls_agent/pkg/llm"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+)
+
+func TestPaperOfTheDayWorkflow(t *testing.T) {
+	date := time.Date(2025, 8, 20, 0, 0, 0, 0, time.UTC)
+
+	// abstracts maps each paper to its abstract and the verdict the mocked LLM returns
+	abstracts := map[string]struct {
+		abstract string
+		response string
+	}{
+		"2508.00001": {"We introduce a quantization method that halves inference memory.", `{"keep": true}`},
+		"2508.00002": {"We apply an LLM to optimize shipping routes.", `{"keep": false}`},
+		"2508.00003": {"A sparse attention kernel that doubles training throughput.", `{"keep": true}`},
+	}
+
+	testCases := []struct {
+		name        string
+		ids         []string
+		llmErr      error
+		llmResponse string
+		expected    []string
+		expectedErr string
+	}{
+		{
+			name:     "Keep and reject",
+			ids:      []string{"2508.00001", "2508.00002", "2508.00003"},
+			expected: []string{"2508.00001", "2508.00003"},
+		},
+		{
+			name:     "Nothing kept",
+			ids:      []string{"2508.00002"},
+			expected: nil,
+		},
+		{
+			name:     "No papers",
+			ids:      []string{},
+			expected: nil,
+		},
+		{
+			name:        "LLM failure",
+			ids:         []string{"2508.00001"},
+			llmErr:      temporal.NewNonRetryableApplicationError("rate limited", "TestFailure", nil),
+			expectedErr: "failed to complete with schema",
+		},
+		{
+			name:        "Response doesn't match the schema",
+			ids:         []string{"2508.00001"},
+			llmResponse: `{"is_relevant": true}`,
+			expectedErr: "doesn't match the schema",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var suite testsuite.WorkflowTestSuite
+			env := suite.NewTestWorkflowEnvironment()
+
+			env.OnActivity(GetArxivIdsForDateActivity, mock.Anything, mock.Anything).Return(tc.ids, nil)
+			for id, paper := range abstracts {
+				env.OnActivity(GetArxivAbstractActivity, mock.Anything, id).Return(paper.abstract, nil)
+
+				abstract, response := paper.abstract, paper.response
+				if tc.llmErr != nil {
+					response = ""
+				}
+				if tc.llmResponse != "" {
+					response = tc.llmResponse
+				}
+				env.OnActivity(CompleteWithReasoningActivity, mock.Anything, "research", mock.Anything, mock.Anything, mock.Anything,
+					mock.MatchedBy(func(user string) bool { return strings.HasSuffix(user, "Abstract: "+abstract) }),
+					mock.Anything).Return(llm.Response{Content: response}, tc.llmErr)
+			}
+
+			env.ExecuteWorkflow(PaperOfTheDayWorkflow, PaperOfTheDayWorkflowParams{Date: date, CredentialProfile: "research"})
+
+			if !env.IsWorkflowCompleted() {
+				t.Fatal("Workflow did not complete")
+			}
+
+			err := env.GetWorkflowError()
+			if tc.expectedErr != "" {
+				if err == nil {
+					t.Fatal("Expected the workflow to fail")
+				}
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Errorf("Expected error containing '%s', but got: %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Workflow returned an error: %v", err)
+			}
+
+			var got []string
+			if err := env.GetWorkflowResult(&got); err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tc.expected) {
+				t.Fatalf("Workflow result = %v, want %v", got, tc.expected)
+			}
+			for i := range got {
+				if got[i] != tc.expected[i] {
+					t.Errorf("Workflow result = %v, want %v", got, tc.expected)
+					break
+				}
+			}
+			env.AssertActivityNumberOfCalls(t, "CompleteWithReasoningActivity", len(tc.ids))
+		})
+	}
+}
+
+func TestPaperOfTheDayWorkflowDecisions(t *testing.T) {
+	for _, keepReasoning := range []bool{false, true} {
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestWorkflowEnvironment()
+
+		env.OnActivity(GetArxivIdsForDateActivity, mock.Anything, mock.Anything).Return([]string{"2508.00001", "2508.00002"}, nil)
+		env.OnActivity(GetArxivAbstractActivity, mock.Anything, "2508.00001").Return("A new quantization method.", nil)
+		env.OnActivity(GetArxivAbstractActivity, mock.Anything, "2508.00002").Return("An LLM for shipping routes.", nil)
+		env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.MatchedBy(func(user string) bool { return strings.HasSuffix(user, "quantization method.") }), defaultPaperModel).
+			Return(llm.Response{Content: `{"keep": true}`, Reasoning: "Quantization improves inference cost."}, nil)
+		env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.MatchedBy(func(user string) bool { return strings.HasSuffix(user, "shipping routes.") }), defaultPaperModel).
+			Return(llm.Response{Content: `{"keep": false}`, Reasoning: "An application, not an efficiency technique."}, nil)
+
+		env.ExecuteWorkflow(PaperOfTheDayWorkflow, PaperOfTheDayWorkflowParams{KeepReasoning: keepReasoning})
+		if err := env.GetWorkflowError(); err != nil {
+			t.Fatalf("KeepReasoning=%v: workflow returned an error: %v", keepReasoning, err)
+		}
+
+		value, err := env.QueryWorkflow(DecisionsQuery)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []PaperDecision
+		if err := value.Get(&got); err != nil {
+			t.Fatal(err)
+		}
+		want := []PaperDecision{
+			{ArxivID: "2508.00001", Keep: true},
+			{ArxivID: "2508.00002", Keep: false},
+		}
+		if keepReasoning {
+			want[0].Reasoning = "Quantization improves inference cost."
+			want[1].Reasoning = "An application, not an efficiency technique."
+		}
+		if len(got) != len(want) {
+			t.Fatalf("KeepReasoning=%v: got decisions %+v, want %+v", keepReasoning, got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("KeepReasoning=%v: decision %d = %+v, want %+v", keepReasoning, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestPaperOfTheDayWorkflowModel(t *testing.T) {
+	testCases := []struct {
+		name   string
+		params PaperOfTheDayWorkflowParams
+		want   string
+	}{
+		{
+			name: "Pinned by default",
+			want: defaultPaperModel,
+		},
+		{
+			name:   "OpenAI model is left to the BLS workflows",
+			params: PaperOfTheDayWorkflowParams{OpenAIModel: "gpt-4o-mini"},
+			want:   defaultPaperModel,
+		},
+		{
+			name:   "Arxiv model",
+			params: PaperOfTheDayWorkflowParams{OpenAIModel: "gpt-4o-mini", ArxivModel: "qwen/qwq-32b"},
+			want:   "qwen/qwq-32b",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var suite testsuite.WorkflowTestSuite
+			env := suite.NewTestWorkflowEnvironment()
+			env.OnActivity(GetArxivIdsForDateActivity, mock.Anything, mock.Anything).Return([]string{"2508.00001"}, nil)
+			env.OnActivity(GetArxivAbstractActivity, mock.Anything, "2508.00001").Return("A new quantization method.", nil)
+			env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, tc.want).
+				Return(llm.Response{Content: `{"keep": true}`}, nil)
+
+			env.ExecuteWorkflow(PaperOfTheDayWorkflow, tc.params)
+			if err := env.GetWorkflowError(); err != nil {
+				t.Fatalf("Workflow returned an error: %v", err)
+			}
+			env.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPaperOfTheDayWorkflowCompletionTimeouts(t *testing.T) {
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+
+	env.OnActivity(GetArxivIdsForDateActivity, mock.Anything, mock.Anything).Return([]string{"2508.00001"}, nil)
+	env.OnActivity(GetArxivAbstractActivity, mock.Anything, "2508.00001").Return("A new quantization method.", nil)
+	env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(llm.Response{Content: `{"keep": true}`}, nil)
+
+	// The completer retries and repairs inside the activity, which takes far
+	// longer than the minute the scraping activities get
+	timeouts := make(map[string]time.Duration)
+	heartbeats := make(map[string]time.Duration)
+	env.SetOnActivityStartedListener(func(info *activity.Info, _ context.Context, _ converter.EncodedValues) {
+		timeouts[info.ActivityType.Name] = info.Deadline.Sub(info.StartedTime)
+		heartbeats[info.ActivityType.Name] = info.HeartbeatTimeout
+	})
+
+	env.ExecuteWorkflow(PaperOfTheDayWorkflow, PaperOfTheDayWorkflowParams{Date: time.Date(2025, 8, 20, 0, 0, 0, 0, time.UTC)})
+	if err := env.GetWorkflowError(); err != nil {
+		t.Fatalf("Workflow returned an error: %v", err)
+	}
+
+	if got := timeouts["GetArxivAbstractActivity"]; got != time.Minute {
+		t.Errorf("GetArxivAbstractActivity timeout = %v, want 1m", got)
+	}
+	if got := timeouts["CompleteWithReasoningActivity"]; got < 10*time.Minute {
+		t.Errorf("CompleteWithReasoningActivity timeout = %v, want room for its retries", got)
+	}
+	if got := heartbeats["CompleteWithReasoningActivity"]; got == 0 {
+		t.Error("Expected CompleteWithReasoningActivity to have a heartbeat timeout")
+	}
+}
+
+func TestPaperOfTheDayWorkflowCompletionAttempts(t *testing.T) {
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+
+	env.OnActivity(GetArxivIdsForDateActivity, mock.Anything, mock.Anything).Return([]string{"2508.00001"}, nil)
+	env.OnActivity(GetArxivAbstractActivity, mock.Anything, "2508.00001").Return("A new quantization method.", nil)
+	env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(llm.Response{}, temporal.NewApplicationError("service unavailable", CompletionTransportErrorType))
+
+	// A completion that keeps failing is given up on rather than retried forever
+	env.ExecuteWorkflow(PaperOfTheDayWorkflow, PaperOfTheDayWorkflowParams{Date: time.Date(2025, 8, 20, 0, 0, 0, 0, time.UTC)})
+	if err := env.GetWorkflowError(); err == nil {
+		t.Fatal("Expected the workflow to fail")
+	}
+	env.AssertActivityNumberOfCalls(t, "CompleteWithReasoningActivity", 3)
+}
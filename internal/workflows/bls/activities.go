@@ -2,14 +2,91 @@ package bls
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/gflarity/bls_agent/internal/credentials"
+	"github.com/gflarity/bls_agent/internal/ledger"
+	"github.com/gflarity/bls_agent/internal/releases"
+	"github.com/gflarity/bls_agent/internal/workflows/completion"
 	"github.com/gflarity/bls_agent/pkg/bls"
+	"github.com/gflarity/bls_agent/pkg/factcheck"
 	"github.com/gflarity/bls_agent/pkg/llm"
 	"github.com/gflarity/bls_agent/pkg/twitter"
 	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
 )
 
+// credentialProvider resolves secrets on the worker. It defaults to reading the
+// environment and can be replaced with SetCredentialProvider.
+var credentialProvider credentials.Provider = credentials.EnvProvider{}
+
+// SetCredentialProvider sets the provider activities use to resolve credentials.
+func SetCredentialProvider(p credentials.Provider) {
+	credentialProvider = p
+}
+
+// completer answers CompleteWithReasoningActivity. When it isn't set an
+// OpenAI-compatible API is called with the workflow's credential profile and
+// base URL.
+var completer llm.Completer
+
+// SetCompleter sets the completer the completion activity uses in place of the
+// OpenAI-compatible API, e.g. an llm.FakeCompleter in tests.
+func SetCompleter(c llm.Completer) {
+	completer = c
+}
+
+// Application error types CompleteWithReasoningActivity fails with, so workflows
+// can tell a model that gave no answer or kept breaking the schema from an API
+// that couldn't be reached or credentials that couldn't be loaded.
+const (
+	CompletionTransportErrorType   = completion.TransportErrorType
+	CompletionRefusedErrorType     = completion.RefusedErrorType
+	CompletionInvalidErrorType     = completion.InvalidErrorType
+	CompletionCredentialsErrorType = completion.CredentialsErrorType
+)
+
+// blsClient fetches the calendar and releases. It defaults to bls.gov and can be
+// replaced with SetBLSClient, e.g. to point at a local stand-in.
+var blsClient = bls.DefaultClient
+
+// SetBLSClient sets the client activities use to reach bls.gov.
+func SetBLSClient(c *bls.Client) {
+	blsClient = c
+}
+
+// calendar keeps the release calendar on disk between fetches. When it isn't
+// set the calendar is downloaded on every call.
+var calendar *bls.CalendarCache
+
+// SetCalendarCache sets the cache activities read the release calendar through.
+func SetCalendarCache(c *bls.CalendarCache) {
+	calendar = c
+}
+
+// publications is the worker's record of what has already been posted.
+var publications ledger.Store
+
+// errNoPublicationStore is returned when the worker didn't call SetPublicationStore.
+var errNoPublicationStore = errors.New("no publication store configured")
+
+// SetPublicationStore sets the store used by the publication activities. The
+// worker must call it before it starts polling.
+func SetPublicationStore(store ledger.Store) {
+	publications = store
+}
+
+// releaseStore keeps the figures of every fetched release. When it isn't set
+// releases aren't compared with the previous one.
+var releaseStore releases.Store
+
+// SetReleaseStore sets the store CompareReleaseActivity keeps releases in.
+func SetReleaseStore(store releases.Store) {
+	releaseStore = store
+}
+
 // FindEventsActivity finds BLS events that happened within the last specified minutes
 func FindEventsActivity(ctx context.Context, mins float64) ([]bls.Event, error) {
 	// Get activity info
@@ -23,8 +100,8 @@ func FindEventsActivity(ctx context.Context, mins float64) ([]bls.Event, error)
 		"runID", runID,
 		"mins", mins)
 
-	// Call the BLS package function
-	events, err := bls.FindEvents(mins)
+	// Call the BLS client
+	events, err := blsClient.FindEvents(ctx, mins)
 	if err != nil {
 		activity.GetLogger(ctx).Error("FindEventsActivity failed", "error", err)
 		return nil, fmt.Errorf("failed to find events: %w", err)
@@ -37,49 +114,139 @@ func FindEventsActivity(ctx context.Context, mins float64) ([]bls.Event, error)
 	return events, nil
 }
 
-// CompleteWithSchemaActivity performs LLM completion with a specified JSON schema
-func CompleteWithSchemaActivity(
+// GetAllEventsActivity fetches every event in the BLS release calendar
+func GetAllEventsActivity(ctx context.Context) ([]bls.Event, error) {
+	// Get activity info
+	activityInfo := activity.GetInfo(ctx)
+	workflowID := activityInfo.WorkflowExecution.ID
+	runID := activityInfo.WorkflowExecution.RunID
+
+	// Log activity execution
+	activity.GetLogger(ctx).Info("Executing GetAllEventsActivity",
+		"workflowID", workflowID,
+		"runID", runID)
+
+	events, err := getAllEvents(ctx)
+	if err != nil {
+		activity.GetLogger(ctx).Error("GetAllEventsActivity failed", "error", err)
+		return nil, fmt.Errorf("failed to get all events: %w", err)
+	}
+
+	// Log the results
+	activity.GetLogger(ctx).Info("GetAllEventsActivity completed successfully",
+		"eventsFound", len(events))
+
+	return events, nil
+}
+
+// FindScheduledEventsActivity returns the calendar events starting after `after`
+// and no later than `until`, ordered by start time. Filtering on the worker keeps
+// the full calendar out of the scheduler's history.
+func FindScheduledEventsActivity(ctx context.Context, after time.Time, until time.Time) ([]bls.Event, error) {
+	// Get activity info
+	activityInfo := activity.GetInfo(ctx)
+	workflowID := activityInfo.WorkflowExecution.ID
+	runID := activityInfo.WorkflowExecution.RunID
+
+	// Log activity execution
+	activity.GetLogger(ctx).Info("Executing FindScheduledEventsActivity",
+		"workflowID", workflowID,
+		"runID", runID,
+		"after", after,
+		"until", until)
+
+	events, err := getAllEvents(ctx)
+	if err != nil {
+		activity.GetLogger(ctx).Error("FindScheduledEventsActivity failed", "error", err)
+		return nil, fmt.Errorf("failed to get all events: %w", err)
+	}
+	scheduled := bls.EventsBetween(events, after, until)
+
+	// Log the results
+	activity.GetLogger(ctx).Info("FindScheduledEventsActivity completed successfully",
+		"calendarEvents", len(events),
+		"eventsFound", len(scheduled))
+
+	return scheduled, nil
+}
+
+// getAllEvents reads the calendar through the cache when one is configured.
+func getAllEvents(ctx context.Context) ([]bls.Event, error) {
+	if calendar == nil {
+		return blsClient.GetAllEvents(ctx)
+	}
+
+	snapshot, err := calendar.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := activity.GetLogger(ctx)
+	if snapshot.Stale {
+		logger.Warn("Using cached calendar, bls.gov fetch failed",
+			"fetchedAt", snapshot.FetchedAt,
+			"error", snapshot.FetchErr)
+	}
+	if snapshot.Diff != nil && !snapshot.Diff.Empty() {
+		logger.Info("BLS calendar changed",
+			"added", len(snapshot.Diff.Added),
+			"removed", len(snapshot.Diff.Removed),
+			"rescheduled", len(snapshot.Diff.Rescheduled))
+	}
+
+	return snapshot.Events, nil
+}
+
+// CompleteWithReasoningActivity performs LLM completion with a specified JSON
+// schema. The API key is resolved on the worker from the named credential
+// profile. The response carries the model's reasoning when the provider returns
+// it.
+func CompleteWithReasoningActivity(
 	ctx context.Context,
-	apiKey string,
+	credentialProfile string,
 	baseURL string,
 	schema string,
 	systemPrompt string,
 	userPrompt string,
 	model string,
-) (string, error) {
+) (llm.Response, error) {
 	// Get activity info
 	activityInfo := activity.GetInfo(ctx)
 	workflowID := activityInfo.WorkflowExecution.ID
 	runID := activityInfo.WorkflowExecution.RunID
 
 	// Log activity execution
-	activity.GetLogger(ctx).Info("Executing CompleteWithSchemaActivity",
+	activity.GetLogger(ctx).Info("Executing CompleteWithReasoningActivity",
 		"workflowID", workflowID,
 		"runID", runID,
 		"model", model,
-		"baseURL", baseURL)
+		"baseURL", baseURL,
+		"credentialProfile", credentialProfile)
+
+	c, err := completion.For(completer, credentialProvider, credentialProfile, baseURL)
+	if err != nil {
+		activity.GetLogger(ctx).Error("CompleteWithReasoningActivity failed to load credentials", "error", err)
+		return llm.Response{}, err
+	}
 
-	// Call the LLM package function
-	content, reasoning, err := llm.CompleteWithSchema(
-		ctx,
-		apiKey,
-		baseURL,
-		schema,
-		systemPrompt,
-		userPrompt,
-		model,
-	)
+	// Call the completer
+	resp, err := c.Complete(ctx, llm.Request{
+		Schema:       schema,
+		SystemPrompt: systemPrompt,
+		UserPrompt:   userPrompt,
+		Options:      llm.Options{Model: model},
+	})
 	if err != nil {
-		activity.GetLogger(ctx).Error("CompleteWithSchemaActivity failed", "error", err)
-		return "", fmt.Errorf("failed to complete with schema: %w", err)
+		activity.GetLogger(ctx).Error("CompleteWithReasoningActivity failed", "error", err)
+		return llm.Response{}, completion.Error(err)
 	}
 
 	// Log the results
-	activity.GetLogger(ctx).Info("CompleteWithSchemaActivity completed successfully",
-		"contentLength", len(content),
-		"reasoningLength", len(reasoning))
+	activity.GetLogger(ctx).Info("CompleteWithReasoningActivity completed successfully",
+		"contentLength", len(resp.Content),
+		"reasoningLength", len(resp.Reasoning))
 
-	return content, nil
+	return resp, nil
 }
 
 // FetchReleaseHTMLActivity fetches the HTML for the release of an event
@@ -95,8 +262,8 @@ func FetchReleaseHTMLActivity(ctx context.Context, event bls.Event) (string, err
 		"runID", runID,
 		"eventSummary", event.Summary)
 
-	// Call the BLS package function
-	html, err := bls.FetchReleaseHTML(event)
+	// Call the BLS client
+	html, err := blsClient.FetchReleaseHTML(ctx, event)
 	if err != nil {
 		activity.GetLogger(ctx).Error("FetchReleaseHTMLActivity failed", "error", err)
 		return "", fmt.Errorf("failed to fetch release HTML: %w", err)
@@ -109,7 +276,135 @@ func FetchReleaseHTMLActivity(ctx context.Context, event bls.Event) (string, err
 	return html, nil
 }
 
-// ExtractSummaryActivity extracts the summary text from the release HTML
+// FetchArchivedReleaseHTMLActivity fetches the archived HTML for the release of
+// a past event. Events without a release or an archive fail right away, since
+// retrying won't change the answer.
+func FetchArchivedReleaseHTMLActivity(ctx context.Context, event bls.Event) (string, error) {
+	// Get activity info
+	activityInfo := activity.GetInfo(ctx)
+	workflowID := activityInfo.WorkflowExecution.ID
+	runID := activityInfo.WorkflowExecution.RunID
+
+	// Log activity execution
+	activity.GetLogger(ctx).Info("Executing FetchArchivedReleaseHTMLActivity",
+		"workflowID", workflowID,
+		"runID", runID,
+		"eventSummary", event.Summary)
+
+	// Call the BLS client
+	html, err := blsClient.FetchArchivedReleaseHTML(ctx, event)
+	switch {
+	case errors.Is(err, bls.ErrNoMapping):
+		activity.GetLogger(ctx).Error("FetchArchivedReleaseHTMLActivity failed", "error", err)
+		return "", temporal.NewNonRetryableApplicationError(fmt.Sprintf("failed to fetch archived release HTML: %v", err), "NoMapping", err)
+	case errors.Is(err, bls.ErrNotArchived):
+		activity.GetLogger(ctx).Error("FetchArchivedReleaseHTMLActivity failed", "error", err)
+		return "", temporal.NewNonRetryableApplicationError(fmt.Sprintf("failed to fetch archived release HTML: %v", err), "NotArchived", err)
+	case err != nil:
+		activity.GetLogger(ctx).Error("FetchArchivedReleaseHTMLActivity failed", "error", err)
+		return "", fmt.Errorf("failed to fetch archived release HTML: %w", err)
+	}
+
+	// Log the results
+	activity.GetLogger(ctx).Info("FetchArchivedReleaseHTMLActivity completed successfully",
+		"htmlLength", len(html))
+
+	return html, nil
+}
+
+// ReleaseLateErrorType is the application error type WaitForReleaseActivity
+// fails with when a release isn't published by the deadline.
+const ReleaseLateErrorType = "ReleaseLate"
+
+// releasePollInterval is the first wait between polls of a release page. It
+// doubles after every poll up to releasePollMaxInterval.
+var (
+	releasePollInterval    = 15 * time.Second
+	releasePollMaxInterval = 2 * time.Minute
+)
+
+// WaitForReleaseActivity polls the release page of an event until it shows the
+// release published on the event's day, and returns its HTML. Right at release
+// time bls.gov may still serve the previous release or answer 403, so those
+// are retried with backoff until the deadline, after which the activity fails
+// with a non-retryable ReleaseLateErrorType error. Events without a start time
+// and releases outside the registry, such as other agencies' releases fetched
+// from the event's URL, can't be checked and return the first page fetched.
+func WaitForReleaseActivity(ctx context.Context, event bls.Event, deadline time.Time) (string, error) {
+	// Get activity info
+	activityInfo := activity.GetInfo(ctx)
+	workflowID := activityInfo.WorkflowExecution.ID
+	runID := activityInfo.WorkflowExecution.RunID
+
+	// Log activity execution
+	activity.GetLogger(ctx).Info("Executing WaitForReleaseActivity",
+		"workflowID", workflowID,
+		"runID", runID,
+		"eventSummary", event.Summary,
+		"deadline", deadline)
+
+	day, hasDay := event.ReleaseDay()
+	if _, ok := blsClient.LookupRelease(event.Summary); !ok {
+		hasDay = false
+	}
+	wait := releasePollInterval
+	for attempt := 1; ; attempt++ {
+		// Call the BLS client
+		var status string
+		html, err := blsClient.FetchReleaseHTML(ctx, event)
+		switch {
+		case errors.Is(err, bls.ErrNoMapping):
+			activity.GetLogger(ctx).Error("WaitForReleaseActivity failed", "error", err)
+			return "", temporal.NewNonRetryableApplicationError(fmt.Sprintf("failed to fetch release HTML: %v", err), "NoMapping", err)
+		case err != nil:
+			status = err.Error()
+		case !hasDay:
+			return html, nil
+		default:
+			published, err := bls.ReleaseDate(html)
+			if err != nil {
+				status = fmt.Sprintf("failed to read release date: %v", err)
+				break
+			}
+			if published.Equal(day) {
+				// Log the results
+				activity.GetLogger(ctx).Info("WaitForReleaseActivity completed successfully",
+					"attempts", attempt,
+					"htmlLength", len(html))
+				return html, nil
+			}
+			status = fmt.Sprintf("page still shows the release of %s", published.Format("2006-01-02"))
+		}
+
+		activity.RecordHeartbeat(ctx, attempt)
+		if time.Now().Add(wait).After(deadline) {
+			activity.GetLogger(ctx).Error("Release not published by the deadline",
+				"eventSummary", event.Summary,
+				"attempts", attempt,
+				"status", status)
+			return "", temporal.NewNonRetryableApplicationError(
+				fmt.Sprintf("release %q was not published by %s: %s", event.Summary, deadline.Format(time.RFC3339), status),
+				ReleaseLateErrorType, nil)
+		}
+		activity.GetLogger(ctx).Info("Release not published yet",
+			"attempt", attempt,
+			"status", status,
+			"retryIn", wait)
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+		if wait > releasePollMaxInterval {
+			wait = releasePollMaxInterval
+		}
+	}
+}
+
+// ExtractSummaryActivity extracts the summary text from the release HTML, or
+// the main text of releases from other agencies
 func ExtractSummaryActivity(ctx context.Context, html string) (string, error) {
 	// Get activity info
 	activityInfo := activity.GetInfo(ctx)
@@ -123,7 +418,7 @@ func ExtractSummaryActivity(ctx context.Context, html string) (string, error) {
 		"htmlLength", len(html))
 
 	// Call the BLS package function
-	summary, err := bls.ExtractSummary(html)
+	summary, err := bls.ExtractReleaseText(html)
 	if err != nil {
 		activity.GetLogger(ctx).Error("ExtractSummaryActivity failed", "error", err)
 		return "", fmt.Errorf("failed to extract summary from HTML: %w", err)
@@ -136,8 +431,123 @@ func ExtractSummaryActivity(ctx context.Context, html string) (string, error) {
 	return summary, nil
 }
 
+// ExtractHeadlineActivity pulls the headline figures out of the release HTML
+// for releases that have a dedicated extractor. It returns no metrics when the
+// release has no extractor or the extractor fails, so the workflow carries on
+// with the summary text alone.
+func ExtractHeadlineActivity(ctx context.Context, event bls.Event, html string) ([]bls.Metric, error) {
+	// Get activity info
+	activityInfo := activity.GetInfo(ctx)
+	workflowID := activityInfo.WorkflowExecution.ID
+	runID := activityInfo.WorkflowExecution.RunID
+
+	// Log activity execution
+	activity.GetLogger(ctx).Info("Executing ExtractHeadlineActivity",
+		"workflowID", workflowID,
+		"runID", runID,
+		"eventSummary", event.Summary,
+		"htmlLength", len(html))
+
+	// Call the BLS package function
+	headline, err := blsClient.ExtractHeadline(event, html)
+	if errors.Is(err, bls.ErrNoExtractor) {
+		activity.GetLogger(ctx).Info("No headline extractor for release", "eventSummary", event.Summary)
+		return nil, nil
+	}
+	if err != nil {
+		// The release layout changes now and then, don't fail the workflow over it
+		activity.GetLogger(ctx).Warn("ExtractHeadlineActivity failed, using the summary alone", "error", err)
+		return nil, nil
+	}
+	metrics := headline.Metrics()
+
+	// Log the results
+	activity.GetLogger(ctx).Info("ExtractHeadlineActivity completed successfully",
+		"metrics", len(metrics))
+
+	return metrics, nil
+}
+
+// FactCheckTweetActivity checks every number, percentage and month in a draft
+// tweet against the release content it was generated from
+func FactCheckTweetActivity(ctx context.Context, tweet string, source string) (factcheck.Report, error) {
+	// Get activity info
+	activityInfo := activity.GetInfo(ctx)
+	workflowID := activityInfo.WorkflowExecution.ID
+	runID := activityInfo.WorkflowExecution.RunID
+
+	// Log activity execution
+	activity.GetLogger(ctx).Info("Executing FactCheckTweetActivity",
+		"workflowID", workflowID,
+		"runID", runID,
+		"tweetLength", len(tweet),
+		"sourceLength", len(source))
+
+	// Call the factcheck package function
+	report := factcheck.Check(tweet, source)
+	for _, claim := range report.Unsupported {
+		activity.GetLogger(ctx).Warn("Unsupported claim in tweet", "claim", claim.Text, "kind", claim.Kind)
+	}
+
+	// Log the results
+	activity.GetLogger(ctx).Info("FactCheckTweetActivity completed successfully",
+		"claims", len(report.Claims),
+		"unsupported", len(report.Unsupported))
+
+	return report, nil
+}
+
+// CompareReleaseActivity stores the figures of a release and compares them with
+// the previous release of the same series. It returns the revisions and trend
+// changes it found as prompt context, or "" when there is nothing to compare.
+func CompareReleaseActivity(ctx context.Context, event bls.Event, html string) (string, error) {
+	// Get activity info
+	activityInfo := activity.GetInfo(ctx)
+	workflowID := activityInfo.WorkflowExecution.ID
+	runID := activityInfo.WorkflowExecution.RunID
+
+	// Log activity execution
+	activity.GetLogger(ctx).Info("Executing CompareReleaseActivity",
+		"workflowID", workflowID,
+		"runID", runID,
+		"eventSummary", event.Summary,
+		"htmlLength", len(html))
+
+	if releaseStore == nil {
+		activity.GetLogger(ctx).Info("No release store configured, skipping comparison")
+		return "", nil
+	}
+
+	// Call the BLS package function
+	current := blsClient.NewReleaseSnapshot(event, html, time.Now())
+	previous, err := releaseStore.Previous(current.Release, current.Start)
+	if err != nil {
+		return "", fmt.Errorf("failed to get previous release: %w", err)
+	}
+	if err := releaseStore.Put(current); err != nil {
+		return "", fmt.Errorf("failed to store release: %w", err)
+	}
+	if previous == nil {
+		activity.GetLogger(ctx).Info("No previous release to compare with", "release", current.Release)
+		return "", nil
+	}
+
+	comparison, err := bls.CompareReleases(*previous, current)
+	if err != nil {
+		return "", fmt.Errorf("failed to compare releases: %w", err)
+	}
+
+	// Log the results
+	activity.GetLogger(ctx).Info("CompareReleaseActivity completed successfully",
+		"previousKey", comparison.PreviousKey,
+		"revisions", len(comparison.Revisions),
+		"trends", len(comparison.Trends))
+
+	return comparison.Context(), nil
+}
+
 // PostTweetThreadActivity posts a thread of tweets to Twitter
-func PostTweetThreadActivity(ctx context.Context, tweetTexts []string, twitterAPIKey, twitterAPISecret, twitterAccessToken, twitterAccessSecret string) error {
+func PostTweetThreadActivity(ctx context.Context, tweetTexts []string, credentialProfile string) error {
 	// Get activity info
 	activityInfo := activity.GetInfo(ctx)
 	workflowID := activityInfo.WorkflowExecution.ID
@@ -149,8 +559,15 @@ func PostTweetThreadActivity(ctx context.Context, tweetTexts []string, twitterAP
 		"runID", runID,
 		"tweetCount", len(tweetTexts))
 
-	// Create a new Twitter client with provided credentials
-	client, err := twitter.NewClientWithCredentials(twitterAPIKey, twitterAPISecret, twitterAccessToken, twitterAccessSecret)
+	// Resolve the Twitter credentials for the profile on the worker
+	creds, err := credentials.LoadTwitter(credentialProvider, credentialProfile)
+	if err != nil {
+		activity.GetLogger(ctx).Error("PostTweetThreadActivity failed to load credentials", "error", err)
+		return fmt.Errorf("failed to load Twitter credentials: %w", err)
+	}
+
+	// Create a new Twitter client with the resolved credentials
+	client, err := twitter.NewClientWithCredentials(creds.APIKey, creds.APISecret, creds.AccessToken, creds.AccessSecret)
 	if err != nil {
 		activity.GetLogger(ctx).Error("PostTweetThreadActivity failed to create Twitter client", "error", err)
 		return fmt.Errorf("failed to create Twitter client: %w", err)
@@ -170,13 +587,14 @@ func PostTweetThreadActivity(ctx context.Context, tweetTexts []string, twitterAP
 	return nil
 }
 
-// PostTweetActivity posts a single tweet to Twitter
-func PostTweetActivity(ctx context.Context, tweetText string, twitterAPIKey, twitterAPISecret, twitterAccessToken, twitterAccessSecret string, forReal bool) error {
+// PostTweetActivity posts a single tweet to Twitter and returns its ID. Dry runs
+// return an empty ID.
+func PostTweetActivity(ctx context.Context, tweetText string, credentialProfile string, forReal bool) (string, error) {
 
 	if !forReal {
 		activity.GetLogger(ctx).Info("PostTweetActivity completed successfully (but not for real)",
 			"tweetPosted", tweetText)
-		return nil
+		return "", nil
 	}
 
 	// Get activity info
@@ -190,23 +608,94 @@ func PostTweetActivity(ctx context.Context, tweetText string, twitterAPIKey, twi
 		"runID", runID,
 		"tweetLength", len(tweetText))
 
-	// Create a new Twitter client with provided credentials
-	client, err := twitter.NewClientWithCredentials(twitterAPIKey, twitterAPISecret, twitterAccessToken, twitterAccessSecret)
+	// Resolve the Twitter credentials for the profile on the worker
+	creds, err := credentials.LoadTwitter(credentialProvider, credentialProfile)
+	if err != nil {
+		activity.GetLogger(ctx).Error("PostTweetActivity failed to load credentials", "error", err)
+		return "", fmt.Errorf("failed to load Twitter credentials: %w", err)
+	}
+
+	// Create a new Twitter client with the resolved credentials
+	client, err := twitter.NewClientWithCredentials(creds.APIKey, creds.APISecret, creds.AccessToken, creds.AccessSecret)
 	if err != nil {
 		activity.GetLogger(ctx).Error("PostTweetActivity failed to create Twitter client", "error", err)
-		return fmt.Errorf("failed to create Twitter client: %w", err)
+		return "", fmt.Errorf("failed to create Twitter client: %w", err)
 	}
 
 	// Post the single tweet
-	_, err = client.PostTweet(tweetText, "")
+	tweetID, err := client.PostTweet(tweetText, "")
 	if err != nil {
 		activity.GetLogger(ctx).Error("PostTweetActivity failed to post tweet", "error", err)
-		return fmt.Errorf("failed to post tweet: %w", err)
+		return "", fmt.Errorf("failed to post tweet: %w", err)
 	}
 
 	// Log the results
 	activity.GetLogger(ctx).Info("PostTweetActivity completed successfully",
+		"tweetID", tweetID,
 		"tweetPosted", tweetText)
 
+	return tweetID, nil
+}
+
+// GetPublicationActivity looks up the publication record for an event key. It
+// returns nil if nothing has been published under that key.
+func GetPublicationActivity(ctx context.Context, key string) (*ledger.Record, error) {
+	// Get activity info
+	activityInfo := activity.GetInfo(ctx)
+	workflowID := activityInfo.WorkflowExecution.ID
+	runID := activityInfo.WorkflowExecution.RunID
+
+	// Log activity execution
+	activity.GetLogger(ctx).Info("Executing GetPublicationActivity",
+		"workflowID", workflowID,
+		"runID", runID,
+		"key", key)
+
+	if publications == nil {
+		return nil, errNoPublicationStore
+	}
+
+	record, err := publications.Get(key)
+	if err != nil {
+		activity.GetLogger(ctx).Error("GetPublicationActivity failed", "error", err)
+		return nil, fmt.Errorf("failed to get publication record: %w", err)
+	}
+
+	// Log the results
+	activity.GetLogger(ctx).Info("GetPublicationActivity completed successfully",
+		"published", record != nil)
+
+	return record, nil
+}
+
+// RecordPublicationActivity stores the publication record for a posted event.
+func RecordPublicationActivity(ctx context.Context, record ledger.Record) error {
+	// Get activity info
+	activityInfo := activity.GetInfo(ctx)
+	workflowID := activityInfo.WorkflowExecution.ID
+	runID := activityInfo.WorkflowExecution.RunID
+
+	// Log activity execution
+	activity.GetLogger(ctx).Info("Executing RecordPublicationActivity",
+		"workflowID", workflowID,
+		"runID", runID,
+		"key", record.Key,
+		"tweetID", record.TweetID)
+
+	if publications == nil {
+		return errNoPublicationStore
+	}
+
+	// A retried activity may find its own record already in place
+	err := publications.Put(record)
+	if err != nil && !errors.Is(err, ledger.ErrAlreadyRecorded) {
+		activity.GetLogger(ctx).Error("RecordPublicationActivity failed", "error", err)
+		return fmt.Errorf("failed to record publication: %w", err)
+	}
+
+	// Log the results
+	activity.GetLogger(ctx).Info("RecordPublicationActivity completed successfully",
+		"key", record.Key)
+
 	return nil
 }
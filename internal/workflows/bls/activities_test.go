@@ -0,0 +1,342 @@
+package bls
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gflarity/bls_agent/internal/credentials"
+	"github.com/gflarity/bls_agent/internal/releases"
+	"github.com/gflarity/bls_agent/pkg/bls"
+	"github.com/gflarity/bls_agent/pkg/llm"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+)
+
+const (
+	previousRelease = "<pre>embargoed until\n8:30 a.m. (ET) Wednesday, December 11, 2024\nCONSUMER PRICE INDEX - NOVEMBER 2024</pre>"
+	currentRelease  = "<pre>embargoed until\n8:30 a.m. (ET) Wednesday, January 15, 2025\nCONSUMER PRICE INDEX - DECEMBER 2024</pre>"
+)
+
+// useReleaseServer points the activities at a server answering each poll with
+// the next of responses, repeating the last one, and speeds up polling. It
+// returns the server's URL.
+func useReleaseServer(t *testing.T, responses ...string) string {
+	t.Helper()
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := responses[min(polls, len(responses)-1)]
+		polls++
+		if response == "403" {
+			http.Error(w, "Access Denied", http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	client := bls.NewClient()
+	client.HTTPClient = server.Client()
+	client.BaseURL = server.URL
+	previousClient, previousInterval, previousMax := blsClient, releasePollInterval, releasePollMaxInterval
+	SetBLSClient(client)
+	releasePollInterval, releasePollMaxInterval = 10*time.Millisecond, 20*time.Millisecond
+	t.Cleanup(func() {
+		SetBLSClient(previousClient)
+		releasePollInterval, releasePollMaxInterval = previousInterval, previousMax
+	})
+	return server.URL
+}
+
+func TestWaitForReleaseActivity(t *testing.T) {
+	t.Run("Waits out the previous release and 403s", func(t *testing.T) {
+		useReleaseServer(t, "403", previousRelease, currentRelease)
+
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestActivityEnvironment()
+		env.RegisterActivity(WaitForReleaseActivity)
+
+		value, err := env.ExecuteActivity(WaitForReleaseActivity, testEvent(), time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatalf("WaitForReleaseActivity returned an error: %v", err)
+		}
+		var html string
+		if err := value.Get(&html); err != nil {
+			t.Fatal(err)
+		}
+		if html != currentRelease {
+			t.Errorf("Got %q, want the current release", html)
+		}
+	})
+
+	t.Run("Late release", func(t *testing.T) {
+		useReleaseServer(t, previousRelease)
+
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestActivityEnvironment()
+		env.RegisterActivity(WaitForReleaseActivity)
+
+		_, err := env.ExecuteActivity(WaitForReleaseActivity, testEvent(), time.Now().Add(50*time.Millisecond))
+		var appErr *temporal.ApplicationError
+		if !errors.As(err, &appErr) || appErr.Type() != ReleaseLateErrorType || !appErr.NonRetryable() {
+			t.Fatalf("Expected a non-retryable %s error, got %v", ReleaseLateErrorType, err)
+		}
+		if !strings.Contains(err.Error(), "2024-12-11") {
+			t.Errorf("Expected the error to name the release still on the page, got %v", err)
+		}
+	})
+
+	t.Run("Unmapped event fails right away", func(t *testing.T) {
+		useReleaseServer(t, currentRelease)
+
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestActivityEnvironment()
+		env.RegisterActivity(WaitForReleaseActivity)
+
+		event := testEvent()
+		event.Summary = "Gross Domestic Product"
+		_, err := env.ExecuteActivity(WaitForReleaseActivity, event, time.Now().Add(time.Minute))
+		var appErr *temporal.ApplicationError
+		if !errors.As(err, &appErr) || !appErr.NonRetryable() || appErr.Type() == ReleaseLateErrorType {
+			t.Fatalf("Expected a non-retryable mapping error, got %v", err)
+		}
+	})
+
+	t.Run("Event URL outside the registry isn't date checked", func(t *testing.T) {
+		page := "<main><p>Real GDP increased 2.3 percent.</p></main>"
+		url := useReleaseServer(t, page, "403")
+
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestActivityEnvironment()
+		env.RegisterActivity(WaitForReleaseActivity)
+
+		event := testEvent()
+		event.Summary = "Gross Domestic Product"
+		event.URL = url + "/data/gdp/gross-domestic-product"
+		value, err := env.ExecuteActivity(WaitForReleaseActivity, event, time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatalf("WaitForReleaseActivity returned an error: %v", err)
+		}
+		var html string
+		if err := value.Get(&html); err != nil {
+			t.Fatal(err)
+		}
+		if html != page {
+			t.Errorf("Got %q, want the first page fetched", html)
+		}
+	})
+}
+
+func TestCompareReleaseActivity(t *testing.T) {
+	store := releases.NewMemoryStore()
+	previousStore := releaseStore
+	SetReleaseStore(store)
+	t.Cleanup(func() { SetReleaseStore(previousStore) })
+
+	compare := func(event bls.Event, html string) string {
+		t.Helper()
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestActivityEnvironment()
+		env.RegisterActivity(CompareReleaseActivity)
+
+		value, err := env.ExecuteActivity(CompareReleaseActivity, event, html)
+		if err != nil {
+			t.Fatalf("CompareReleaseActivity returned an error: %v", err)
+		}
+		var comparison string
+		if err := value.Get(&comparison); err != nil {
+			t.Fatal(err)
+		}
+		return comparison
+	}
+
+	data, err := os.ReadFile("../../../pkg/bls/testdata/cpi_release.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	current := string(data)
+	// The same tables a month earlier
+	previous := strings.NewReplacer("Oct.<br>2024", "Sep.<br>2024", "Nov.<br>2024", "Oct.<br>2024", "Dec.<br>2024", "Nov.<br>2024").Replace(current)
+
+	previousStart := time.Date(2024, 12, 11, 13, 30, 0, 0, time.UTC)
+	previousEvent := bls.Event{Summary: "Consumer Price Index", UID: "cpi-2024-12@bls.gov", Start: &previousStart}
+	if got := compare(previousEvent, previous); got != "" {
+		t.Errorf("Expected no comparison for the first release, got %q", got)
+	}
+
+	got := compare(testEvent(), current)
+	if !strings.Contains(got, "CPI all items, m/m") || !strings.Contains(got, "in Nov. 2024") {
+		t.Errorf("Expected the comparison to mention the previous reading, got %q", got)
+	}
+}
+
+func TestCompleteWithReasoningActivity(t *testing.T) {
+	previous := completer
+	t.Cleanup(func() { SetCompleter(previous) })
+
+	t.Run("Uses the injected completer", func(t *testing.T) {
+		fake := llm.NewFakeCompleter(llm.FakeReply{Content: `{"tweet": "CPI rose"}`, Reasoning: "Lead with the headline."})
+		SetCompleter(fake)
+
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestActivityEnvironment()
+		env.RegisterActivity(CompleteWithReasoningActivity)
+
+		value, err := env.ExecuteActivity(CompleteWithReasoningActivity, "default", "http://localhost", `{"type":"object"}`, "system", "user", "test-model")
+		if err != nil {
+			t.Fatalf("CompleteWithReasoningActivity returned an error: %v", err)
+		}
+		var resp llm.Response
+		if err := value.Get(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Content != `{"tweet": "CPI rose"}` || resp.Reasoning != "Lead with the headline." {
+			t.Errorf("Got %+v", resp)
+		}
+		requests := fake.Requests()
+		if len(requests) != 1 {
+			t.Fatalf("Expected one request, got %d", len(requests))
+		}
+		want := llm.Request{Schema: `{"type":"object"}`, SystemPrompt: "system", UserPrompt: "user", Options: llm.Options{Model: "test-model"}}
+		if requests[0] != want {
+			t.Errorf("Got request %+v, want %+v", requests[0], want)
+		}
+	})
+
+	t.Run("Repairs responses that break the schema", func(t *testing.T) {
+		schema, err := llm.SchemaFor[TweetResponse]()
+		if err != nil {
+			t.Fatal(err)
+		}
+		fake := llm.NewFakeCompleter().
+			Reply(`{"tweet": "` + strings.Repeat("a", 281) + `"}`).
+			Reply(`{"tweet": "CPI rose"}`)
+		SetCompleter(fake)
+
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestActivityEnvironment()
+		env.RegisterActivity(CompleteWithReasoningActivity)
+
+		value, err := env.ExecuteActivity(CompleteWithReasoningActivity, "default", "http://localhost", schema, "system", "user", "test-model")
+		if err != nil {
+			t.Fatalf("CompleteWithReasoningActivity returned an error: %v", err)
+		}
+		var resp llm.Response
+		if err := value.Get(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Content != `{"tweet": "CPI rose"}` {
+			t.Errorf("Got %q, want the repaired tweet", resp.Content)
+		}
+		if n := len(fake.Requests()); n != 2 {
+			t.Errorf("Expected 2 completions, got %d", n)
+		}
+	})
+
+	t.Run("Refusals have their own error type", func(t *testing.T) {
+		SetCompleter(llm.NewFakeCompleter().Fail(&llm.RefusalError{Refusal: "I can't help with that"}))
+
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestActivityEnvironment()
+		env.RegisterActivity(CompleteWithReasoningActivity)
+
+		_, err := env.ExecuteActivity(CompleteWithReasoningActivity, "default", "http://localhost", `{"type":"object"}`, "system", "user", "test-model")
+		var appErr *temporal.ApplicationError
+		if !errors.As(err, &appErr) || appErr.Type() != CompletionRefusedErrorType {
+			t.Fatalf("Expected a %s error, got %v", CompletionRefusedErrorType, err)
+		}
+		if !strings.Contains(err.Error(), "I can't help with that") {
+			t.Errorf("Expected the error to carry the refusal, got %v", err)
+		}
+	})
+	t.Run("Error classification", func(t *testing.T) {
+		tests := []struct {
+			name             string
+			err              error
+			wantType         string
+			wantNonRetryable bool
+			wantDelay        time.Duration
+		}{
+			{
+				name:             "Bad credentials",
+				err:              &llm.TransportError{StatusCode: http.StatusUnauthorized, Err: errors.New("invalid api key")},
+				wantType:         CompletionTransportErrorType,
+				wantNonRetryable: true,
+			},
+			{
+				name:             "Context length",
+				err:              &llm.TransportError{StatusCode: http.StatusBadRequest, Code: "context_length_exceeded", Err: errors.New("too long")},
+				wantType:         CompletionTransportErrorType,
+				wantNonRetryable: true,
+			},
+			{
+				name:      "Rate limited",
+				err:       &llm.TransportError{StatusCode: http.StatusTooManyRequests, RetryAfter: 2 * time.Minute, Err: errors.New("slow down")},
+				wantType:  CompletionTransportErrorType,
+				wantDelay: 2 * time.Minute,
+			},
+			{
+				name:     "Server error",
+				err:      &llm.TransportError{StatusCode: http.StatusBadGateway, Err: errors.New("upstream failed")},
+				wantType: CompletionTransportErrorType,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				SetCompleter(llm.NewFakeCompleter().Fail(tt.err))
+
+				var suite testsuite.WorkflowTestSuite
+				env := suite.NewTestActivityEnvironment()
+				env.RegisterActivity(CompleteWithReasoningActivity)
+
+				_, err := env.ExecuteActivity(CompleteWithReasoningActivity, "default", "http://localhost", `{"type":"object"}`, "system", "user", "test-model")
+				var appErr *temporal.ApplicationError
+				if !errors.As(err, &appErr) || appErr.Type() != tt.wantType {
+					t.Fatalf("Expected a %s error, got %v", tt.wantType, err)
+				}
+				if appErr.NonRetryable() != tt.wantNonRetryable {
+					t.Errorf("NonRetryable = %v, want %v", appErr.NonRetryable(), tt.wantNonRetryable)
+				}
+				if appErr.NextRetryDelay() != tt.wantDelay {
+					t.Errorf("NextRetryDelay = %v, want %v", appErr.NextRetryDelay(), tt.wantDelay)
+				}
+			})
+		}
+	})
+
+	t.Run("Missing credentials aren't retried", func(t *testing.T) {
+		SetCompleter(nil)
+		previousProvider := credentialProvider
+		SetCredentialProvider(credentials.DirProvider{Dir: t.TempDir()})
+		t.Cleanup(func() { SetCredentialProvider(previousProvider) })
+
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestActivityEnvironment()
+		env.RegisterActivity(CompleteWithReasoningActivity)
+
+		_, err := env.ExecuteActivity(CompleteWithReasoningActivity, "default", "http://localhost", `{"type":"object"}`, "system", "user", "test-model")
+		var appErr *temporal.ApplicationError
+		if !errors.As(err, &appErr) || appErr.Type() != CompletionCredentialsErrorType || !appErr.NonRetryable() {
+			t.Fatalf("Expected a non-retryable %s error, got %v", CompletionCredentialsErrorType, err)
+		}
+	})
+
+	t.Run("Invalid schemas aren't retried", func(t *testing.T) {
+		SetCompleter(llm.NewFakeCompleter().Reply(`{"tweet": "CPI rose"}`))
+
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestActivityEnvironment()
+		env.RegisterActivity(CompleteWithReasoningActivity)
+
+		_, err := env.ExecuteActivity(CompleteWithReasoningActivity, "default", "http://localhost", `{"type":`, "system", "user", "test-model")
+		var appErr *temporal.ApplicationError
+		if !errors.As(err, &appErr) || appErr.Type() != CompletionInvalidErrorType || !appErr.NonRetryable() {
+			t.Fatalf("Expected a non-retryable %s error, got %v", CompletionInvalidErrorType, err)
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package bls
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gflarity/bls_agent/pkg/bls"
+	"github.com/gflarity/bls_agent/pkg/twitter"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	// DraftQuery is the query that returns an event workflow's current Draft.
+	DraftQuery = "draft"
+	// ApprovalSignal is the signal carrying an ApprovalDecision for a pending draft.
+	ApprovalSignal = "approval"
+
+	// defaultApprovalTimeout is how long a draft waits for a reviewer by default.
+	defaultApprovalTimeout = 2 * time.Hour
+)
+
+// DraftStatus describes where a draft is in the approval process.
+type DraftStatus string
+
+const (
+	DraftStatusGenerating DraftStatus = "generating"
+	DraftStatusPending    DraftStatus = "pending_approval"
+	DraftStatusApproved   DraftStatus = "approved"
+	DraftStatusRejected   DraftStatus = "rejected"
+	DraftStatusTimedOut   DraftStatus = "timed_out"
+	DraftStatusPosted     DraftStatus = "posted"
+	DraftStatusFailed     DraftStatus = "failed"
+)
+
+// ApprovalAction is the reviewer's verdict on a draft.
+type ApprovalAction string
+
+const (
+	ApprovalApprove ApprovalAction = "approve"
+	ApprovalEdit    ApprovalAction = "edit"
+	ApprovalReject  ApprovalAction = "reject"
+)
+
+// ApprovalDecision is the payload of ApprovalSignal. Text is only used by edits.
+type ApprovalDecision struct {
+	Action   ApprovalAction `json:"action"`
+	Text     string         `json:"text,omitempty"`
+	Reviewer string         `json:"reviewer,omitempty"`
+}
+
+// Draft is the state exposed by DraftQuery.
+type Draft struct {
+	Event            bls.Event   `json:"event"`
+	Text             string      `json:"text"`
+	Reasoning        string      `json:"reasoning,omitempty"`
+	Status           DraftStatus `json:"status"`
+	Reviewer         string      `json:"reviewer,omitempty"`
+	ApprovalDeadline time.Time   `json:"approval_deadline,omitempty"`
+}
+
+// awaitApproval marks the draft as pending and waits for an ApprovalSignal or
+// the timeout. It returns the text to post, or "" if the draft was rejected or
+// nobody answered in time. Invalid edits and unknown actions are logged and
+// ignored, so the reviewer can try again before the deadline.
+func awaitApproval(ctx workflow.Context, timeout time.Duration, draft *Draft) string {
+	if timeout <= 0 {
+		timeout = defaultApprovalTimeout
+	}
+
+	draft.Status = DraftStatusPending
+	draft.ApprovalDeadline = workflow.Now(ctx).Add(timeout)
+	workflow.GetLogger(ctx).Info("Waiting for draft approval",
+		"event", draft.Event.Summary,
+		"deadline", draft.ApprovalDeadline)
+
+	timerCtx, cancelTimer := workflow.WithCancel(ctx)
+	defer cancelTimer()
+	timer := workflow.NewTimer(timerCtx, timeout)
+	signals := workflow.GetSignalChannel(ctx, ApprovalSignal)
+
+	for {
+		var decision ApprovalDecision
+		timedOut := false
+
+		selector := workflow.NewSelector(ctx)
+		selector.AddReceive(signals, func(c workflow.ReceiveChannel, more bool) {
+			c.Receive(ctx, &decision)
+		})
+		selector.AddFuture(timer, func(workflow.Future) {
+			timedOut = true
+		})
+		selector.Select(ctx)
+
+		if timedOut {
+			draft.Status = DraftStatusTimedOut
+			workflow.GetLogger(ctx).Warn("Draft approval timed out", "event", draft.Event.Summary)
+			return ""
+		}
+
+		switch decision.Action {
+		case ApprovalApprove:
+			draft.Status = DraftStatusApproved
+			draft.Reviewer = decision.Reviewer
+			workflow.GetLogger(ctx).Info("Draft approved", "event", draft.Event.Summary, "reviewer", decision.Reviewer)
+			return draft.Text
+
+		case ApprovalEdit:
+			text := strings.TrimSpace(decision.Text)
+			if length := twitter.Length(text); length == 0 || length > twitter.MaxTweetLength {
+				workflow.GetLogger(ctx).Warn("Ignoring invalid edit", "event", draft.Event.Summary, "reviewer", decision.Reviewer, "length", length)
+				continue
+			}
+			draft.Text = text
+			draft.Status = DraftStatusApproved
+			draft.Reviewer = decision.Reviewer
+			workflow.GetLogger(ctx).Info("Draft edited and approved", "event", draft.Event.Summary, "reviewer", decision.Reviewer)
+			return draft.Text
+
+		case ApprovalReject:
+			draft.Status = DraftStatusRejected
+			draft.Reviewer = decision.Reviewer
+			workflow.GetLogger(ctx).Info("Draft rejected", "event", draft.Event.Summary, "reviewer", decision.Reviewer)
+			return ""
+
+		default:
+			workflow.GetLogger(ctx).Warn("Ignoring unknown approval action", "event", draft.Event.Summary, "action", decision.Action)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package bls
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gflarity/bls_agent/pkg/bls"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	// defaultRefreshInterval is how long the scheduler runs before re-reading the calendar.
+	defaultRefreshInterval = 24 * time.Hour
+	// defaultStartDelay gives bls.gov a moment to publish before we fetch the release.
+	defaultStartDelay = 2 * time.Minute
+	// defaultCheckInterval is how often the calendar is checked for changes between refreshes.
+	defaultCheckInterval = time.Hour
+)
+
+// SchedulerParams contains the parameters for BLSReleaseSchedulerWorkflow.
+type SchedulerParams struct {
+	// WorkflowParams are handed to every per-event child workflow. Mins is unused.
+	WorkflowParams
+
+	// RefreshInterval is how often the calendar is re-read. The workflow continues
+	// as new after each refresh to keep its history small. Defaults to 24 hours.
+	RefreshInterval time.Duration `json:"refresh_interval"`
+	// StartDelay is how long after an event's start time its child workflow is
+	// started. Defaults to 2 minutes.
+	StartDelay time.Duration `json:"start_delay"`
+	// CheckInterval is how often the calendar is checked for added, removed or
+	// rescheduled releases between refreshes. Defaults to 1 hour.
+	CheckInterval time.Duration `json:"check_interval"`
+
+	// Cursor is the start time of the last event handed off to a child workflow.
+	// It is carried across ContinueAsNew so events are never started twice.
+	Cursor time.Time `json:"cursor"`
+}
+
+// BLSReleaseSchedulerWorkflow is a long-running workflow that reads the BLS
+// calendar, sleeps until each upcoming release and starts a BLSEventSummaryWorkflow
+// child for it. Every CheckInterval it re-reads the calendar so added, removed and
+// rescheduled releases are picked up. After RefreshInterval it continues as new.
+func BLSReleaseSchedulerWorkflow(ctx workflow.Context, params SchedulerParams) error {
+	if params.RefreshInterval <= 0 {
+		params.RefreshInterval = defaultRefreshInterval
+	}
+	if params.StartDelay <= 0 {
+		params.StartDelay = defaultStartDelay
+	}
+	if params.CheckInterval <= 0 {
+		params.CheckInterval = defaultCheckInterval
+	}
+
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 120 * time.Second,
+	})
+
+	now := workflow.Now(ctx)
+	if params.Cursor.IsZero() {
+		params.Cursor = now
+	}
+	refreshAt := now.Add(params.RefreshInterval)
+
+	var due []bls.Event
+	err := workflow.ExecuteActivity(ctx, FindScheduledEventsActivity, params.Cursor, refreshAt).Get(ctx, &due)
+	if err != nil {
+		return fmt.Errorf("failed to get calendar events: %w", err)
+	}
+
+	workflow.GetLogger(ctx).Info("Scheduling BLS events",
+		"dueEvents", len(due),
+		"cursor", params.Cursor,
+		"refreshAt", refreshAt)
+
+	nextCheck := now.Add(params.CheckInterval)
+	for {
+		now := workflow.Now(ctx)
+
+		// Start the next release once its delay has passed
+		wakeAt := refreshAt
+		if len(due) > 0 {
+			wakeAt = due[0].Start.Add(params.StartDelay)
+			if !wakeAt.After(now) {
+				event := due[0]
+				due = due[1:]
+				if err := startEventWorkflow(ctx, params.WorkflowParams, event); err != nil {
+					// A failed start shouldn't stop the rest of the schedule.
+					workflow.GetLogger(ctx).Error("Failed to start event workflow", "event", event.Summary, "error", err)
+				}
+				params.Cursor = *event.Start
+				continue
+			}
+		} else if !refreshAt.After(now) {
+			break
+		}
+
+		if !nextCheck.After(now) {
+			due = checkCalendar(ctx, due, params.Cursor, refreshAt)
+			nextCheck = now.Add(params.CheckInterval)
+			continue
+		}
+
+		if nextCheck.Before(wakeAt) {
+			wakeAt = nextCheck
+		}
+		if err := workflow.Sleep(ctx, wakeAt.Sub(now)); err != nil {
+			return err
+		}
+	}
+
+	return workflow.NewContinueAsNewError(ctx, BLSReleaseSchedulerWorkflow, params)
+}
+
+// startEventWorkflow starts a BLSEventSummaryWorkflow child for the event and
+// waits only until it has started. The child is abandoned on ContinueAsNew so
+// it can keep running after the scheduler's run ends. An event that was already
+// processed under its workflow ID is skipped.
+func startEventWorkflow(ctx workflow.Context, params WorkflowParams, event bls.Event) error {
+	opts := eventChildOptions(event, params.TweetForReal)
+	opts.ParentClosePolicy = enumspb.PARENT_CLOSE_POLICY_ABANDON
+	cctx := workflow.WithChildOptions(ctx, opts)
+
+	child := workflow.ExecuteChildWorkflow(cctx, BLSEventSummaryWorkflow, EventWorkflowParams{
+		WorkflowParams: params,
+		Event:          event,
+	})
+
+	var execution workflow.Execution
+	err := child.GetChildWorkflowExecution().Get(ctx, &execution)
+	if temporal.IsWorkflowExecutionAlreadyStartedError(err) {
+		workflow.GetLogger(ctx).Info("Event already processed, skipping", "event", event.Summary, "key", event.Key())
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	workflow.GetLogger(ctx).Info("Started event workflow",
+		"event", event.Summary,
+		"workflowID", execution.ID,
+		"runID", execution.RunID)
+	return nil
+}
+
+// checkCalendar re-reads the calendar and returns the events still to start,
+// logging how they differ from due. If the calendar can't be read the current
+// schedule is kept.
+func checkCalendar(ctx workflow.Context, due []bls.Event, cursor, refreshAt time.Time) []bls.Event {
+	logger := workflow.GetLogger(ctx)
+
+	var current []bls.Event
+	err := workflow.ExecuteActivity(ctx, FindScheduledEventsActivity, cursor, refreshAt).Get(ctx, &current)
+	if err != nil {
+		logger.Warn("Failed to check calendar, keeping current schedule", "error", err)
+		return due
+	}
+
+	diff := bls.DiffEvents(due, current)
+	for _, event := range diff.Added {
+		logger.Info("Release added to calendar", "event", event.Summary, "start", event.Start)
+	}
+	for _, event := range diff.Removed {
+		logger.Info("Release removed from calendar", "event", event.Summary, "start", event.Start)
+	}
+	for _, moved := range diff.Rescheduled {
+		logger.Info("Release rescheduled",
+			"event", moved.Current.Summary,
+			"previousStart", moved.Previous.Start,
+			"start", moved.Current.Start)
+	}
+
+	return current
+}
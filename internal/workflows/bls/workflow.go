@@ -1,12 +1,19 @@
 package bls
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/gflarity/bls_agent/internal/ledger"
+	"github.com/gflarity/bls_agent/internal/workflows/completion"
 	"github.com/gflarity/bls_agent/pkg/bls"
+	"github.com/gflarity/bls_agent/pkg/factcheck"
 	"github.com/gflarity/bls_agent/pkg/llm"
+	"github.com/gflarity/bls_agent/pkg/twitter"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
 )
 
@@ -14,22 +21,62 @@ import (
 type WorkflowParams struct {
 	Mins float64 `json:"mins"`
 	// OpenAI configuration
-	OpenAIAPIKey  string `json:"openai_api_key"`
 	OpenAIBaseURL string `json:"openai_base_url"`
 	OpenAIModel   string `json:"openai_model"`
-	// Twitter credentials
-	TwitterAPIKey       string `json:"twitter_api_key"`
-	TwitterAPISecret    string `json:"twitter_api_secret"`
-	TwitterAccessToken  string `json:"twitter_access_token"`
-	TwitterAccessSecret string `json:"twitter_access_token_secret"`
+
+	// CredentialProfile names the worker-side credential profile used to look up
+	// the OpenAI and Twitter secrets. Empty means the default profile. Secrets are
+	// never passed as workflow input, so they don't end up in Temporal history.
+	CredentialProfile string `json:"credential_profile"`
 
 	// Tweet For Real
 	TweetForReal bool `json:"tweet_for_real"`
+
+	// RequireApproval holds each draft until a reviewer approves, edits or
+	// rejects it through ApprovalSignal. Drafts are rejected after
+	// ApprovalTimeout, which defaults to 2 hours.
+	RequireApproval bool          `json:"require_approval"`
+	ApprovalTimeout time.Duration `json:"approval_timeout"`
+
+	// DraftAttempts is how many drafts are generated before giving up when
+	// drafts keep citing figures that aren't in the release. Defaults to 2.
+	DraftAttempts int `json:"draft_attempts"`
+
+	// ReleaseWaitTimeout is how long to wait for bls.gov to publish a release
+	// before reporting it late. Defaults to 30 minutes.
+	ReleaseWaitTimeout time.Duration `json:"release_wait_timeout"`
+
+	// KeepReasoning stores the model's reasoning for a posted tweet in its
+	// publication record, to audit why it was phrased the way it was.
+	KeepReasoning bool `json:"keep_reasoning"`
+
+	// FromArchive fetches each event's release from the bls.gov archive instead
+	// of waiting for it on the current release page, to replay past events.
+	// Archived releases are never posted for real.
+	FromArchive bool `json:"from_archive"`
+}
+
+const (
+	// defaultDraftAttempts allows one regeneration after a failed fact check
+	defaultDraftAttempts = 2
+	// defaultReleaseWaitTimeout is how long releases are waited for by default
+	defaultReleaseWaitTimeout = 30 * time.Minute
+)
+
+// EventWorkflowParams contains the parameters for BLSEventSummaryWorkflow, which
+// summarizes a single, already known event.
+type EventWorkflowParams struct {
+	WorkflowParams
+	Event bls.Event `json:"event"`
+
+	// Agency is the full name of the agency that publishes the event, e.g.
+	// "Bureau of Economic Analysis", for the prompts. Empty means BLS.
+	Agency string `json:"agency,omitempty"`
 }
 
 // TweetResponse represents the expected response from the LLM
 type TweetResponse struct {
-	Tweet string `json:"tweet" jsonschema:"required,description=A single tweet summarizing the BLS release,minLength=1,maxLength=280"`
+	Tweet string `json:"tweet" jsonschema:"required,description=A single tweet summarizing the release,minLength=1,maxLength=280"`
 }
 
 // BLSReleaseSummaryWorkflow is a workflow that generates BLS release summaries
@@ -54,128 +101,364 @@ func BLSReleaseSummaryWorkflow(ctx workflow.Context, params WorkflowParams) ([]s
 		return nil, nil
 	}
 
-	// Process each event individually and post to Twitter
+	// Process each event individually and post to Twitter. The events run as
+	// children in parallel, so a draft waiting for approval doesn't hold up the
+	// events after it.
 	// Note that we continue even if one event fails, because we want to post any tweets if possible
-	var twtsums []string
+	children := make([]workflow.ChildWorkflowFuture, len(events))
 	for i, event := range events {
-
-		// create a ticker for 5 seconds, so we don't post tweets to quickly, we'll
-		// wait below until the ticker is done
-		timer := workflow.NewTimer(ctx, 5*time.Second)
+		// stagger the starts by 5 seconds, so we don't post tweets to quickly
+		if i > 0 {
+			if err := workflow.Sleep(ctx, 5*time.Second); err != nil {
+				return nil, err
+			}
+		}
 
 		workflow.GetLogger(ctx).Info("Processing event", "index", i, "summary", event.Summary)
 
-		var html string
-		err := workflow.ExecuteActivity(ctx, FetchReleaseHTMLActivity, event).Get(ctx, &html)
+		// Each event runs as a child under its deterministic ID, so an event that
+		// was already handled by the scheduler or another run is skipped
+		cctx := workflow.WithChildOptions(ctx, eventChildOptions(event, params.TweetForReal))
+		children[i] = workflow.ExecuteChildWorkflow(cctx, BLSEventSummaryWorkflow, EventWorkflowParams{
+			WorkflowParams: params,
+			Event:          event,
+		})
+	}
+
+	var twtsums []string
+	for i, child := range children {
+		event := events[i]
+		var twttxt string
+		err := child.Get(ctx, &twttxt)
+		if temporal.IsWorkflowExecutionAlreadyStartedError(err) {
+			workflow.GetLogger(ctx).Info("Event already processed, skipping", "event", event.Summary, "key", event.Key())
+			continue
+		}
 		if err != nil {
-			workflow.GetLogger(ctx).Error("Failed to fetch HTML for event", "event", event.Summary, "error", err)
 			// Continue with other events even if one fails
+			workflow.GetLogger(ctx).Error("Event workflow failed", "event", event.Summary, "error", err)
 			continue
 		}
+		if twttxt != "" {
+			twtsums = append(twtsums, twttxt)
+		}
+	}
+	return twtsums, nil
+}
+
+// BLSEventSummaryWorkflow summarizes and posts a single BLS event. It should be
+// started under EventWorkflowID so the same event is never completed twice, and
+// when posting for real it also checks the publication ledger before doing any
+// work.
+func BLSEventSummaryWorkflow(ctx workflow.Context, params EventWorkflowParams) (string, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 600 * time.Second,
+	})
 
-		// Extract twtsum from HTML
-		var txtsum string
-		err = workflow.ExecuteActivity(ctx, ExtractSummaryActivity, html).Get(ctx, &txtsum)
+	event := params.Event
+	key := event.Key()
+	workflow.GetLogger(ctx).Info("Processing event", "summary", event.Summary, "key", key)
+
+	// Expose the draft so reviewers can see what is about to be posted
+	draft := &Draft{Event: event, Status: DraftStatusGenerating}
+	err := workflow.SetQueryHandler(ctx, DraftQuery, func() (Draft, error) {
+		return *draft, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to register draft query: %w", err)
+	}
+
+	// Replays are for evaluating prompts on past releases, never for posting
+	if params.FromArchive && params.TweetForReal {
+		return "", errors.New("archived releases can't be posted for real")
+	}
+
+	// Dry runs never post, so only real runs need to consult the ledger
+	if params.TweetForReal {
+		record, err := findPublication(ctx, key)
 		if err != nil {
-			workflow.GetLogger(ctx).Error("Failed to extract summary from HTML", "event", event.Summary, "error", err)
-			continue
+			return "", err
 		}
+		if record != nil {
+			workflow.GetLogger(ctx).Info("Event already published, skipping", "event", event.Summary, "tweetID", record.TweetID)
+			return record.Text, nil
+		}
+	}
 
-		// Use LLM to create a Twitter-appropriate summary for this specific event
-		prompt := fmt.Sprintf("Create a concise tweet summarizing this BLS release: %s\n\nContent: %s\n\nCreate a single engaging tweet under 280 characters focusing on the most important economic insights and data points.", event.Summary, txtsum)
+	twttxt, reasoning, err := summarizeEvent(ctx, params.WorkflowParams, params.Agency, event)
+	if err != nil {
+		draft.Status = DraftStatusFailed
+		return "", err
+	}
+	draft.Text = twttxt
+	draft.Reasoning = reasoning
 
-		// Temporarily use a hardcoded schema string for testing
-		twtstruct := TweetResponse{}
-		schema, err := llm.GenerateSchemaFromType(twtstruct)
-		if err != nil {
-			workflow.GetLogger(ctx).Error("Failed to generate schema from type", "error", err)
-			continue
+	if params.RequireApproval {
+		twttxt = awaitApproval(ctx, params.ApprovalTimeout, draft)
+		if twttxt == "" {
+			return "", nil
 		}
+	}
 
-		// Pretty print the generated schema
-		schemaBytes, err := json.MarshalIndent(schema, "", "  ")
+	// Drafting and approval can take hours, so check again that nothing else
+	// posted the event in the meantime
+	if params.TweetForReal {
+		record, err := findPublication(ctx, key)
 		if err != nil {
-			workflow.GetLogger(ctx).Error("Failed to marshal schema", "error", err)
-			continue
+			draft.Status = DraftStatusFailed
+			return "", err
 		}
+		if record != nil {
+			workflow.GetLogger(ctx).Info("Event published while drafting, skipping", "event", event.Summary, "tweetID", record.TweetID)
+			return record.Text, nil
+		}
+	}
 
-		// Marshal the schema map to a JSON string for CompleteWithSchema
-		schemaStr := string(schemaBytes)
-		workflow.GetLogger(ctx).Info("Generated Schema: %s", schemaStr)
-
-		//schemaStr := `{"type":"object","properties":{"tweet":{"type":"string","description":"A single tweet summarizing the BLS release","minLength":1,"maxLength":280}},"required":["tweet"]}`
-
-		// Get LLM configuration from workflow params
-		apiKey := params.OpenAIAPIKey
-		baseURL := params.OpenAIBaseURL
-		model := params.OpenAIModel
-
-		sysprom := "You are an expert economic analyst who creates engaging single tweets about BLS (Bureau of Labor Statistics) releases. Your responses must follow the exact JSON schema provided."
-
-		// Final validation of all parameters before activity call
-		var resp string
-		workflow.GetLogger(ctx).Debug("Final parameters for CompleteWithSchemaActivity",
-			"baseURL", baseURL,
-			"schemaStr", schemaStr,
-			"systemPrompt", sysprom,
-			// only print the first 80 characters of the prompt
-			"prompt", prompt,
-			"model", model,
-			"apiKeyType", fmt.Sprintf("%T", apiKey),
-			"baseURLType", fmt.Sprintf("%T", baseURL),
-			"schemaStrType", fmt.Sprintf("%T", schemaStr),
-			"systemPromptType", fmt.Sprintf("%T", sysprom),
-			"promptType", fmt.Sprintf("%T", prompt),
-			"modelType", fmt.Sprintf("%T", model))
-
-		err = workflow.ExecuteActivity(ctx, CompleteWithSchemaActivity, apiKey, baseURL, schemaStr, sysprom, prompt, model).Get(ctx, &resp)
-		// unmarshal the response into the twtstruct
-		err = json.Unmarshal([]byte(resp), &twtstruct)
+	tweetID, err := postTweet(ctx, params.WorkflowParams, event, twttxt)
+	if err != nil {
+		draft.Status = DraftStatusFailed
+		return "", err
+	}
+	draft.Status = DraftStatusPosted
+
+	if params.TweetForReal {
+		record := ledger.Record{
+			Key:         key,
+			Summary:     event.Summary,
+			TweetID:     tweetID,
+			Text:        twttxt,
+			WorkflowID:  workflow.GetInfo(ctx).WorkflowExecution.ID,
+			PublishedAt: workflow.Now(ctx),
+		}
+		if event.Start != nil {
+			record.Start = *event.Start
+		}
+		if params.KeepReasoning {
+			record.Reasoning = reasoning
+		}
+		// The tweet is out, so failing here would only invite a second post
+		err = workflow.ExecuteActivity(ctx, RecordPublicationActivity, record).Get(ctx, nil)
 		if err != nil {
-			workflow.GetLogger(ctx).Error("Failed to unmarshal response into twtstruct", "error", err)
-			workflow.GetLogger(ctx).Error("response", "response", resp)
-			continue
+			workflow.GetLogger(ctx).Error("Failed to record publication", "event", event.Summary, "tweetID", tweetID, "error", err)
 		}
+	}
+	return twttxt, nil
+}
 
-		// Process the LLM response for this event
-		var twttxt string
-		if resp != "" {
-			err = json.Unmarshal([]byte(resp), &twtstruct)
-			if err != nil {
-				workflow.GetLogger(ctx).Error("Failed to unmarshal response into twtstruct", "error", err)
-				continue
-			}
-			twttxt = twtstruct.Tweet
+// findPublication returns the ledger record for an event key, or nil when the
+// event hasn't been published
+func findPublication(ctx workflow.Context, key string) (*ledger.Record, error) {
+	var record *ledger.Record
+	err := workflow.ExecuteActivity(ctx, GetPublicationActivity, key).Get(ctx, &record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check publication ledger: %w", err)
+	}
+	return record, nil
+}
 
-			// Validate tweet length
-			if len(twttxt) > 280 {
-				workflow.GetLogger(ctx).Error("LLM generated tweet is too long: %d characters (max 280)", twttxt)
-				continue
-			}
+// EventWorkflowID returns the deterministic workflow ID an event is processed
+// under. Dry runs use their own prefix so they never block a real post.
+func EventWorkflowID(event bls.Event, forReal bool) string {
+	if forReal {
+		return "bls-event-" + event.Key()
+	}
+	return "bls-event-dryrun-" + event.Key()
+}
+
+// eventChildOptions returns the child workflow options for processing an event.
+// Rejecting duplicate IDs means a closed run, successful or not, is never
+// repeated, since a run that failed may have failed after posting.
+func eventChildOptions(event bls.Event, forReal bool) workflow.ChildWorkflowOptions {
+	return workflow.ChildWorkflowOptions{
+		WorkflowID:            EventWorkflowID(event, forReal),
+		WorkflowIDReusePolicy: enumspb.WORKFLOW_ID_REUSE_POLICY_REJECT_DUPLICATE,
+	}
+}
+
+// summarizeEvent fetches the release for an event and asks the LLM for a tweet
+// summarizing it. It returns the tweet and the model's reasoning for it.
+// Failures are logged here, so callers only need to decide whether to carry on.
+// agency is the publishing agency's full name, empty for BLS.
+func summarizeEvent(ctx workflow.Context, params WorkflowParams, agency string, event bls.Event) (string, string, error) {
+	html, err := fetchRelease(ctx, params, event)
+	if err != nil {
+		return "", "", err
+	}
+
+	var txtsum string
+	err = workflow.ExecuteActivity(ctx, ExtractSummaryActivity, html).Get(ctx, &txtsum)
+	if err != nil {
+		workflow.GetLogger(ctx).Error("Failed to extract summary from HTML", "event", event.Summary, "error", err)
+		return "", "", fmt.Errorf("failed to extract summary: %w", err)
+	}
+
+	// Releases with an extractor get their exact headline figures next to the
+	// summary text
+	var metrics []bls.Metric
+	err = workflow.ExecuteActivity(ctx, ExtractHeadlineActivity, event, html).Get(ctx, &metrics)
+	if err != nil {
+		workflow.GetLogger(ctx).Error("Failed to extract headline from HTML", "event", event.Summary, "error", err)
+		return "", "", fmt.Errorf("failed to extract headline: %w", err)
+	}
+	if len(metrics) > 0 {
+		txtsum += "\n\nHeadline figures:\n" + bls.FormatMetrics(metrics)
+	}
+
+	// Revisions and trend changes since the previous release give the tweet
+	// something the headline alone doesn't, but aren't worth failing over
+	var comparison string
+	err = workflow.ExecuteActivity(ctx, CompareReleaseActivity, event, html).Get(ctx, &comparison)
+	if err != nil {
+		workflow.GetLogger(ctx).Warn("Failed to compare with the previous release", "event", event.Summary, "error", err)
+	} else if comparison != "" {
+		txtsum += "\n\nCompared with the previous release:\n" + comparison
+	}
+
+	// Use LLM to create a Twitter-appropriate summary for this specific event
+	prompt := fmt.Sprintf("Create a concise tweet summarizing this release: %s\n\nContent: %s\n\nCreate a single engaging tweet under 280 characters focusing on the most important economic insights and data points.", event.Summary, txtsum)
+
+	// Every figure in the tweet must come from the release text, its headline
+	// figures or the comparison, drafts that fail the check are regenerated with
+	// the offending figures pointed out
+	attempts := params.DraftAttempts
+	if attempts <= 0 {
+		attempts = defaultDraftAttempts
+	}
+	var report factcheck.Report
+	for attempt := 1; attempt <= attempts; attempt++ {
+		twttxt, reasoning, err := draftTweet(ctx, params, agency, event, prompt)
+		if err != nil {
+			return "", "", err
+		}
 
+		err = workflow.ExecuteActivity(ctx, FactCheckTweetActivity, twttxt, txtsum).Get(ctx, &report)
+		if err != nil {
+			workflow.GetLogger(ctx).Error("Failed to fact check tweet", "event", event.Summary, "error", err)
+			return "", "", fmt.Errorf("failed to fact check tweet: %w", err)
+		}
+		if report.OK() {
+			workflow.GetLogger(ctx).Info("Tweet passed fact check", "event", event.Summary, "attempt", attempt, "claims", len(report.Claims))
+			return twttxt, reasoning, nil
 		}
 
-		// wait for the timer to finish so that we don't post tweets to quickly			// wait for the ticker to finish so that we don't post tweets to quickly
-		timer.Get(ctx, nil)
+		workflow.GetLogger(ctx).Warn("Tweet failed fact check", "event", event.Summary, "attempt", attempt, "tweet", twttxt, "report", report.String())
+		prompt = fmt.Sprintf("%s\n\nA previous draft used figures that are not in the content (%s). Only use numbers, percentages and months that appear in the content.", prompt, unsupportedText(report))
+	}
 
-		// Post the tweet for this specific event
-		if twttxt != "" {
-			workflow.GetLogger(ctx).Info("Posting tweet for event", "event", event.Summary, "tweetLength", len(twttxt))
-
-			err = workflow.ExecuteActivity(ctx, PostTweetActivity, twttxt, params.TwitterAPIKey, params.TwitterAPISecret, params.TwitterAccessToken, params.TwitterAccessSecret, params.TweetForReal).Get(ctx, nil)
-			if err != nil {
-				workflow.GetLogger(ctx).Error("Failed to post tweet for event", "event", event.Summary, "tweet", twttxt, "error", err)
-				continue
-			} else {
-				workflow.GetLogger(ctx).Info("Successfully posted tweet for event", "event", event.Summary, "tweet", twttxt[:min(len(twttxt), 50)])
-				twtsums = append(twtsums, twttxt)
-			}
-		} else {
-			workflow.GetLogger(ctx).Error("No valid tweet generated for event", "event", event.Summary)
-			continue
+	workflow.GetLogger(ctx).Error("Rejecting tweet that failed fact check", "event", event.Summary, "attempts", attempts)
+	return "", "", fmt.Errorf("tweet failed fact check after %d attempts: %s", attempts, report.String())
+}
+
+// fetchRelease gets the HTML of the release for an event, from the archive for
+// replays and otherwise by waiting for bls.gov to publish it
+func fetchRelease(ctx workflow.Context, params WorkflowParams, event bls.Event) (string, error) {
+	var html string
+	if params.FromArchive {
+		err := workflow.ExecuteActivity(ctx, FetchArchivedReleaseHTMLActivity, event).Get(ctx, &html)
+		if err != nil {
+			workflow.GetLogger(ctx).Error("Failed to fetch archived HTML for event", "event", event.Summary, "error", err)
+			return "", fmt.Errorf("failed to fetch archived release HTML: %w", err)
 		}
+		return html, nil
 	}
-	return twtsums, nil
+
+	// Wait for bls.gov to publish this release rather than summarizing the
+	// previous one still on the page
+	waitTimeout := params.ReleaseWaitTimeout
+	if waitTimeout <= 0 {
+		waitTimeout = defaultReleaseWaitTimeout
+	}
+	wctx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: waitTimeout + 5*time.Minute,
+		HeartbeatTimeout:    5 * time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 3},
+	})
+	err := workflow.ExecuteActivity(wctx, WaitForReleaseActivity, event, workflow.Now(ctx).Add(waitTimeout)).Get(ctx, &html)
+	var appErr *temporal.ApplicationError
+	if errors.As(err, &appErr) && appErr.Type() == ReleaseLateErrorType {
+		workflow.GetLogger(ctx).Error("Release is late, not posting", "event", event.Summary, "error", err)
+		return "", fmt.Errorf("release is late: %w", err)
+	}
+	if err != nil {
+		workflow.GetLogger(ctx).Error("Failed to fetch HTML for event", "event", event.Summary, "error", err)
+		return "", fmt.Errorf("failed to fetch release HTML: %w", err)
+	}
+	return html, nil
+}
+
+// draftTweet asks the LLM for a single tweet for the prompt and checks it fits.
+// It also returns the model's reasoning for the tweet.
+func draftTweet(ctx workflow.Context, params WorkflowParams, agency string, event bls.Event, prompt string) (string, string, error) {
+	sysprom := systemPrompt(agency)
+
+	workflow.GetLogger(ctx).Debug("Drafting tweet",
+		"baseURL", params.OpenAIBaseURL,
+		"model", params.OpenAIModel,
+		"credentialProfile", params.CredentialProfile,
+		"promptLength", len(prompt))
+
+	resp, reasoning, err := completion.Into[TweetResponse](ctx, CompleteWithReasoningActivity, params.CredentialProfile, params.OpenAIBaseURL, params.OpenAIModel, sysprom, prompt)
+	var schemaErr *llm.SchemaError
+	if errors.As(err, &schemaErr) {
+		workflow.GetLogger(ctx).Error("Failed to unmarshal LLM response", "error", err, "response", schemaErr.Content)
+		return "", "", fmt.Errorf("failed to unmarshal LLM response: %w", err)
+	}
+	if err != nil {
+		workflow.GetLogger(ctx).Error("Failed to complete with schema", "event", event.Summary, "error", err)
+		return "", "", fmt.Errorf("failed to complete with schema: %w", err)
+	}
+
+	twttxt := resp.Tweet
+	if twttxt == "" {
+		workflow.GetLogger(ctx).Error("No valid tweet generated for event", "event", event.Summary)
+		return "", "", fmt.Errorf("no valid tweet generated for event %s", event.Summary)
+	}
+
+	// Validate tweet length the way X counts it, with emoji and URLs weighted
+	if length := twitter.Length(twttxt); length > twitter.MaxTweetLength {
+		workflow.GetLogger(ctx).Error("LLM generated tweet is too long", "length", length)
+		return "", "", fmt.Errorf("generated tweet is too long: %d characters (max %d)", length, twitter.MaxTweetLength)
+	}
+	return twttxt, reasoning, nil
+}
+
+// systemPrompt returns the system prompt for drafting tweets about an agency's
+// releases, BLS when agency is empty
+func systemPrompt(agency string) string {
+	if agency == "" {
+		agency = "BLS (Bureau of Labor Statistics)"
+	}
+	return fmt.Sprintf("You are an expert economic analyst who creates engaging single tweets about %s releases. Your responses must follow the exact JSON schema provided.", agency)
+}
+
+// unsupportedText lists the unsupported figures of a report for the prompt
+func unsupportedText(report factcheck.Report) string {
+	texts := make([]string, len(report.Unsupported))
+	for i, c := range report.Unsupported {
+		texts[i] = c.Text
+	}
+	return strings.Join(texts, ", ")
+}
+
+// postTweet posts the tweet for a single event, honoring params.TweetForReal. It
+// returns the new tweet's ID, which is empty for dry runs. The post is tried
+// once, since an attempt that timed out may still have posted.
+func postTweet(ctx workflow.Context, params WorkflowParams, event bls.Event, twttxt string) (string, error) {
+	workflow.GetLogger(ctx).Info("Posting tweet for event", "event", event.Summary, "tweetLength", len(twttxt))
+
+	opts := workflow.GetActivityOptions(ctx)
+	opts.RetryPolicy = &temporal.RetryPolicy{MaximumAttempts: 1}
+	ctx = workflow.WithActivityOptions(ctx, opts)
+
+	var tweetID string
+	err := workflow.ExecuteActivity(ctx, PostTweetActivity, twttxt, params.CredentialProfile, params.TweetForReal).Get(ctx, &tweetID)
+	if err != nil {
+		workflow.GetLogger(ctx).Error("Failed to post tweet for event", "event", event.Summary, "tweet", twttxt, "error", err)
+		return "", fmt.Errorf("failed to post tweet: %w", err)
+	}
+
+	workflow.GetLogger(ctx).Info("Successfully posted tweet for event", "event", event.Summary, "tweetID", tweetID, "tweet", twttxt[:min(len(twttxt), 50)])
+	return tweetID, nil
 }
 
 // min returns the smaller of two integers
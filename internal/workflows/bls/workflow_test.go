@@ -0,0 +1,860 @@
+package bls
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gflarity/bls_agent/internal/ledger"
+	"github.com/gflarity/bls_agent/pkg/bls"
+	agencies "github.com/gflarity/bls_agent/pkg/calendar"
+	"github.com/gflarity/bls_agent/pkg/llm"
+	"github.com/stretchr/testify/mock"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+// These tests run the workflows against Temporal's test environment with the
+// activities mocked or pointed at local servers, so they need neither a
+// Temporal server nor network access.
+
+const testReleaseText = "The Consumer Price Index for All Urban Consumers (CPI-U) increased 0.4 percent on a seasonally adjusted basis in December."
+
+func testEvent() bls.Event {
+	start := time.Date(2025, 1, 15, 8, 30, 0, 0, time.UTC)
+	return bls.Event{Summary: "Consumer Price Index", UID: "cpi-2025-01@bls.gov", Start: &start}
+}
+
+// newEventEnv returns a test environment with the fetch and extract activities
+// mocked to succeed. The release has no headline extractor, so only the summary
+// text is used.
+func newEventEnv() *testsuite.TestWorkflowEnvironment {
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.RegisterActivity(FactCheckTweetActivity)
+	env.OnActivity(WaitForReleaseActivity, mock.Anything, mock.Anything, mock.Anything).Return("<pre>"+testReleaseText+"</pre>", nil)
+	env.OnActivity(ExtractHeadlineActivity, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	env.OnActivity(ExtractSummaryActivity, mock.Anything, mock.Anything).Return(testReleaseText, nil)
+	env.OnActivity(CompareReleaseActivity, mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+	return env
+}
+
+// nonRetryable returns an error so the default retry policy doesn't retry the mocked activity forever.
+func nonRetryable(msg string) error {
+	return temporal.NewNonRetryableApplicationError(msg, "TestFailure", nil)
+}
+
+func TestBLSEventSummaryWorkflow(t *testing.T) {
+	tweet := "CPI rose 0.4% in December."
+
+	t.Run("Happy path - dry run", func(t *testing.T) {
+		env := newEventEnv()
+		env.OnActivity(CompleteWithReasoningActivity, mock.Anything, "default", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(llm.Response{Content: `{"tweet": "` + tweet + `"}`}, nil)
+		env.OnActivity(PostTweetActivity, mock.Anything, tweet, "default", false).Return("", nil)
+
+		env.ExecuteWorkflow(BLSEventSummaryWorkflow, EventWorkflowParams{
+			WorkflowParams: WorkflowParams{CredentialProfile: "default"},
+			Event:          testEvent(),
+		})
+
+		if !env.IsWorkflowCompleted() {
+			t.Fatal("Workflow did not complete")
+		}
+		if err := env.GetWorkflowError(); err != nil {
+			t.Fatalf("Workflow returned an error: %v", err)
+		}
+		var got string
+		if err := env.GetWorkflowResult(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got != tweet {
+			t.Errorf("Workflow result = %q, want %q", got, tweet)
+		}
+		env.AssertExpectations(t)
+		// Dry runs never consult or update the ledger
+		env.AssertActivityNotCalled(t, "GetPublicationActivity", mock.Anything, mock.Anything)
+		env.AssertActivityNotCalled(t, "RecordPublicationActivity", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Happy path - for real records the publication", func(t *testing.T) {
+		env := newEventEnv()
+		env.OnActivity(GetPublicationActivity, mock.Anything, testEvent().Key()).Return(nil, nil)
+		env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(llm.Response{Content: `{"tweet": "` + tweet + `"}`}, nil)
+		env.OnActivity(PostTweetActivity, mock.Anything, tweet, mock.Anything, true).Return("1234", nil)
+		env.OnActivity(RecordPublicationActivity, mock.Anything, mock.MatchedBy(func(r ledger.Record) bool {
+			return r.Key == testEvent().Key() && r.TweetID == "1234" && r.Text == tweet
+		})).Return(nil)
+
+		env.ExecuteWorkflow(BLSEventSummaryWorkflow, EventWorkflowParams{
+			WorkflowParams: WorkflowParams{TweetForReal: true},
+			Event:          testEvent(),
+		})
+
+		if err := env.GetWorkflowError(); err != nil {
+			t.Fatalf("Workflow returned an error: %v", err)
+		}
+		env.AssertExpectations(t)
+	})
+
+	t.Run("Published while drafting", func(t *testing.T) {
+		env := newEventEnv()
+		env.OnActivity(GetPublicationActivity, mock.Anything, testEvent().Key()).Return(nil, nil).Once()
+		env.OnActivity(GetPublicationActivity, mock.Anything, testEvent().Key()).Return(&ledger.Record{Key: testEvent().Key(), TweetID: "99", Text: "Posted elsewhere"}, nil).Once()
+		env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(llm.Response{Content: `{"tweet": "` + tweet + `"}`}, nil)
+
+		env.ExecuteWorkflow(BLSEventSummaryWorkflow, EventWorkflowParams{
+			WorkflowParams: WorkflowParams{TweetForReal: true},
+			Event:          testEvent(),
+		})
+
+		if err := env.GetWorkflowError(); err != nil {
+			t.Fatalf("Workflow returned an error: %v", err)
+		}
+		var result string
+		if err := env.GetWorkflowResult(&result); err != nil {
+			t.Fatal(err)
+		}
+		if result != "Posted elsewhere" {
+			t.Errorf("Expected the published tweet, got %q", result)
+		}
+		env.AssertActivityNotCalled(t, "PostTweetActivity", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Posting is not retried", func(t *testing.T) {
+		env := newEventEnv()
+		env.OnActivity(GetPublicationActivity, mock.Anything, testEvent().Key()).Return(nil, nil)
+		env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(llm.Response{Content: `{"tweet": "` + tweet + `"}`}, nil)
+		env.OnActivity(PostTweetActivity, mock.Anything, tweet, mock.Anything, true).Return("", errors.New("connection reset"))
+
+		env.ExecuteWorkflow(BLSEventSummaryWorkflow, EventWorkflowParams{
+			WorkflowParams: WorkflowParams{TweetForReal: true},
+			Event:          testEvent(),
+		})
+
+		if err := env.GetWorkflowError(); err == nil {
+			t.Fatal("Expected the workflow to fail")
+		}
+		env.AssertActivityNumberOfCalls(t, "PostTweetActivity", 1)
+		env.AssertActivityNotCalled(t, "RecordPublicationActivity", mock.Anything, mock.Anything)
+	})
+
+	t.Run("A posted tweet that can't be recorded still succeeds", func(t *testing.T) {
+		env := newEventEnv()
+		env.OnActivity(GetPublicationActivity, mock.Anything, testEvent().Key()).Return(nil, nil)
+		env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(llm.Response{Content: `{"tweet": "` + tweet + `"}`}, nil)
+		env.OnActivity(PostTweetActivity, mock.Anything, tweet, mock.Anything, true).Return("1234", nil)
+		env.OnActivity(RecordPublicationActivity, mock.Anything, mock.Anything).
+			Return(temporal.NewNonRetryableApplicationError("disk full", "LedgerWrite", nil))
+
+		env.ExecuteWorkflow(BLSEventSummaryWorkflow, EventWorkflowParams{
+			WorkflowParams: WorkflowParams{TweetForReal: true},
+			Event:          testEvent(),
+		})
+
+		if err := env.GetWorkflowError(); err != nil {
+			t.Fatalf("Workflow returned an error: %v", err)
+		}
+		var result string
+		if err := env.GetWorkflowResult(&result); err != nil {
+			t.Fatal(err)
+		}
+		if result != tweet {
+			t.Errorf("Expected the posted tweet, got %q", result)
+		}
+	})
+
+	t.Run("Scripted completer", func(t *testing.T) {
+		fake := llm.NewFakeCompleter().Reply(`{"tweet": "` + tweet + `"}`)
+		previous := completer
+		SetCompleter(fake)
+		t.Cleanup(func() { SetCompleter(previous) })
+
+		env := newEventEnv()
+		env.RegisterActivity(CompleteWithReasoningActivity)
+		env.OnActivity(PostTweetActivity, mock.Anything, tweet, mock.Anything, false).Return("", nil)
+
+		env.ExecuteWorkflow(BLSEventSummaryWorkflow, EventWorkflowParams{
+			WorkflowParams: WorkflowParams{OpenAIModel: "test-model"},
+			Event:          testEvent(),
+		})
+
+		if err := env.GetWorkflowError(); err != nil {
+			t.Fatalf("Workflow returned an error: %v", err)
+		}
+		env.AssertExpectations(t)
+		requests := fake.Requests()
+		if len(requests) != 1 {
+			t.Fatalf("Expected one completion, got %d", len(requests))
+		}
+		if !strings.Contains(requests[0].UserPrompt, testReleaseText) || requests[0].Options.Model != "test-model" {
+			t.Errorf("Completion request doesn't carry the release and model: %+v", requests[0])
+		}
+	})
+
+	t.Run("Reasoning is kept with the publication record on request", func(t *testing.T) {
+		for _, keep := range []bool{false, true} {
+			want := ""
+			if keep {
+				want = "Lead with the monthly change."
+			}
+
+			env := newEventEnv()
+			env.OnActivity(GetPublicationActivity, mock.Anything, testEvent().Key()).Return(nil, nil)
+			env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return(llm.Response{Content: `{"tweet": "` + tweet + `"}`, Reasoning: "Lead with the monthly change."}, nil)
+			env.OnActivity(PostTweetActivity, mock.Anything, tweet, mock.Anything, true).Return("1234", nil)
+			env.OnActivity(RecordPublicationActivity, mock.Anything, mock.MatchedBy(func(r ledger.Record) bool {
+				return r.Reasoning == want
+			})).Return(nil)
+
+			env.ExecuteWorkflow(BLSEventSummaryWorkflow, EventWorkflowParams{
+				WorkflowParams: WorkflowParams{TweetForReal: true, KeepReasoning: keep},
+				Event:          testEvent(),
+			})
+
+			if err := env.GetWorkflowError(); err != nil {
+				t.Fatalf("KeepReasoning=%v: workflow returned an error: %v", keep, err)
+			}
+			env.AssertExpectations(t)
+
+			value, err := env.QueryWorkflow(DraftQuery)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var draft Draft
+			if err := value.Get(&draft); err != nil {
+				t.Fatal(err)
+			}
+			if draft.Reasoning != "Lead with the monthly change." {
+				t.Errorf("KeepReasoning=%v: expected the draft to show the reasoning, got %q", keep, draft.Reasoning)
+			}
+		}
+	})
+
+	t.Run("Tweet length is weighted the way X counts it", func(t *testing.T) {
+		// Under 280 characters, but emoji count 2 each
+		long := "CPI rose 0.4% in December " + strings.Repeat("📈", 200)
+
+		env := newEventEnv()
+		env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(llm.Response{Content: `{"tweet": "` + long + `"}`}, nil)
+		env.OnActivity(PostTweetActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+
+		env.ExecuteWorkflow(BLSEventSummaryWorkflow, EventWorkflowParams{Event: testEvent()})
+
+		err := env.GetWorkflowError()
+		if err == nil || !strings.Contains(err.Error(), "too long") {
+			t.Fatalf("Expected the tweet to be rejected as too long, got %v", err)
+		}
+		env.AssertActivityNotCalled(t, "PostTweetActivity", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Already published", func(t *testing.T) {
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestWorkflowEnvironment()
+		env.OnActivity(GetPublicationActivity, mock.Anything, mock.Anything).Return(&ledger.Record{Key: testEvent().Key(), Text: tweet}, nil)
+		env.OnActivity(WaitForReleaseActivity, mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+		env.OnActivity(PostTweetActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+
+		env.ExecuteWorkflow(BLSEventSummaryWorkflow, EventWorkflowParams{
+			WorkflowParams: WorkflowParams{TweetForReal: true},
+			Event:          testEvent(),
+		})
+
+		if err := env.GetWorkflowError(); err != nil {
+			t.Fatalf("Workflow returned an error: %v", err)
+		}
+		env.AssertActivityNotCalled(t, "WaitForReleaseActivity", mock.Anything, mock.Anything, mock.Anything)
+		env.AssertActivityNotCalled(t, "PostTweetActivity", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Headline figures are added to the summary", func(t *testing.T) {
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestWorkflowEnvironment()
+		env.RegisterActivity(FactCheckTweetActivity)
+		headline := &bls.CPIHeadline{Period: "Dec. 2024", AllItemsMoM: 0.4, AllItemsYoY: 2.9, CoreMoM: 0.2, CoreYoY: 3.2}
+		env.OnActivity(WaitForReleaseActivity, mock.Anything, mock.Anything, mock.Anything).Return("<pre>"+testReleaseText+"</pre>", nil)
+		env.OnActivity(ExtractHeadlineActivity, mock.Anything, mock.Anything, mock.Anything).Return(headline.Metrics(), nil)
+		env.OnActivity(ExtractSummaryActivity, mock.Anything, mock.Anything).Return(testReleaseText, nil)
+		env.OnActivity(CompareReleaseActivity, mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+		env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.MatchedBy(func(prompt string) bool {
+				return strings.Contains(prompt, testReleaseText+"\n\nHeadline figures:\n"+bls.FormatMetrics(headline.Metrics()))
+			}), mock.Anything).
+			Return(llm.Response{Content: `{"tweet": "` + tweet + `"}`}, nil)
+		env.OnActivity(PostTweetActivity, mock.Anything, tweet, mock.Anything, false).Return("", nil)
+
+		env.ExecuteWorkflow(BLSEventSummaryWorkflow, EventWorkflowParams{Event: testEvent()})
+
+		if err := env.GetWorkflowError(); err != nil {
+			t.Fatalf("Workflow returned an error: %v", err)
+		}
+		env.AssertExpectations(t)
+	})
+
+	t.Run("Fact check covers the release text and headline figures", func(t *testing.T) {
+		release := "The Consumer Price Index for All Urban Consumers (CPI-U) increased 0.4 percent on a seasonally adjusted basis in December. " +
+			"Over the last 12 months, the all items index increased 2.9 percent before seasonal adjustment."
+		tweet := "CPI rose 0.4% in December and 2.9% over the past 12 months."
+
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestWorkflowEnvironment()
+		env.RegisterActivity(FactCheckTweetActivity)
+		headline := &bls.CPIHeadline{Period: "Dec. 2024", AllItemsMoM: 0.4, AllItemsYoY: 2.9, CoreMoM: 0.2, CoreYoY: 3.2}
+		env.OnActivity(WaitForReleaseActivity, mock.Anything, mock.Anything, mock.Anything).Return("<pre>"+release+"</pre>", nil)
+		env.OnActivity(ExtractHeadlineActivity, mock.Anything, mock.Anything, mock.Anything).Return(headline.Metrics(), nil)
+		env.OnActivity(ExtractSummaryActivity, mock.Anything, mock.Anything).Return(release, nil)
+		env.OnActivity(CompareReleaseActivity, mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+		env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(llm.Response{Content: `{"tweet": "` + tweet + `"}`}, nil).Once()
+		env.OnActivity(PostTweetActivity, mock.Anything, tweet, mock.Anything, false).Return("", nil)
+
+		env.ExecuteWorkflow(BLSEventSummaryWorkflow, EventWorkflowParams{Event: testEvent()})
+
+		// "12" is only in the release text, not in the headline figures
+		if err := env.GetWorkflowError(); err != nil {
+			t.Fatalf("Workflow returned an error: %v", err)
+		}
+		env.AssertExpectations(t)
+	})
+
+	t.Run("Draft failing the fact check is regenerated", func(t *testing.T) {
+		env := newEventEnv()
+		env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.MatchedBy(func(prompt string) bool { return !strings.Contains(prompt, "previous draft") }), mock.Anything).
+			Return(llm.Response{Content: `{"tweet": "CPI rose 0.5% in December."}`}, nil).Once()
+		env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.MatchedBy(func(prompt string) bool { return strings.Contains(prompt, "(0.5%)") }), mock.Anything).
+			Return(llm.Response{Content: `{"tweet": "` + tweet + `"}`}, nil).Once()
+		env.OnActivity(PostTweetActivity, mock.Anything, tweet, mock.Anything, false).Return("", nil)
+
+		env.ExecuteWorkflow(BLSEventSummaryWorkflow, EventWorkflowParams{Event: testEvent()})
+
+		if err := env.GetWorkflowError(); err != nil {
+			t.Fatalf("Workflow returned an error: %v", err)
+		}
+		env.AssertExpectations(t)
+	})
+
+	t.Run("Comparison with the previous release reaches the prompt", func(t *testing.T) {
+		comparison := "- CPI all items, m/m: 0.4 percent in Dec. 2024, up from 0.3 in Nov. 2024"
+		tweet := "CPI rose 0.4% in December, up from 0.3% in November."
+
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestWorkflowEnvironment()
+		env.RegisterActivity(FactCheckTweetActivity)
+		env.OnActivity(WaitForReleaseActivity, mock.Anything, mock.Anything, mock.Anything).Return("<pre>"+testReleaseText+"</pre>", nil)
+		env.OnActivity(ExtractHeadlineActivity, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+		env.OnActivity(ExtractSummaryActivity, mock.Anything, mock.Anything).Return(testReleaseText, nil)
+		env.OnActivity(CompareReleaseActivity, mock.Anything, mock.Anything, mock.Anything).Return(comparison, nil)
+		env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.MatchedBy(func(prompt string) bool {
+				return strings.Contains(prompt, "Compared with the previous release:\n"+comparison)
+			}), mock.Anything).
+			Return(llm.Response{Content: `{"tweet": "` + tweet + `"}`}, nil).Once()
+		env.OnActivity(PostTweetActivity, mock.Anything, tweet, mock.Anything, false).Return("", nil)
+
+		env.ExecuteWorkflow(BLSEventSummaryWorkflow, EventWorkflowParams{Event: testEvent()})
+
+		// The previous month's figure passes the fact check because it's in the context
+		if err := env.GetWorkflowError(); err != nil {
+			t.Fatalf("Workflow returned an error: %v", err)
+		}
+		env.AssertExpectations(t)
+	})
+
+	t.Run("Failed comparison doesn't stop the tweet", func(t *testing.T) {
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestWorkflowEnvironment()
+		env.RegisterActivity(FactCheckTweetActivity)
+		env.OnActivity(WaitForReleaseActivity, mock.Anything, mock.Anything, mock.Anything).Return("<pre>"+testReleaseText+"</pre>", nil)
+		env.OnActivity(ExtractHeadlineActivity, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+		env.OnActivity(ExtractSummaryActivity, mock.Anything, mock.Anything).Return(testReleaseText, nil)
+		env.OnActivity(CompareReleaseActivity, mock.Anything, mock.Anything, mock.Anything).Return("", nonRetryable("disk full"))
+		env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.MatchedBy(func(prompt string) bool { return !strings.Contains(prompt, "Compared with") }), mock.Anything).
+			Return(llm.Response{Content: `{"tweet": "` + tweet + `"}`}, nil)
+		env.OnActivity(PostTweetActivity, mock.Anything, tweet, mock.Anything, false).Return("", nil)
+
+		env.ExecuteWorkflow(BLSEventSummaryWorkflow, EventWorkflowParams{Event: testEvent()})
+
+		if err := env.GetWorkflowError(); err != nil {
+			t.Fatalf("Workflow returned an error: %v", err)
+		}
+		env.AssertExpectations(t)
+	})
+
+	t.Run("Replay from the archive", func(t *testing.T) {
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestWorkflowEnvironment()
+		env.RegisterActivity(FactCheckTweetActivity)
+		env.OnActivity(FetchArchivedReleaseHTMLActivity, mock.Anything, mock.Anything).Return("<pre>"+testReleaseText+"</pre>", nil)
+		env.OnActivity(ExtractHeadlineActivity, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+		env.OnActivity(ExtractSummaryActivity, mock.Anything, mock.Anything).Return(testReleaseText, nil)
+		env.OnActivity(CompareReleaseActivity, mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+		env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(llm.Response{Content: `{"tweet": "` + tweet + `"}`}, nil)
+		env.OnActivity(PostTweetActivity, mock.Anything, tweet, mock.Anything, false).Return("", nil)
+
+		env.ExecuteWorkflow(BLSEventSummaryWorkflow, EventWorkflowParams{
+			WorkflowParams: WorkflowParams{FromArchive: true},
+			Event:          testEvent(),
+		})
+
+		if err := env.GetWorkflowError(); err != nil {
+			t.Fatalf("Workflow returned an error: %v", err)
+		}
+		env.AssertExpectations(t)
+		env.AssertActivityNotCalled(t, "WaitForReleaseActivity", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Archived releases are never posted for real", func(t *testing.T) {
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestWorkflowEnvironment()
+		env.OnActivity(FetchArchivedReleaseHTMLActivity, mock.Anything, mock.Anything).Return("<pre>"+testReleaseText+"</pre>", nil)
+
+		env.ExecuteWorkflow(BLSEventSummaryWorkflow, EventWorkflowParams{
+			WorkflowParams: WorkflowParams{FromArchive: true, TweetForReal: true},
+			Event:          testEvent(),
+		})
+
+		err := env.GetWorkflowError()
+		if err == nil || !strings.Contains(err.Error(), "can't be posted for real") {
+			t.Fatalf("Expected the workflow to refuse, got %v", err)
+		}
+		env.AssertActivityNotCalled(t, "FetchArchivedReleaseHTMLActivity", mock.Anything, mock.Anything)
+	})
+
+	failureCases := []struct {
+		name        string
+		setup       func(env *testsuite.TestWorkflowEnvironment)
+		params      WorkflowParams
+		expectedErr string
+	}{
+		{
+			name: "Failed HTML fetch",
+			setup: func(env *testsuite.TestWorkflowEnvironment) {
+				env.OnActivity(WaitForReleaseActivity, mock.Anything, mock.Anything, mock.Anything).Return("", nonRetryable("access forbidden"))
+			},
+			expectedErr: "failed to fetch release HTML",
+		},
+		{
+			name: "Release not archived",
+			setup: func(env *testsuite.TestWorkflowEnvironment) {
+				env.OnActivity(FetchArchivedReleaseHTMLActivity, mock.Anything, mock.Anything).
+					Return("", temporal.NewNonRetryableApplicationError("release not archived", "NotArchived", nil))
+			},
+			params:      WorkflowParams{FromArchive: true},
+			expectedErr: "failed to fetch archived release HTML",
+		},
+		{
+			name: "Late release",
+			setup: func(env *testsuite.TestWorkflowEnvironment) {
+				env.OnActivity(WaitForReleaseActivity, mock.Anything, mock.Anything, mock.Anything).
+					Return("", temporal.NewNonRetryableApplicationError("page still shows the release of 2024-12-11", ReleaseLateErrorType, nil))
+			},
+			expectedErr: "release is late",
+		},
+		{
+			name: "Malformed LLM JSON",
+			setup: func(env *testsuite.TestWorkflowEnvironment) {
+				env.OnActivity(WaitForReleaseActivity, mock.Anything, mock.Anything, mock.Anything).Return("<pre>"+testReleaseText+"</pre>", nil)
+				env.OnActivity(ExtractHeadlineActivity, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				env.OnActivity(ExtractSummaryActivity, mock.Anything, mock.Anything).Return(testReleaseText, nil)
+				env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(llm.Response{Content: `{"tweet": "CPI rose`}, nil)
+			},
+			expectedErr: "failed to unmarshal LLM response",
+		},
+		{
+			name: "Over-length tweet",
+			setup: func(env *testsuite.TestWorkflowEnvironment) {
+				env.OnActivity(WaitForReleaseActivity, mock.Anything, mock.Anything, mock.Anything).Return("<pre>"+testReleaseText+"</pre>", nil)
+				env.OnActivity(ExtractHeadlineActivity, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				env.OnActivity(ExtractSummaryActivity, mock.Anything, mock.Anything).Return(testReleaseText, nil)
+				env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(llm.Response{Content: `{"tweet": "` + strings.Repeat("a", 281) + `"}`}, nil)
+			},
+			expectedErr: "too long",
+		},
+		{
+			name: "Every draft fails the fact check",
+			setup: func(env *testsuite.TestWorkflowEnvironment) {
+				env.OnActivity(WaitForReleaseActivity, mock.Anything, mock.Anything, mock.Anything).Return("<pre>"+testReleaseText+"</pre>", nil)
+				env.OnActivity(ExtractHeadlineActivity, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				env.OnActivity(ExtractSummaryActivity, mock.Anything, mock.Anything).Return(testReleaseText, nil)
+				env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(llm.Response{Content: `{"tweet": "CPI rose 0.5% in March."}`}, nil).Times(defaultDraftAttempts)
+			},
+			expectedErr: "tweet failed fact check after 2 attempts",
+		},
+	}
+
+	for _, tc := range failureCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var suite testsuite.WorkflowTestSuite
+			env := suite.NewTestWorkflowEnvironment()
+			env.RegisterActivity(FactCheckTweetActivity)
+			tc.setup(env)
+			env.OnActivity(CompareReleaseActivity, mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+			env.OnActivity(PostTweetActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+
+			env.ExecuteWorkflow(BLSEventSummaryWorkflow, EventWorkflowParams{WorkflowParams: tc.params, Event: testEvent()})
+
+			err := env.GetWorkflowError()
+			if err == nil {
+				t.Fatal("Expected the workflow to fail")
+			}
+			if !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Errorf("Expected error containing '%s', but got: %v", tc.expectedErr, err)
+			}
+			env.AssertActivityNotCalled(t, "PostTweetActivity", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestBLSEventSummaryWorkflowOtherAgency(t *testing.T) {
+	// The fetch and extract activities run for real against a stand-in for
+	// bea.gov, since the event's URL is what routes it there
+	url := useReleaseServer(t, `<html><body><nav><p>Menu</p></nav><main>
+		<h1>GDP (Advance Estimate), 4th Quarter and Year 2024</h1>
+		<p>Real gross domestic product (GDP) increased at an annual rate of 2.3 percent in the fourth quarter of 2024.</p>
+		</main></body></html>`)
+	event := agencies.Event{
+		Agency:     agencies.AgencyBEA,
+		Series:     "Gross Domestic Product",
+		Title:      "GDP (Advance Estimate), 4th Quarter and Year 2024",
+		Start:      time.Date(2025, 1, 30, 13, 30, 0, 0, time.UTC),
+		ReleaseURL: url + "/data/gdp/gross-domestic-product",
+	}
+
+	tweet := "Real GDP grew at a 2.3% annual rate in the fourth quarter of 2024."
+	fake := llm.NewFakeCompleter().Reply(`{"tweet": "` + tweet + `"}`)
+	previous := completer
+	SetCompleter(fake)
+	t.Cleanup(func() { SetCompleter(previous) })
+
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.RegisterActivity(WaitForReleaseActivity)
+	env.RegisterActivity(ExtractSummaryActivity)
+	env.RegisterActivity(ExtractHeadlineActivity)
+	env.RegisterActivity(CompareReleaseActivity)
+	env.RegisterActivity(CompleteWithReasoningActivity)
+	env.RegisterActivity(FactCheckTweetActivity)
+	env.OnActivity(PostTweetActivity, mock.Anything, tweet, mock.Anything, false).Return("", nil)
+
+	env.ExecuteWorkflow(BLSEventSummaryWorkflow, EventWorkflowParams{
+		Event:  event.BLSEvent(),
+		Agency: agencies.AgencyName(event.Agency),
+	})
+
+	if err := env.GetWorkflowError(); err != nil {
+		t.Fatalf("Workflow returned an error: %v", err)
+	}
+	env.AssertExpectations(t)
+	requests := fake.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("Expected one completion, got %d", len(requests))
+	}
+	if !strings.Contains(requests[0].UserPrompt, "annual rate of 2.3 percent") || strings.Contains(requests[0].UserPrompt, "Menu") {
+		t.Errorf("Completion prompt doesn't carry the release text alone: %s", requests[0].UserPrompt)
+	}
+	if !strings.Contains(requests[0].SystemPrompt, "Bureau of Economic Analysis") || strings.Contains(requests[0].SystemPrompt, "BLS") {
+		t.Errorf("System prompt isn't about the event's agency: %s", requests[0].SystemPrompt)
+	}
+}
+
+func TestBLSEventSummaryWorkflowApproval(t *testing.T) {
+	tweet := "CPI rose 0.4% in December."
+
+	testCases := []struct {
+		name       string
+		decision   *ApprovalDecision
+		wantPosted string
+		wantStatus DraftStatus
+	}{
+		{
+			name:       "Approve",
+			decision:   &ApprovalDecision{Action: ApprovalApprove, Reviewer: "alex"},
+			wantPosted: tweet,
+			wantStatus: DraftStatusPosted,
+		},
+		{
+			name:       "Edit",
+			decision:   &ApprovalDecision{Action: ApprovalEdit, Text: "CPI up 0.4% m/m in December.", Reviewer: "alex"},
+			wantPosted: "CPI up 0.4% m/m in December.",
+			wantStatus: DraftStatusPosted,
+		},
+		{
+			// Under 280 characters, but emoji count 2 each
+			name:       "Edit over the weighted length is ignored",
+			decision:   &ApprovalDecision{Action: ApprovalEdit, Text: "CPI up 0.4% m/m in December " + strings.Repeat("📈", 200), Reviewer: "alex"},
+			wantStatus: DraftStatusTimedOut,
+		},
+		{
+			name:       "Reject",
+			decision:   &ApprovalDecision{Action: ApprovalReject, Reviewer: "alex"},
+			wantStatus: DraftStatusRejected,
+		},
+		{
+			name:       "Timeout",
+			wantStatus: DraftStatusTimedOut,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newEventEnv()
+			env.OnActivity(CompleteWithReasoningActivity, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return(llm.Response{Content: `{"tweet": "` + tweet + `"}`}, nil)
+			env.OnActivity(PostTweetActivity, mock.Anything, mock.Anything, mock.Anything, false).Return("", nil)
+
+			env.RegisterDelayedCallback(func() {
+				value, err := env.QueryWorkflow(DraftQuery)
+				if err != nil {
+					t.Errorf("QueryWorkflow() returned an error: %v", err)
+					return
+				}
+				var draft Draft
+				if err := value.Get(&draft); err != nil {
+					t.Errorf("Failed to decode draft: %v", err)
+					return
+				}
+				if draft.Status != DraftStatusPending || draft.Text != tweet {
+					t.Errorf("Expected a pending draft with the generated tweet, got %+v", draft)
+				}
+
+				if tc.decision != nil {
+					env.SignalWorkflow(ApprovalSignal, *tc.decision)
+				}
+			}, time.Minute)
+
+			env.ExecuteWorkflow(BLSEventSummaryWorkflow, EventWorkflowParams{
+				WorkflowParams: WorkflowParams{RequireApproval: true, ApprovalTimeout: time.Hour},
+				Event:          testEvent(),
+			})
+
+			if err := env.GetWorkflowError(); err != nil {
+				t.Fatalf("Workflow returned an error: %v", err)
+			}
+
+			var got string
+			if err := env.GetWorkflowResult(&got); err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.wantPosted {
+				t.Errorf("Workflow result = %q, want %q", got, tc.wantPosted)
+			}
+
+			if tc.wantPosted == "" {
+				env.AssertActivityNotCalled(t, "PostTweetActivity", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				env.AssertActivityNumberOfCalls(t, "PostTweetActivity", 1)
+			}
+
+			value, err := env.QueryWorkflow(DraftQuery)
+			if err != nil {
+				t.Fatalf("QueryWorkflow() returned an error: %v", err)
+			}
+			var draft Draft
+			if err := value.Get(&draft); err != nil {
+				t.Fatal(err)
+			}
+			if draft.Status != tc.wantStatus {
+				t.Errorf("Draft status = %q, want %q", draft.Status, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestBLSReleaseSummaryWorkflow(t *testing.T) {
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+
+	cpi := testEvent()
+	ppiStart := cpi.Start.Add(24 * time.Hour)
+	ppi := bls.Event{Summary: "Producer Price Index", UID: "ppi-2025-01@bls.gov", Start: &ppiStart}
+
+	env.OnActivity(FindEventsActivity, mock.Anything, 60.0).Return([]bls.Event{cpi, ppi}, nil)
+	env.RegisterWorkflow(BLSEventSummaryWorkflow)
+	env.OnWorkflow(BLSEventSummaryWorkflow, mock.Anything, mock.MatchedBy(func(p EventWorkflowParams) bool {
+		return p.Event.UID == cpi.UID
+	})).Return("CPI tweet", nil)
+	env.OnWorkflow(BLSEventSummaryWorkflow, mock.Anything, mock.MatchedBy(func(p EventWorkflowParams) bool {
+		return p.Event.UID == ppi.UID
+	})).Return("", nonRetryable("no valid tweet generated"))
+
+	env.ExecuteWorkflow(BLSReleaseSummaryWorkflow, WorkflowParams{Mins: 60})
+
+	if err := env.GetWorkflowError(); err != nil {
+		t.Fatalf("Workflow returned an error: %v", err)
+	}
+	var got []string
+	if err := env.GetWorkflowResult(&got); err != nil {
+		t.Fatal(err)
+	}
+	// A failed event doesn't stop the others from being posted
+	if len(got) != 1 || got[0] != "CPI tweet" {
+		t.Errorf("Workflow result = %v, want [CPI tweet]", got)
+	}
+}
+
+func TestBLSReleaseSummaryWorkflowRunsEventsInParallel(t *testing.T) {
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	env.SetStartTime(now)
+
+	cpi := testEvent()
+	ppiStart := cpi.Start.Add(24 * time.Hour)
+	ppi := bls.Event{Summary: "Producer Price Index", UID: "ppi-2025-01@bls.gov", Start: &ppiStart}
+	env.OnActivity(FindEventsActivity, mock.Anything, 60.0).Return([]bls.Event{cpi, ppi}, nil)
+
+	// Each draft waits an hour for a reviewer
+	started := make(map[string]time.Time)
+	env.RegisterWorkflow(BLSEventSummaryWorkflow)
+	env.OnWorkflow(BLSEventSummaryWorkflow, mock.Anything, mock.Anything).Return(
+		func(ctx workflow.Context, p EventWorkflowParams) (string, error) {
+			started[p.Event.UID] = workflow.Now(ctx)
+			if err := workflow.Sleep(ctx, time.Hour); err != nil {
+				return "", err
+			}
+			return p.Event.Summary + " tweet", nil
+		})
+
+	env.ExecuteWorkflow(BLSReleaseSummaryWorkflow, WorkflowParams{Mins: 60, RequireApproval: true})
+
+	if err := env.GetWorkflowError(); err != nil {
+		t.Fatalf("Workflow returned an error: %v", err)
+	}
+	var got []string
+	if err := env.GetWorkflowResult(&got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != "Consumer Price Index tweet" || got[1] != "Producer Price Index tweet" {
+		t.Errorf("Workflow result = %v, want both tweets in event order", got)
+	}
+	if wait := started[ppi.UID].Sub(started[cpi.UID]); wait >= time.Hour {
+		t.Errorf("Second event started %v after the first, want it not to wait for the first approval", wait)
+	}
+}
+
+func TestEventChildOptions(t *testing.T) {
+	opts := eventChildOptions(testEvent(), true)
+	if opts.WorkflowID != "bls-event-"+testEvent().Key() {
+		t.Errorf("WorkflowID = %q", opts.WorkflowID)
+	}
+	// A failed run may have failed after posting, so it must never run again
+	if opts.WorkflowIDReusePolicy != enumspb.WORKFLOW_ID_REUSE_POLICY_REJECT_DUPLICATE {
+		t.Errorf("WorkflowIDReusePolicy = %v", opts.WorkflowIDReusePolicy)
+	}
+}
+
+// mockCalendar serves FindScheduledEventsActivity from a calendar that the test
+// can change while the scheduler runs.
+func mockCalendar(env *testsuite.TestWorkflowEnvironment, calendar *[]bls.Event) {
+	env.OnActivity(FindScheduledEventsActivity, mock.Anything, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, after, until time.Time) ([]bls.Event, error) {
+			return bls.EventsBetween(*calendar, after, until), nil
+		})
+}
+
+// recordStarts mocks the event workflow and records when each event was started.
+func recordStarts(env *testsuite.TestWorkflowEnvironment) map[string]time.Time {
+	started := make(map[string]time.Time)
+	env.RegisterWorkflow(BLSEventSummaryWorkflow)
+	env.OnWorkflow(BLSEventSummaryWorkflow, mock.Anything, mock.Anything).Return(
+		func(ctx workflow.Context, p EventWorkflowParams) (string, error) {
+			started[p.Event.UID] = workflow.Now(ctx)
+			return "tweet", nil
+		})
+	return started
+}
+
+func TestBLSReleaseSchedulerWorkflow(t *testing.T) {
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+
+	now := time.Date(2025, 1, 15, 6, 0, 0, 0, time.UTC)
+	env.SetStartTime(now)
+
+	past := now.Add(-time.Hour)
+	soon := now.Add(2*time.Hour + 30*time.Minute)
+	later := now.Add(48 * time.Hour)
+	calendar := []bls.Event{
+		{Summary: "Real Earnings", UID: "later", Start: &later},
+		{Summary: "Consumer Price Index", UID: "soon", Start: &soon},
+		{Summary: "Employment Situation", UID: "past", Start: &past},
+	}
+	mockCalendar(env, &calendar)
+	started := recordStarts(env)
+
+	env.ExecuteWorkflow(BLSReleaseSchedulerWorkflow, SchedulerParams{})
+
+	if !workflow.IsContinueAsNewError(env.GetWorkflowError()) {
+		t.Fatalf("Expected the scheduler to continue as new, got %v", env.GetWorkflowError())
+	}
+	// Only the event inside the refresh window is started, after the default delay
+	if len(started) != 1 {
+		t.Errorf("Started %d event workflows, want 1: %v", len(started), started)
+	}
+	if want := soon.Add(defaultStartDelay); !started["soon"].Equal(want) {
+		t.Errorf("Event workflow started at %v, want %v", started["soon"], want)
+	}
+}
+
+func TestBLSReleaseSchedulerWorkflowCalendarChanges(t *testing.T) {
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+
+	now := time.Date(2025, 1, 15, 6, 0, 0, 0, time.UTC)
+	env.SetStartTime(now)
+
+	cpi := now.Add(3 * time.Hour)
+	ppi := now.Add(4 * time.Hour)
+	calendar := []bls.Event{
+		{Summary: "Consumer Price Index", UID: "cpi", Start: &cpi},
+		{Summary: "Producer Price Index", UID: "ppi", Start: &ppi},
+	}
+	mockCalendar(env, &calendar)
+	started := recordStarts(env)
+
+	// Before the first hourly check, CPI moves, PPI is cancelled and JOLTS is added
+	movedCPI := now.Add(5 * time.Hour)
+	jolts := now.Add(6 * time.Hour)
+	env.RegisterDelayedCallback(func() {
+		calendar = []bls.Event{
+			{Summary: "Consumer Price Index", UID: "cpi", Start: &movedCPI},
+			{Summary: "Job Openings and Labor Turnover Survey", UID: "jolts", Start: &jolts},
+		}
+	}, 30*time.Minute)
+
+	env.ExecuteWorkflow(BLSReleaseSchedulerWorkflow, SchedulerParams{})
+
+	if !workflow.IsContinueAsNewError(env.GetWorkflowError()) {
+		t.Fatalf("Expected the scheduler to continue as new, got %v", env.GetWorkflowError())
+	}
+
+	want := map[string]time.Time{
+		"cpi":   movedCPI.Add(defaultStartDelay),
+		"jolts": jolts.Add(defaultStartDelay),
+	}
+	if len(started) != len(want) {
+		t.Errorf("Started %v, want %v", started, want)
+	}
+	for uid, at := range want {
+		if !started[uid].Equal(at) {
+			t.Errorf("%s started at %v, want %v", uid, started[uid], at)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Package completion holds what the workflow packages share to ask an LLM for
+// structured output: picking the completer in the activity, turning its errors
+// into Temporal application errors and decoding the response in the workflow.
+package completion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gflarity/bls_agent/internal/credentials"
+	"github.com/gflarity/bls_agent/pkg/llm"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Application error types completion activities fail with, so workflows can
+// tell a model that gave no answer or kept breaking the schema from an API that
+// couldn't be reached or credentials that couldn't be loaded.
+const (
+	TransportErrorType   = "CompletionTransport"
+	RefusedErrorType     = "CompletionRefused"
+	InvalidErrorType     = "CompletionInvalid"
+	CredentialsErrorType = "CompletionCredentials"
+)
+
+const (
+	// requestTimeout bounds each request to the API, so a request that hangs is
+	// retried instead of holding the activity until it times out.
+	requestTimeout = 3 * time.Minute
+	// activityAttempts is how many times Temporal runs a completion activity,
+	// e.g. after a worker restart or a Retry-After too long to wait out inside it.
+	activityAttempts = 3
+	// activityRetryWait is the longest wait between activity attempts the
+	// schedule-to-close timeout leaves room for.
+	activityRetryWait = 5 * time.Minute
+)
+
+// openAICompleters keeps one client per API key and base URL between calls.
+var openAICompleters llm.OpenAICompleters
+
+// For returns the completer for a credential profile and base URL. Responses
+// are checked against the schema and repaired when they break it, and transient
+// API failures are retried. Every request heartbeats the activity first. When
+// override is set it answers in place of the OpenAI-compatible API, e.g. an
+// llm.FakeCompleter in tests. Credentials that can't be loaded fail with a
+// non-retryable CredentialsErrorType error, since retrying won't find them.
+func For(override llm.Completer, provider credentials.Provider, credentialProfile, baseURL string) (llm.Completer, error) {
+	if override != nil {
+		return llm.NewValidatingCompleter(override), nil
+	}
+	creds, err := credentials.LoadOpenAI(provider, credentialProfile)
+	if err != nil {
+		return nil, temporal.NewNonRetryableApplicationError(fmt.Sprintf("failed to load OpenAI credentials: %v", err), CredentialsErrorType, err)
+	}
+	api := llm.NewRetryingCompleter(heartbeatCompleter{openAICompleters.Get(creds.APIKey, baseURL)})
+	return llm.NewValidatingCompleter(api), nil
+}
+
+// heartbeatCompleter heartbeats the activity before every request and limits
+// the request to requestTimeout. Wrapped in a RetryingCompleter it heartbeats
+// between the waits of the retry loop.
+type heartbeatCompleter struct {
+	llm.Completer
+}
+
+// Complete implements llm.Completer.
+func (c heartbeatCompleter) Complete(ctx context.Context, req llm.Request) (llm.Response, error) {
+	activity.RecordHeartbeat(ctx)
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	return c.Completer.Complete(ctx, req)
+}
+
+// activityOptions returns ctx's activity options with timeouts that fit every
+// request For's completer can make: the first completion and each repair,
+// tried llm.DefaultRetryAttempts times with the longest backoff in between.
+// The heartbeat timeout covers one request and one wait. Temporal runs the
+// activity at most activityAttempts times, and the schedule-to-close timeout
+// covers all of them.
+func activityOptions(ctx workflow.Context) workflow.ActivityOptions {
+	completions := 1 + llm.DefaultRepairAttempts
+	requests := completions * llm.DefaultRetryAttempts
+	waits := completions * (llm.DefaultRetryAttempts - 1)
+
+	opts := workflow.GetActivityOptions(ctx)
+	opts.StartToCloseTimeout = time.Duration(requests)*requestTimeout + time.Duration(waits)*llm.DefaultRetryMaxDelay
+	opts.ScheduleToCloseTimeout = activityAttempts*opts.StartToCloseTimeout + (activityAttempts-1)*activityRetryWait
+	opts.HeartbeatTimeout = requestTimeout + llm.DefaultRetryMaxDelay + time.Minute
+	opts.RetryPolicy = &temporal.RetryPolicy{MaximumAttempts: activityAttempts}
+	return opts
+}
+
+// Error wraps a completer error in an application error of its type. Errors
+// that fail the same way every time, such as bad credentials or a prompt over
+// the context length, are non-retryable, and a Retry-After from the API sets
+// the delay before Temporal's next attempt.
+func Error(err error) error {
+	msg := fmt.Sprintf("failed to complete with schema: %v", err)
+	var transportErr *llm.TransportError
+	var refusalErr *llm.RefusalError
+	var schemaErr *llm.SchemaError
+	var errType string
+	switch {
+	case errors.As(err, &transportErr):
+		errType = TransportErrorType
+	case errors.As(err, &refusalErr):
+		errType = RefusedErrorType
+	case errors.As(err, &schemaErr), errors.Is(err, llm.ErrInvalidSchema):
+		errType = InvalidErrorType
+	default:
+		return fmt.Errorf("failed to complete with schema: %w", err)
+	}
+
+	if !llm.IsRetryable(err) {
+		return temporal.NewNonRetryableApplicationError(msg, errType, err)
+	}
+	delay, _ := llm.RetryAfter(err)
+	return temporal.NewApplicationErrorWithOptions(msg, errType, temporal.ApplicationErrorOptions{
+		Cause:          err,
+		NextRetryDelay: delay,
+	})
+}
+
+// Into runs a completion activity with T's schema and decodes the response
+// into T. The activity takes the credential profile, base URL, schema, system
+// prompt, user prompt and model, and returns an llm.Response. Its timeouts are
+// set to fit the completer's retries. Into also returns the model's reasoning,
+// which is empty for models that don't report it. A response that doesn't
+// match the schema fails with an *llm.SchemaError; activity failures are
+// returned as they are.
+func Into[T any](ctx workflow.Context, activity interface{}, credentialProfile, baseURL, model, systemPrompt, userPrompt string) (T, string, error) {
+	var zero T
+	schema, err := llm.SchemaFor[T]()
+	if err != nil {
+		return zero, "", fmt.Errorf("failed to generate schema: %w", err)
+	}
+
+	ctx = workflow.WithActivityOptions(ctx, activityOptions(ctx))
+	var resp llm.Response
+	err = workflow.ExecuteActivity(ctx, activity, credentialProfile, baseURL, schema, systemPrompt, userPrompt, model).Get(ctx, &resp)
+	if err != nil {
+		return zero, "", err
+	}
+	v, err := llm.Decode[T](resp.Content)
+	if err != nil {
+		return zero, "", err
+	}
+	return v, resp.Reasoning, nil
+}
@@ -0,0 +1,536 @@
+package bls
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAPIBaseURL is the BLS Public Data API v2.
+	DefaultAPIBaseURL = "https://api.bls.gov/publicAPI/v2"
+	// DefaultAPIInterval spaces out requests to stay under the API's limit of
+	// 50 requests per 10 seconds.
+	DefaultAPIInterval = 200 * time.Millisecond
+	// DefaultAPIRetries is how many times a request is retried after a 429.
+	DefaultAPIRetries = 3
+
+	// timeSeriesPath is the path of the time series endpoint relative to the base URL.
+	timeSeriesPath = "/timeseries/data/"
+
+	// The API caps how many series and years a single request may ask for. The
+	// caps are lower without a registration key.
+	maxSeriesRegistered   = 50
+	maxSeriesUnregistered = 25
+	maxYearsRegistered    = 20
+	maxYearsUnregistered  = 10
+)
+
+// ErrRateLimited is returned when the API refuses a request because a rate or
+// daily limit was exceeded.
+var ErrRateLimited = errors.New("BLS API rate limit exceeded")
+
+// APIError is returned when the API doesn't process a request.
+type APIError struct {
+	Status   string
+	Messages []string
+}
+
+func (e *APIError) Error() string {
+	if len(e.Messages) == 0 {
+		return fmt.Sprintf("BLS API request failed: %s", e.Status)
+	}
+	return fmt.Sprintf("BLS API request failed: %s: %s", e.Status, strings.Join(e.Messages, "; "))
+}
+
+// Is reports API errors about exceeded thresholds as ErrRateLimited.
+func (e *APIError) Is(target error) bool {
+	if target != ErrRateLimited {
+		return false
+	}
+	for _, msg := range e.Messages {
+		if strings.Contains(strings.ToLower(msg), "threshold") {
+			return true
+		}
+	}
+	return false
+}
+
+// APIClient queries the BLS Public Data API v2. The zero value is not usable;
+// create one with NewAPIClient.
+type APIClient struct {
+	// HTTPClient sends the requests.
+	HTTPClient *http.Client
+	// BaseURL is the API root, e.g. an httptest server's URL in tests.
+	BaseURL string
+	// RegistrationKey is optional. Registered requests may ask for more series
+	// and years at a time, and get catalog data and calculations.
+	RegistrationKey string
+	// UserAgent is sent with every request.
+	UserAgent string
+	// Timeout bounds each request. Zero means no timeout beyond the HTTPClient's own.
+	Timeout time.Duration
+	// MinInterval is the least time between the start of two requests.
+	MinInterval time.Duration
+	// MaxRetries is how many times a request is retried when the API answers
+	// 429 Too Many Requests.
+	MaxRetries int
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// NewAPIClient returns an APIClient for the public API. The registration key
+// may be empty.
+func NewAPIClient(registrationKey string) *APIClient {
+	return &APIClient{
+		HTTPClient:      &http.Client{Timeout: DefaultTimeout},
+		BaseURL:         DefaultAPIBaseURL,
+		RegistrationKey: registrationKey,
+		UserAgent:       "bls_agent",
+		Timeout:         DefaultTimeout,
+		MinInterval:     DefaultAPIInterval,
+		MaxRetries:      DefaultAPIRetries,
+	}
+}
+
+// SeriesRequest describes the series and years to fetch.
+type SeriesRequest struct {
+	SeriesIDs []string
+	// StartYear and EndYear bound the request. When both are zero the API
+	// returns its default span of recent years; setting only one is an error.
+	StartYear int
+	EndYear   int
+	// Catalog, Calculations and AnnualAverage ask for the series catalog, the
+	// net and percent changes of each observation, and annual averages. They
+	// need a registration key.
+	Catalog       bool
+	Calculations  bool
+	AnnualAverage bool
+}
+
+// TimeSeries is a series returned by the API, with its observations ordered
+// from oldest to newest.
+type TimeSeries struct {
+	ID           string         `json:"id"`
+	Catalog      *SeriesCatalog `json:"catalog,omitempty"`
+	Observations []Observation  `json:"observations"`
+}
+
+// SeriesCatalog describes a series. Only returned for registered requests.
+type SeriesCatalog struct {
+	Title              string `json:"series_title"`
+	SeriesID           string `json:"series_id"`
+	Seasonality        string `json:"seasonality"`
+	SurveyName         string `json:"survey_name"`
+	SurveyAbbreviation string `json:"survey_abbreviation"`
+	MeasureDataType    string `json:"measure_data_type"`
+	Area               string `json:"area"`
+	Item               string `json:"item"`
+}
+
+// Observation is a single reading of a series.
+type Observation struct {
+	Year int `json:"year"`
+	// Period is the API's period code, e.g. "M01" for January, "Q04" for the
+	// fourth quarter or "M13" for the annual average.
+	Period     string  `json:"period"`
+	PeriodName string  `json:"period_name"`
+	Value      float64 `json:"value"`
+	// Latest marks the most recent observation of the series.
+	Latest      bool     `json:"latest,omitempty"`
+	Preliminary bool     `json:"preliminary,omitempty"`
+	Footnotes   []string `json:"footnotes,omitempty"`
+	// NetChanges and PctChanges are keyed by the span in months, e.g. 12 for
+	// the change over the year. Only set when calculations were requested.
+	NetChanges map[int]float64 `json:"net_changes,omitempty"`
+	PctChanges map[int]float64 `json:"pct_changes,omitempty"`
+}
+
+// AnnualAverage reports whether the observation is an annual average rather
+// than a reading for a single period.
+func (o Observation) AnnualAverage() bool {
+	return o.Period == "M13" || o.Period == "A01"
+}
+
+// Date returns the first day of the observation's period.
+func (o Observation) Date() time.Time {
+	month := 1
+	if len(o.Period) == 3 {
+		n, err := strconv.Atoi(o.Period[1:])
+		if err == nil {
+			switch o.Period[0] {
+			case 'M':
+				if n >= 1 && n <= 12 {
+					month = n
+				}
+			case 'Q':
+				if n >= 1 && n <= 4 {
+					month = 3*(n-1) + 1
+				}
+			case 'S':
+				if n == 2 {
+					month = 7
+				}
+			}
+		}
+	}
+	return time.Date(o.Year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Label returns the observation's period for people, e.g. "December 2024".
+func (o Observation) Label() string {
+	if o.PeriodName == "" {
+		return fmt.Sprintf("%s %d", o.Period, o.Year)
+	}
+	return fmt.Sprintf("%s %d", o.PeriodName, o.Year)
+}
+
+// Latest returns the newest observation that isn't an annual average.
+func (s TimeSeries) Latest() (Observation, bool) {
+	for i := len(s.Observations) - 1; i >= 0; i-- {
+		if !s.Observations[i].AnnualAverage() {
+			return s.Observations[i], true
+		}
+	}
+	return Observation{}, false
+}
+
+// MaxSince returns the highest reading from year onwards, ignoring annual
+// averages. Ties go to the most recent reading.
+func (s TimeSeries) MaxSince(year int) (Observation, bool) {
+	var highest Observation
+	found := false
+	for _, o := range s.Observations {
+		if o.Year < year || o.AnnualAverage() {
+			continue
+		}
+		if !found || o.Value >= highest.Value {
+			highest = o
+			found = true
+		}
+	}
+	return highest, found
+}
+
+// HighestSince returns the most recent earlier reading at least as high as the
+// latest one, for statements like "the highest since March 2023". It returns
+// false when the latest reading is the highest in the series.
+func (s TimeSeries) HighestSince() (Observation, bool) {
+	latest, ok := s.Latest()
+	if !ok {
+		return Observation{}, false
+	}
+	for i := len(s.Observations) - 1; i >= 0; i-- {
+		o := s.Observations[i]
+		if o.AnnualAverage() || !o.Date().Before(latest.Date()) {
+			continue
+		}
+		if o.Value >= latest.Value {
+			return o, true
+		}
+	}
+	return Observation{}, false
+}
+
+// apiRequest is the body of a time series request.
+type apiRequest struct {
+	SeriesIDs       []string `json:"seriesid"`
+	StartYear       string   `json:"startyear,omitempty"`
+	EndYear         string   `json:"endyear,omitempty"`
+	Catalog         bool     `json:"catalog,omitempty"`
+	Calculations    bool     `json:"calculations,omitempty"`
+	AnnualAverage   bool     `json:"annualaverage,omitempty"`
+	RegistrationKey string   `json:"registrationkey,omitempty"`
+}
+
+// apiResponse is the body of a time series response.
+type apiResponse struct {
+	Status  string   `json:"status"`
+	Message []string `json:"message"`
+	Results struct {
+		Series []struct {
+			SeriesID string         `json:"seriesID"`
+			Catalog  *SeriesCatalog `json:"catalog"`
+			Data     []struct {
+				Year       string `json:"year"`
+				Period     string `json:"period"`
+				PeriodName string `json:"periodName"`
+				Latest     string `json:"latest"`
+				Value      string `json:"value"`
+				Footnotes  []struct {
+					Code string `json:"code"`
+					Text string `json:"text"`
+				} `json:"footnotes"`
+				Calculations struct {
+					NetChanges map[string]string `json:"net_changes"`
+					PctChanges map[string]string `json:"pct_changes"`
+				} `json:"calculations"`
+			} `json:"data"`
+		} `json:"series"`
+	} `json:"Results"`
+}
+
+// TimeSeries fetches the requested series. Requests for more series or years
+// than the API allows at once are split up and the results merged, so each
+// series is returned once, in the order requested. Observations whose value
+// isn't a number, such as "-" for missing data, are skipped.
+func (c *APIClient) TimeSeries(ctx context.Context, req SeriesRequest) ([]TimeSeries, error) {
+	if len(req.SeriesIDs) == 0 {
+		return nil, errors.New("no series requested")
+	}
+	if (req.StartYear == 0) != (req.EndYear == 0) {
+		return nil, fmt.Errorf("start year %d and end year %d must both be set or both be zero", req.StartYear, req.EndYear)
+	}
+	if req.EndYear < req.StartYear {
+		return nil, fmt.Errorf("end year %d is before start year %d", req.EndYear, req.StartYear)
+	}
+
+	maxSeries, maxYears := maxSeriesUnregistered, maxYearsUnregistered
+	if c.RegistrationKey != "" {
+		maxSeries, maxYears = maxSeriesRegistered, maxYearsRegistered
+	}
+
+	series := make(map[string]*TimeSeries, len(req.SeriesIDs))
+	for _, id := range req.SeriesIDs {
+		series[id] = &TimeSeries{ID: id}
+	}
+
+	for _, years := range yearWindows(req.StartYear, req.EndYear, maxYears) {
+		for start := 0; start < len(req.SeriesIDs); start += maxSeries {
+			end := min(start+maxSeries, len(req.SeriesIDs))
+			body := apiRequest{
+				SeriesIDs:       req.SeriesIDs[start:end],
+				Catalog:         req.Catalog,
+				Calculations:    req.Calculations,
+				AnnualAverage:   req.AnnualAverage,
+				RegistrationKey: c.RegistrationKey,
+			}
+			if years[0] != 0 {
+				body.StartYear = strconv.Itoa(years[0])
+				body.EndYear = strconv.Itoa(years[1])
+			}
+
+			resp, err := c.post(ctx, body)
+			if err != nil {
+				return nil, err
+			}
+			if err := mergeSeries(series, resp); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	results := make([]TimeSeries, 0, len(req.SeriesIDs))
+	for _, id := range req.SeriesIDs {
+		s := series[id]
+		sort.SliceStable(s.Observations, func(i, j int) bool {
+			a, b := s.Observations[i], s.Observations[j]
+			if a.Year != b.Year {
+				return a.Year < b.Year
+			}
+			return a.Period < b.Period
+		})
+		results = append(results, *s)
+	}
+	return results, nil
+}
+
+// yearWindows splits [start, end] into spans of at most size years. Unbounded
+// requests are a single zero window.
+func yearWindows(start, end, size int) [][2]int {
+	if start == 0 && end == 0 {
+		return [][2]int{{0, 0}}
+	}
+	var windows [][2]int
+	for from := start; from <= end; from += size {
+		windows = append(windows, [2]int{from, min(from+size-1, end)})
+	}
+	return windows
+}
+
+// mergeSeries adds the observations in resp to the series they belong to.
+func mergeSeries(series map[string]*TimeSeries, resp *apiResponse) error {
+	for _, rs := range resp.Results.Series {
+		s, ok := series[rs.SeriesID]
+		if !ok {
+			continue
+		}
+		if s.Catalog == nil {
+			s.Catalog = rs.Catalog
+		}
+		for _, d := range rs.Data {
+			year, err := strconv.Atoi(d.Year)
+			if err != nil {
+				return fmt.Errorf("invalid year %q for series %s", d.Year, rs.SeriesID)
+			}
+			value, err := strconv.ParseFloat(strings.ReplaceAll(d.Value, ",", ""), 64)
+			if err != nil {
+				continue
+			}
+
+			o := Observation{
+				Year:       year,
+				Period:     d.Period,
+				PeriodName: d.PeriodName,
+				Value:      value,
+				Latest:     d.Latest == "true",
+				NetChanges: parseChanges(d.Calculations.NetChanges),
+				PctChanges: parseChanges(d.Calculations.PctChanges),
+			}
+			for _, f := range d.Footnotes {
+				if f.Code == "P" {
+					o.Preliminary = true
+				}
+				if f.Text != "" {
+					o.Footnotes = append(o.Footnotes, f.Text)
+				}
+			}
+			s.Observations = append(s.Observations, o)
+		}
+	}
+	return nil
+}
+
+// parseChanges converts calculations keyed by span to numbers, dropping the
+// ones that aren't.
+func parseChanges(changes map[string]string) map[int]float64 {
+	if len(changes) == 0 {
+		return nil
+	}
+	parsed := make(map[int]float64, len(changes))
+	for span, value := range changes {
+		months, err := strconv.Atoi(span)
+		if err != nil {
+			continue
+		}
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			continue
+		}
+		parsed[months] = v
+	}
+	return parsed
+}
+
+// post sends one time series request, waiting for the rate limit and retrying
+// on 429 Too Many Requests.
+func (c *APIClient) post(ctx context.Context, body apiRequest) (*apiResponse, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := c.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		status, retryAfter, data, err := c.send(ctx, payload)
+		if err != nil {
+			return nil, err
+		}
+
+		if status == http.StatusTooManyRequests {
+			if attempt >= c.MaxRetries {
+				return nil, fmt.Errorf("%w: still limited after %d retries", ErrRateLimited, attempt)
+			}
+			if retryAfter < 0 {
+				retryAfter = time.Second << attempt
+			}
+			if err := sleep(ctx, retryAfter); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("BLS API returned status %d", status)
+		}
+
+		var resp apiResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, fmt.Errorf("failed to decode BLS API response: %w", err)
+		}
+		if resp.Status != "REQUEST_SUCCEEDED" {
+			return nil, &APIError{Status: resp.Status, Messages: resp.Message}
+		}
+		return &resp, nil
+	}
+}
+
+// send posts payload and returns the status, the Retry-After delay, or -1 when
+// there is none, and the body.
+func (c *APIClient) send(ctx context.Context, payload []byte) (int, time.Duration, []byte, error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	url := strings.TrimRight(c.BaseURL, "/") + timeSeriesPath
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.UserAgent)
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("failed to query BLS API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	retryAfter := time.Duration(-1)
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return 0, 0, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return resp.StatusCode, retryAfter, buf.Bytes(), nil
+}
+
+// wait blocks until the client may send its next request.
+func (c *APIClient) wait(ctx context.Context) error {
+	c.mu.Lock()
+	now := time.Now()
+	at := c.next
+	if at.Before(now) {
+		at = now
+	}
+	c.next = at.Add(c.MinInterval)
+	c.mu.Unlock()
+
+	return sleep(ctx, time.Until(at))
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
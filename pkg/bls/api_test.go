@@ -0,0 +1,287 @@
+package bls
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// These tests point an APIClient at an httptest server replaying a recorded
+// response, so they don't need network access or a registration key.
+
+func newTestAPIClient(t *testing.T, key string, handler http.Handler) *APIClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := NewAPIClient(key)
+	client.HTTPClient = server.Client()
+	client.BaseURL = server.URL
+	client.MinInterval = 0
+	return client
+}
+
+func TestAPIClientTimeSeries(t *testing.T) {
+	recorded, err := os.ReadFile(filepath.Join("testdata", "api_timeseries.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got apiRequest
+	client := newTestAPIClient(t, "test-key", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != timeSeriesPath {
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Write(recorded)
+	}))
+
+	series, err := client.TimeSeries(context.Background(), SeriesRequest{
+		SeriesIDs:     []string{"LNS14000000", "CES0000000001"},
+		StartYear:     2021,
+		EndYear:       2024,
+		Catalog:       true,
+		Calculations:  true,
+		AnnualAverage: true,
+	})
+	if err != nil {
+		t.Fatalf("TimeSeries() returned an error: %v", err)
+	}
+
+	if got.RegistrationKey != "test-key" || got.StartYear != "2021" || got.EndYear != "2024" || !got.Catalog || !got.Calculations || !got.AnnualAverage {
+		t.Errorf("Unexpected request body %+v", got)
+	}
+	if len(series) != 2 || series[0].ID != "LNS14000000" || series[1].ID != "CES0000000001" {
+		t.Fatalf("Unexpected series %+v", series)
+	}
+
+	unemployment := series[0]
+	if unemployment.Catalog == nil || unemployment.Catalog.Title != "(Seas) Unemployment Rate" {
+		t.Errorf("Catalog = %+v", unemployment.Catalog)
+	}
+	if first := unemployment.Observations[0]; first.Year != 2021 || first.Period != "M11" {
+		t.Errorf("First observation = %+v, want November 2021", first)
+	}
+	latest, ok := unemployment.Latest()
+	if !ok || latest.Label() != "December 2024" || !latest.Latest || latest.Value != 4.1 {
+		t.Errorf("Latest() = %+v", latest)
+	}
+	if latest.NetChanges[12] != 0.3 || latest.PctChanges[1] != -2.4 {
+		t.Errorf("Calculations = %v %v", latest.NetChanges, latest.PctChanges)
+	}
+
+	payrolls := series[1]
+	// October has no value and is skipped
+	if len(payrolls.Observations) != 2 {
+		t.Fatalf("Got %d payroll observations, want 2", len(payrolls.Observations))
+	}
+	latest, _ = payrolls.Latest()
+	if latest.Value != 159486 || !latest.Preliminary || len(latest.Footnotes) != 1 {
+		t.Errorf("Latest payrolls = %+v", latest)
+	}
+}
+
+func TestAPIClientSplitsRequests(t *testing.T) {
+	testCases := []struct {
+		name         string
+		key          string
+		series       int
+		start, end   int
+		wantRequests int
+	}{
+		{"Unregistered", "", 30, 2000, 2024, 6},
+		{"Registered", "key", 30, 2000, 2024, 2},
+		{"Default years", "", 10, 0, 0, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var requests []apiRequest
+			client := newTestAPIClient(t, tc.key, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req apiRequest
+				json.NewDecoder(r.Body).Decode(&req)
+				mu.Lock()
+				requests = append(requests, req)
+				mu.Unlock()
+
+				resp := apiResponse{Status: "REQUEST_SUCCEEDED"}
+				data, _ := json.Marshal(resp)
+				w.Write(data)
+			}))
+
+			ids := make([]string, tc.series)
+			for i := range ids {
+				ids[i] = fmt.Sprintf("SERIES%02d", i)
+			}
+			series, err := client.TimeSeries(context.Background(), SeriesRequest{SeriesIDs: ids, StartYear: tc.start, EndYear: tc.end})
+			if err != nil {
+				t.Fatalf("TimeSeries() returned an error: %v", err)
+			}
+			if len(series) != tc.series {
+				t.Errorf("Got %d series, want %d", len(series), tc.series)
+			}
+			if len(requests) != tc.wantRequests {
+				t.Fatalf("Sent %d requests, want %d", len(requests), tc.wantRequests)
+			}
+			for _, req := range requests {
+				if tc.key == "" && len(req.SeriesIDs) > maxSeriesUnregistered {
+					t.Errorf("Request asked for %d series", len(req.SeriesIDs))
+				}
+			}
+			if tc.start != 0 && (requests[0].StartYear != fmt.Sprint(tc.start) || requests[len(requests)-1].EndYear != fmt.Sprint(tc.end)) {
+				t.Errorf("Requests don't cover %d-%d: %+v", tc.start, tc.end, requests)
+			}
+		})
+	}
+}
+
+func TestAPIClientRejectsInvalidRequests(t *testing.T) {
+	testCases := []struct {
+		name        string
+		req         SeriesRequest
+		expectedErr string
+	}{
+		{"No series", SeriesRequest{}, "no series requested"},
+		{"Only an end year", SeriesRequest{SeriesIDs: []string{"CUUR0000SA0"}, EndYear: 2024}, "must both be set"},
+		{"Only a start year", SeriesRequest{SeriesIDs: []string{"CUUR0000SA0"}, StartYear: 2020}, "must both be set"},
+		{"End before start", SeriesRequest{SeriesIDs: []string{"CUUR0000SA0"}, StartYear: 2024, EndYear: 2020}, "before start year"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := 0
+			client := newTestAPIClient(t, "", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.Write([]byte(`{"status":"REQUEST_SUCCEEDED"}`))
+			}))
+
+			_, err := client.TimeSeries(context.Background(), tc.req)
+			if err == nil {
+				t.Fatal("Expected an error but got nil")
+			}
+			if !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Errorf("Expected error containing '%s', but got: %v", tc.expectedErr, err)
+			}
+			if requests != 0 {
+				t.Errorf("Sent %d requests for an invalid request", requests)
+			}
+		})
+	}
+}
+
+func TestAPIClientRateLimits(t *testing.T) {
+	t.Run("Retries after 429", func(t *testing.T) {
+		calls := 0
+		client := newTestAPIClient(t, "", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Write([]byte(`{"status":"REQUEST_SUCCEEDED","Results":{"series":[]}}`))
+		}))
+
+		if _, err := client.TimeSeries(context.Background(), SeriesRequest{SeriesIDs: []string{"A"}}); err != nil {
+			t.Fatalf("TimeSeries() returned an error: %v", err)
+		}
+		if calls != 2 {
+			t.Errorf("Sent %d requests, want 2", calls)
+		}
+	})
+
+	t.Run("Gives up after MaxRetries", func(t *testing.T) {
+		client := newTestAPIClient(t, "", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		client.MaxRetries = 1
+
+		_, err := client.TimeSeries(context.Background(), SeriesRequest{SeriesIDs: []string{"A"}})
+		if !errors.Is(err, ErrRateLimited) {
+			t.Errorf("Expected ErrRateLimited, got %v", err)
+		}
+	})
+
+	t.Run("Daily threshold", func(t *testing.T) {
+		client := newTestAPIClient(t, "", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"status":"REQUEST_NOT_PROCESSED","message":["REQUEST_NOT_PROCESSED: BLS has detected that this request exceeds the daily threshold."],"Results":{}}`))
+		}))
+
+		_, err := client.TimeSeries(context.Background(), SeriesRequest{SeriesIDs: []string{"A"}})
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !errors.Is(err, ErrRateLimited) {
+			t.Errorf("Expected a rate limited APIError, got %v", err)
+		}
+	})
+
+	t.Run("Other API errors", func(t *testing.T) {
+		client := newTestAPIClient(t, "", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"status":"REQUEST_FAILED_INVALID_PARAMETERS","message":["Invalid year"],"Results":{}}`))
+		}))
+
+		_, err := client.TimeSeries(context.Background(), SeriesRequest{SeriesIDs: []string{"A"}})
+		if err == nil || errors.Is(err, ErrRateLimited) {
+			t.Errorf("Expected an APIError that isn't rate limited, got %v", err)
+		}
+	})
+}
+
+func TestTimeSeriesHighestSince(t *testing.T) {
+	obs := func(year int, period string, value float64) Observation {
+		return Observation{Year: year, Period: period, Value: value}
+	}
+
+	testCases := []struct {
+		name       string
+		values     []Observation
+		wantPeriod string
+		wantYear   int
+		wantOK     bool
+	}{
+		{
+			name:       "Highest since an earlier reading",
+			values:     []Observation{obs(2021, "M11", 4.2), obs(2023, "M12", 3.8), obs(2024, "M10", 4.1), obs(2024, "M11", 4.0), obs(2024, "M12", 4.1)},
+			wantPeriod: "M10", wantYear: 2024, wantOK: true,
+		},
+		{
+			name:   "Record high",
+			values: []Observation{obs(2023, "M12", 3.8), obs(2024, "M12", 4.1)},
+		},
+		{
+			name:       "Annual averages are ignored",
+			values:     []Observation{obs(2021, "M11", 4.5), obs(2023, "M13", 4.3), obs(2024, "M12", 4.2)},
+			wantPeriod: "M11", wantYear: 2021, wantOK: true,
+		},
+		{
+			name: "Empty",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := TimeSeries{Observations: tc.values}.HighestSince()
+			if ok != tc.wantOK || got.Period != tc.wantPeriod || got.Year != tc.wantYear {
+				t.Errorf("HighestSince() = %+v, %v; want %s %d, %v", got, ok, tc.wantPeriod, tc.wantYear, tc.wantOK)
+			}
+		})
+	}
+
+	series := TimeSeries{Observations: testCases[0].values}
+	if got, ok := series.MaxSince(2022); !ok || got.Value != 4.1 || got.Period != "M12" {
+		t.Errorf("MaxSince(2022) = %+v, %v", got, ok)
+	}
+}
@@ -0,0 +1,68 @@
+package bls
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// archivePath is where bls.gov keeps every past news release, as
+// <code>_<MMDDYYYY>.htm named after the release day.
+const archivePath = "/news.release/archives/"
+
+// ErrNotArchived is returned when bls.gov has no archived release for an event,
+// e.g. because it was published in a different format or not yet archived.
+var ErrNotArchived = errors.New("release not archived")
+
+// ArchiveURL returns the URL of the archived news release for an event, e.g.
+// https://www.bls.gov/news.release/archives/cpi_01152025.htm for the CPI
+// released on January 15, 2025.
+func (c *Client) ArchiveURL(event Event) (string, error) {
+	release, ok := c.registry().Lookup(event.Summary)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNoMapping, strings.TrimSpace(event.Summary))
+	}
+	day, ok := event.ReleaseDay()
+	if !ok {
+		return "", fmt.Errorf("event %q has no start time", event.Summary)
+	}
+	return c.url(archivePath + release.Code() + "_" + day.Format("01022006") + ".htm"), nil
+}
+
+// FetchArchivedReleaseHTML fetches the archived HTML of the release of a past
+// event using the DefaultClient.
+func FetchArchivedReleaseHTML(event Event) (string, error) {
+	return DefaultClient.FetchArchivedReleaseHTML(context.Background(), event)
+}
+
+// FetchArchivedReleaseHTML fetches the archived HTML of the release of a past
+// event. Unlike FetchReleaseHTML, which always gets the latest release, this
+// gets the release published on the event's day.
+func (c *Client) FetchArchivedReleaseHTML(ctx context.Context, event Event) (string, error) {
+	url, err := c.ArchiveURL(event)
+	if err != nil {
+		return "", err
+	}
+
+	resp, html, err := c.get(ctx, url, "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch HTML from %s: %w", url, err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("%w: %s", ErrNotArchived, url)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("bad status from %s: %s", url, resp.Status)
+	}
+
+	// Make sure we weren't redirected to some other release
+	day, _ := event.ReleaseDay()
+	if released, err := ReleaseDate(string(html)); err == nil && !released.Equal(day) {
+		return "", fmt.Errorf("archived release at %s is from %s, not %s", url, released.Format("2006-01-02"), day.Format("2006-01-02"))
+	}
+
+	return string(html), nil
+}
@@ -0,0 +1,133 @@
+package bls
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientArchiveURL(t *testing.T) {
+	client := NewClient()
+
+	// 8:30 a.m. Eastern on January 15, 2025
+	start := time.Date(2025, 1, 15, 13, 30, 0, 0, time.UTC)
+	// Late evening Eastern is already the next day in UTC
+	evening := time.Date(2025, 3, 1, 2, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name        string
+		event       Event
+		want        string
+		expectedErr string
+	}{
+		{
+			name:  "CPI",
+			event: Event{Summary: "Consumer Price Index", Start: &start},
+			want:  "https://www.bls.gov/news.release/archives/cpi_01152025.htm",
+		},
+		{
+			name:  "Eastern release day",
+			event: Event{Summary: "Employment Situation", Start: &evening},
+			want:  "https://www.bls.gov/news.release/archives/empsit_02282025.htm",
+		},
+		{
+			name:        "No start time",
+			event:       Event{Summary: "Consumer Price Index"},
+			expectedErr: "no start time",
+		},
+		{
+			name:        "Unmapped event",
+			event:       Event{Summary: "An Imaginary Economic Indicator", Start: &start},
+			expectedErr: "no mapping for event",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := client.ArchiveURL(tc.event)
+			if tc.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Errorf("Expected error containing %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ArchiveURL() returned an error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("ArchiveURL() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestClientFetchArchivedReleaseHTML(t *testing.T) {
+	start := time.Date(2025, 1, 15, 13, 30, 0, 0, time.UTC)
+	event := Event{Summary: "Consumer Price Index", Start: &start}
+	archived := "<pre>embargoed until\n8:30 a.m. (ET) Wednesday, January 15, 2025\nCONSUMER PRICE INDEX - DECEMBER 2024</pre>"
+	other := "<pre>embargoed until\n8:30 a.m. (ET) Wednesday, February 12, 2025\nCONSUMER PRICE INDEX - JANUARY 2025</pre>"
+
+	testCases := []struct {
+		name        string
+		handler     http.HandlerFunc
+		want        string
+		expectedErr error
+		errContains string
+	}{
+		{
+			name: "Archived release",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/news.release/archives/cpi_01152025.htm" {
+					http.NotFound(w, r)
+					return
+				}
+				w.Write([]byte(archived))
+			},
+			want: archived,
+		},
+		{
+			name:        "Not archived",
+			handler:     http.NotFound,
+			expectedErr: ErrNotArchived,
+		},
+		{
+			name: "Release from another day",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(other))
+			},
+			errContains: "is from 2025-02-12",
+		},
+		{
+			name: "Bad status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			errContains: "bad status",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newTestClient(t, tc.handler)
+
+			got, err := client.FetchArchivedReleaseHTML(context.Background(), event)
+			switch {
+			case tc.expectedErr != nil:
+				if !errors.Is(err, tc.expectedErr) {
+					t.Errorf("Expected %v, got %v", tc.expectedErr, err)
+				}
+			case tc.errContains != "":
+				if err == nil || !strings.Contains(err.Error(), tc.errContains) {
+					t.Errorf("Expected error containing %q, got %v", tc.errContains, err)
+				}
+			case err != nil:
+				t.Fatalf("FetchArchivedReleaseHTML() returned an error: %v", err)
+			case got != tc.want:
+				t.Errorf("FetchArchivedReleaseHTML() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -71,6 +71,56 @@ func TestExtractSummary(t *testing.T) {
 	}
 }
 
+func TestExtractReleaseText(t *testing.T) {
+	testCases := []struct {
+		name      string
+		htmlInput string
+		want      string
+		expectErr bool
+	}{
+		{
+			name:      "BLS release",
+			htmlInput: `<html><body><nav><p>Menu</p></nav><pre>The CPI rose 0.4 percent.  __________ Tables</pre></body></html>`,
+			want:      "The CPI rose 0.4 percent.  ",
+		},
+		{
+			name: "Main content",
+			htmlInput: `<html><body><nav><p>Menu</p></nav><main><h1>Gross Domestic Product, 4th Quarter 2024</h1>
+				<p>Real GDP increased at an annual rate of
+				2.3 percent.</p><ul><li>Consumer spending rose.</li></ul></main><footer><p>Contact</p></footer></body></html>`,
+			want: "Gross Domestic Product, 4th Quarter 2024\n\nReal GDP increased at an annual rate of 2.3 percent.\n\nConsumer spending rose.",
+		},
+		{
+			name:      "Page without main content",
+			htmlInput: `<html><body><h2>Statement</h2><p>The Committee decided to maintain the target range.</p></body></html>`,
+			want:      "Statement\n\nThe Committee decided to maintain the target range.",
+		},
+		{
+			name:      "Empty input",
+			htmlInput: "",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ExtractReleaseText(tc.htmlInput)
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("Expected an error but got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExtractReleaseText() returned an error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("ExtractReleaseText() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestAgeInMins(t *testing.T) {
 	// t.Parallel()
 
@@ -84,11 +134,49 @@ func TestAgeInMins(t *testing.T) {
 	}
 }
 
+func TestEventKey(t *testing.T) {
+	start := time.Date(2025, 1, 15, 8, 30, 0, 0, time.FixedZone("EST", -5*60*60))
+
+	testCases := []struct {
+		name  string
+		event Event
+		want  string
+	}{
+		{
+			name:  "UID and start",
+			event: Event{UID: "cpi-2025-01@bls.gov", Summary: "Consumer Price Index", Start: &start},
+			want:  "cpi-2025-01@bls.gov-20250115T133000Z",
+		},
+		{
+			name:  "Missing UID falls back to summary",
+			event: Event{Summary: "Consumer Price Index", Start: &start},
+			want:  "Consumer-Price-Index-20250115T133000Z",
+		},
+		{
+			name:  "Missing start",
+			event: Event{UID: "cpi-2025-01@bls.gov"},
+			want:  "cpi-2025-01@bls.gov",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.event.Key(); got != tc.want {
+				t.Errorf("Key() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
 // === INTEGRATION TESTS ===
 // These tests perform live network requests. They are slower and can be brittle.
 // They are skipped by default unless the -short flag is omitted.
 
 func TestGetAllEvents(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode.")
+	}
+
 	events, err := GetAllEvents()
 	if err != nil {
 		t.Fatalf("GetAllEvents() returned an error: %v", err)
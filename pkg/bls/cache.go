@@ -0,0 +1,215 @@
+package bls
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// calendarCacheFile holds the last good copy of the calendar.
+	calendarCacheFile = "bls.ics"
+	// calendarMetaFile holds the validators that came with it.
+	calendarMetaFile = "bls.ics.json"
+)
+
+// CalendarSnapshot is the result of CalendarCache.Fetch.
+type CalendarSnapshot struct {
+	Events []Event
+
+	// ETag and LastModified are the validators of the copy the events came from.
+	ETag         string
+	LastModified string
+	// FetchedAt is when that copy was downloaded.
+	FetchedAt time.Time
+
+	// NotModified is set when bls.gov answered 304 and the cached copy was used.
+	NotModified bool
+	// Stale is set when bls.gov couldn't be reached or refused the request and
+	// the last good copy was used instead. FetchErr holds the reason.
+	Stale    bool
+	FetchErr error `json:"-"`
+
+	// Diff lists what changed relative to the previous cached copy. It is nil
+	// unless a new copy was downloaded.
+	Diff *CalendarDiff
+}
+
+// calendarMeta is persisted next to the cached calendar.
+type calendarMeta struct {
+	ETag         string    `json:"etag"`
+	LastModified string    `json:"last_modified"`
+	FetchedAt    time.Time `json:"fetched_at"`
+}
+
+// CalendarCache fetches the release calendar with conditional requests and
+// keeps the last good copy on disk, so a 304 costs nothing and a 403 or outage
+// at bls.gov doesn't stop scheduling.
+type CalendarCache struct {
+	client *Client
+	dir    string
+
+	// mu serialises fetches so two activities don't race on the cache files
+	mu sync.Mutex
+}
+
+// NewCalendarCache returns a cache that stores its copy under dir, creating it
+// if needed.
+func NewCalendarCache(client *Client, dir string) (*CalendarCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create calendar cache directory: %w", err)
+	}
+	return &CalendarCache{client: client, dir: dir}, nil
+}
+
+// Fetch returns the current calendar. It only returns an error when bls.gov
+// can't provide a calendar and there is no cached copy to fall back to.
+func (c *CalendarCache) Fetch(ctx context.Context) (*CalendarSnapshot, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cachedBody, meta, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var cached []Event
+	if cachedBody != nil {
+		cached, err = parseCalendarBody(cachedBody)
+		if err != nil {
+			// A copy we can't read is as good as none
+			cachedBody, meta, cached = nil, calendarMeta{}, nil
+		}
+	}
+
+	header := make(http.Header)
+	if cachedBody != nil {
+		if meta.ETag != "" {
+			header.Set("If-None-Match", meta.ETag)
+		}
+		if meta.LastModified != "" {
+			header.Set("If-Modified-Since", meta.LastModified)
+		}
+	}
+
+	snapshot := &CalendarSnapshot{
+		Events:       cached,
+		ETag:         meta.ETag,
+		LastModified: meta.LastModified,
+		FetchedAt:    meta.FetchedAt,
+	}
+
+	resp, body, fetchErr := c.client.get(ctx, c.client.CalendarURL(), calendarAccept, header)
+	switch {
+	case fetchErr == nil && resp.StatusCode == http.StatusNotModified && cachedBody != nil:
+		snapshot.NotModified = true
+		return snapshot, nil
+	case fetchErr == nil && resp.StatusCode == http.StatusOK:
+		events, err := parseCalendarBody(body)
+		if err != nil {
+			fetchErr = err
+			break
+		}
+
+		newMeta := calendarMeta{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			FetchedAt:    time.Now().UTC(),
+		}
+		if err := c.save(body, newMeta); err != nil {
+			// The download is still good, we just won't have it next time
+			log.Printf("Warning: failed to cache calendar: %v", err)
+		}
+
+		fresh := &CalendarSnapshot{
+			Events:       events,
+			ETag:         newMeta.ETag,
+			LastModified: newMeta.LastModified,
+			FetchedAt:    newMeta.FetchedAt,
+		}
+		if !bytes.Equal(body, cachedBody) {
+			diff := DiffEvents(cached, events)
+			fresh.Diff = &diff
+		}
+		return fresh, nil
+	case fetchErr == nil:
+		fetchErr = fmt.Errorf("bad status from calendar URL: %s", resp.Status)
+	}
+
+	if cachedBody == nil {
+		return nil, fmt.Errorf("failed to fetch calendar: %w", fetchErr)
+	}
+	snapshot.Stale = true
+	snapshot.FetchErr = fetchErr
+	return snapshot, nil
+}
+
+// load reads the cached calendar and its validators. A missing cache isn't an error.
+func (c *CalendarCache) load() ([]byte, calendarMeta, error) {
+	var meta calendarMeta
+
+	body, err := os.ReadFile(filepath.Join(c.dir, calendarCacheFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, meta, nil
+	}
+	if err != nil {
+		return nil, meta, fmt.Errorf("failed to read cached calendar: %w", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(c.dir, calendarMetaFile))
+	if err == nil {
+		// Without usable validators we simply make an unconditional request
+		_ = json.Unmarshal(data, &meta)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, meta, fmt.Errorf("failed to read calendar metadata: %w", err)
+	}
+
+	return body, meta, nil
+}
+
+// save replaces the cached calendar. The validators are written last so they
+// never describe a body that isn't on disk.
+func (c *CalendarCache) save(body []byte, meta calendarMeta) error {
+	// Drop the old validators first so a crash can't pair them with the new body
+	if err := os.Remove(filepath.Join(c.dir, calendarMetaFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove calendar metadata: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(c.dir, calendarCacheFile), body); err != nil {
+		return fmt.Errorf("failed to write cached calendar: %w", err)
+	}
+
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal calendar metadata: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(c.dir, calendarMetaFile), data); err != nil {
+		return fmt.Errorf("failed to write calendar metadata: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,141 @@
+package bls
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testCalendarMoved = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Test//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:cpi-2025-01@bls.gov\r\n" +
+	"DTSTART:20250116T133000Z\r\n" +
+	"SUMMARY:Consumer Price Index\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestCalendarCache(t *testing.T) {
+	// Each step sets how the stand-in bls.gov answers the next fetch
+	var (
+		body      = testCalendar
+		status    = http.StatusOK
+		etag      = `"v1"`
+		gotIfNone string
+	)
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotIfNone = r.Header.Get("If-None-Match")
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		if gotIfNone == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", "Wed, 01 Jan 2025 00:00:00 GMT")
+		w.Write([]byte(body))
+	}))
+
+	dir := t.TempDir()
+	cache, err := NewCalendarCache(client, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	t.Run("First fetch downloads and saves the calendar", func(t *testing.T) {
+		snapshot, err := cache.Fetch(ctx)
+		if err != nil {
+			t.Fatalf("Fetch() returned an error: %v", err)
+		}
+		if gotIfNone != "" {
+			t.Errorf("First fetch sent If-None-Match %q, want none", gotIfNone)
+		}
+		if len(snapshot.Events) != 1 || snapshot.ETag != `"v1"` {
+			t.Errorf("Fetch() = %d events with ETag %q, want 1 event with ETag %q", len(snapshot.Events), snapshot.ETag, `"v1"`)
+		}
+		if snapshot.Diff == nil || len(snapshot.Diff.Added) != 1 {
+			t.Errorf("Expected the first download to report one added event, got %+v", snapshot.Diff)
+		}
+		if _, err := os.Stat(filepath.Join(dir, calendarCacheFile)); err != nil {
+			t.Errorf("Calendar was not saved: %v", err)
+		}
+	})
+
+	t.Run("Unchanged calendar is not downloaded again", func(t *testing.T) {
+		snapshot, err := cache.Fetch(ctx)
+		if err != nil {
+			t.Fatalf("Fetch() returned an error: %v", err)
+		}
+		if gotIfNone != `"v1"` {
+			t.Errorf("If-None-Match = %q, want %q", gotIfNone, `"v1"`)
+		}
+		if !snapshot.NotModified || snapshot.Diff != nil || len(snapshot.Events) != 1 {
+			t.Errorf("Expected a not-modified snapshot with the cached event, got %+v", snapshot)
+		}
+	})
+
+	t.Run("Forbidden falls back to the cached copy", func(t *testing.T) {
+		status = http.StatusForbidden
+		defer func() { status = http.StatusOK }()
+
+		snapshot, err := cache.Fetch(ctx)
+		if err != nil {
+			t.Fatalf("Fetch() returned an error: %v", err)
+		}
+		if !snapshot.Stale || snapshot.FetchErr == nil || len(snapshot.Events) != 1 {
+			t.Errorf("Expected a stale snapshot with the cached event, got %+v", snapshot)
+		}
+	})
+
+	t.Run("Changed calendar reports a diff", func(t *testing.T) {
+		body, etag = testCalendarMoved, `"v2"`
+
+		snapshot, err := cache.Fetch(ctx)
+		if err != nil {
+			t.Fatalf("Fetch() returned an error: %v", err)
+		}
+		if snapshot.Diff == nil || len(snapshot.Diff.Rescheduled) != 1 || len(snapshot.Diff.Added) != 0 || len(snapshot.Diff.Removed) != 0 {
+			t.Fatalf("Expected one rescheduled event, got %+v", snapshot.Diff)
+		}
+		if got := snapshot.Diff.Rescheduled[0].Current.Start.Day(); got != 16 {
+			t.Errorf("Rescheduled event starts on day %d, want 16", got)
+		}
+	})
+
+	t.Run("Invalid body falls back to the cached copy", func(t *testing.T) {
+		body, etag = "<html>Access Denied</html>", `"v3"`
+
+		snapshot, err := cache.Fetch(ctx)
+		if err != nil {
+			t.Fatalf("Fetch() returned an error: %v", err)
+		}
+		if !snapshot.Stale || snapshot.ETag != `"v2"` {
+			t.Errorf("Expected the v2 copy to be served stale, got %+v", snapshot)
+		}
+	})
+}
+
+func TestCalendarCacheNoCopy(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	cache, err := NewCalendarCache(client, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = cache.Fetch(context.Background())
+	if err == nil {
+		t.Fatal("Expected an error without a cached copy, got nil")
+	}
+	if !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected error mentioning the 403, got: %v", err)
+	}
+}
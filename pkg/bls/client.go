@@ -0,0 +1,164 @@
+package bls
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBaseURL is the bls.gov site the calendar and releases are fetched from.
+	DefaultBaseURL = "https://www.bls.gov"
+	// DefaultUserAgent is a browser User-Agent; bls.gov rejects most non-browser clients.
+	DefaultUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	// DefaultTimeout bounds each request, including reading the body.
+	DefaultTimeout = 30 * time.Second
+
+	// calendarPath is the path of the release calendar relative to the base URL.
+	calendarPath = "/schedule/news_release/bls.ics"
+	// calendarAccept is the Accept header sent when fetching the calendar.
+	calendarAccept = "text/calendar,text/plain,*/*"
+)
+
+// ErrNoMapping is returned when the registry has no release for an event.
+var ErrNoMapping = errors.New("no mapping for event")
+
+// Client fetches the release calendar and news releases from bls.gov. The zero
+// value is not usable; create one with NewClient.
+type Client struct {
+	// HTTPClient sends the requests.
+	HTTPClient *http.Client
+	// BaseURL is prefixed to the calendar and release paths, e.g. an httptest
+	// server's URL in tests.
+	BaseURL string
+	// UserAgent is sent with every request.
+	UserAgent string
+	// Timeout bounds each request. Zero means no timeout beyond the HTTPClient's own.
+	Timeout time.Duration
+	// Registry maps event summaries to news releases. Nil means DefaultRegistry.
+	Registry *Registry
+}
+
+// NewClient returns a Client for bls.gov with the default User-Agent and timeout.
+func NewClient() *Client {
+	return &Client{
+		HTTPClient: &http.Client{Timeout: DefaultTimeout},
+		BaseURL:    DefaultBaseURL,
+		UserAgent:  DefaultUserAgent,
+		Timeout:    DefaultTimeout,
+	}
+}
+
+// DefaultClient is used by the package-level functions.
+var DefaultClient = NewClient()
+
+// CalendarURL returns the URL of the release calendar.
+func (c *Client) CalendarURL() string {
+	return c.url(calendarPath)
+}
+
+// LookupRelease finds the release for an event summary in the client's registry.
+func (c *Client) LookupRelease(summary string) (Release, bool) {
+	return c.registry().Lookup(summary)
+}
+
+// ReleaseURL returns the URL of the news release for an event. Releases in the
+// registry are found under the base URL; other events, such as releases from
+// other agencies, use the event's own URL when it has one.
+func (c *Client) ReleaseURL(event Event) (string, error) {
+	release, ok := c.LookupRelease(event.Summary)
+	if ok {
+		return c.url(release.Path), nil
+	}
+	if url := strings.TrimSpace(event.URL); url != "" {
+		return url, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrNoMapping, strings.TrimSpace(event.Summary))
+}
+
+// PathURL returns the URL of a path relative to the base URL.
+func (c *Client) PathURL(path string) string {
+	return c.url(path)
+}
+
+// CheckPath requests a path relative to the base URL and returns the status
+// code, e.g. to confirm a release page still exists.
+func (c *Client) CheckPath(ctx context.Context, path string) (int, error) {
+	resp, _, err := c.get(ctx, c.url(path), "text/html,application/xhtml+xml,*/*", nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch %s: %w", path, err)
+	}
+	return resp.StatusCode, nil
+}
+
+func (c *Client) registry() *Registry {
+	if c.Registry != nil {
+		return c.Registry
+	}
+	return DefaultRegistry()
+}
+
+func (c *Client) url(path string) string {
+	return strings.TrimRight(c.BaseURL, "/") + path
+}
+
+// get fetches url with browser-like headers, plus any extra headers, and returns
+// the decoded body along with the response. The caller decides which status
+// codes are acceptable.
+func (c *Client) get(ctx context.Context, url string, accept string, extra http.Header) (*http.Response, []byte, error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", c.UserAgent)
+	req.Header.Set("Accept", accept)
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+	req.Header.Set("Connection", "keep-alive")
+	req.Header.Set("Upgrade-Insecure-Requests", "1")
+	for name, values := range extra {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	var bodyReader io.Reader = resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return resp, nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		defer gzipReader.Close()
+		bodyReader = gzipReader
+	}
+
+	body, err := io.ReadAll(bodyReader)
+	if err != nil {
+		return resp, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return resp, body, nil
+}
@@ -0,0 +1,210 @@
+package bls
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// These tests point a Client at an httptest server, so they don't need network access.
+
+const testCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Test//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:cpi-2025-01@bls.gov\r\n" +
+	"DTSTART:20250115T133000Z\r\n" +
+	"DTEND:20250115T140000Z\r\n" +
+	"SUMMARY:Consumer Price Index\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+const testRelease = `<html><body><pre>CONSUMER PRICE INDEX - DECEMBER 2024
+The Consumer Price Index rose 0.4 percent in December.
+__________
+Table of Contents</pre></body></html>`
+
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := NewClient()
+	client.HTTPClient = server.Client()
+	client.BaseURL = server.URL
+	return client
+}
+
+func TestClientGetAllEvents(t *testing.T) {
+	var gotUserAgent string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != calendarPath {
+			http.NotFound(w, r)
+			return
+		}
+		gotUserAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(testCalendar))
+	}))
+	client.UserAgent = "bls-agent-test"
+
+	events, err := client.GetAllEvents(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllEvents() returned an error: %v", err)
+	}
+	if gotUserAgent != "bls-agent-test" {
+		t.Errorf("User-Agent = %q, want %q", gotUserAgent, "bls-agent-test")
+	}
+	if len(events) != 1 {
+		t.Fatalf("GetAllEvents() returned %d events, want 1", len(events))
+	}
+	if events[0].Summary != "Consumer Price Index" {
+		t.Errorf("Summary = %q, want %q", events[0].Summary, "Consumer Price Index")
+	}
+}
+
+func TestClientFetchReleaseHTML(t *testing.T) {
+	future := time.Now().Add(24 * time.Hour)
+	past := time.Now().Add(-24 * time.Hour)
+
+	testCases := []struct {
+		name        string
+		event       Event
+		handler     http.HandlerFunc
+		want        string
+		expectedErr string
+	}{
+		{
+			name:  "Happy Path - Plain response",
+			event: Event{Summary: "Consumer Price Index", Start: &past},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/news.release/cpi.nr0.htm" {
+					http.NotFound(w, r)
+					return
+				}
+				w.Write([]byte(testRelease))
+			},
+			want: testRelease,
+		},
+		{
+			name:  "Happy Path - Gzipped response",
+			event: Event{Summary: " Consumer Price Index ", Start: &past},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				var buf bytes.Buffer
+				zw := gzip.NewWriter(&buf)
+				zw.Write([]byte(testRelease))
+				zw.Close()
+				w.Header().Set("Content-Encoding", "gzip")
+				w.Write(buf.Bytes())
+			},
+			want: testRelease,
+		},
+		{
+			name:  "Error - Forbidden future release",
+			event: Event{Summary: "Consumer Price Index", Start: &future},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			},
+			expectedErr: "release not yet available",
+		},
+		{
+			name:  "Error - Forbidden past release",
+			event: Event{Summary: "Consumer Price Index", Start: &past},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			},
+			expectedErr: "access forbidden",
+		},
+		{
+			name:  "Error - Bad status",
+			event: Event{Summary: "Consumer Price Index", Start: &past},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			expectedErr: "bad status",
+		},
+		{
+			name:        "Error - Event not in map",
+			event:       Event{Summary: "An Imaginary Economic Indicator"},
+			handler:     func(w http.ResponseWriter, r *http.Request) {},
+			expectedErr: "no mapping for event",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newTestClient(t, tc.handler)
+
+			got, err := client.FetchReleaseHTML(context.Background(), tc.event)
+
+			if tc.expectedErr != "" {
+				if err == nil {
+					t.Fatal("Expected an error but got nil")
+				}
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Errorf("Expected error containing '%s', but got: %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Did not expect an error, but got: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("FetchReleaseHTML() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	release := make(chan struct{})
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer close(release)
+	client.Timeout = 50 * time.Millisecond
+
+	start := time.Now()
+	_, err := client.GetAllEvents(context.Background())
+	if err == nil {
+		t.Fatal("Expected a timeout error but got nil")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("GetAllEvents() took %v, expected it to give up after the timeout", elapsed)
+	}
+}
+
+func TestClientReleaseURL(t *testing.T) {
+	client := NewClient()
+	client.BaseURL = "http://localhost:8080/"
+
+	got, err := client.ReleaseURL(Event{Summary: "Employment Situation"})
+	if err != nil {
+		t.Fatalf("ReleaseURL() returned an error: %v", err)
+	}
+	if want := "http://localhost:8080/news.release/empsit.nr0.htm"; got != want {
+		t.Errorf("ReleaseURL() = %q, want %q", got, want)
+	}
+
+	// Events outside the registry use their own URL
+	gdp := Event{Summary: "Gross Domestic Product", URL: "https://www.bea.gov/data/gdp/gross-domestic-product"}
+	got, err = client.ReleaseURL(gdp)
+	if err != nil {
+		t.Fatalf("ReleaseURL() returned an error: %v", err)
+	}
+	if got != gdp.URL {
+		t.Errorf("ReleaseURL() = %q, want %q", got, gdp.URL)
+	}
+
+	if _, err := client.ReleaseURL(Event{Summary: "Gross Domestic Product"}); !errors.Is(err, ErrNoMapping) {
+		t.Errorf("ReleaseURL() error = %v, want ErrNoMapping", err)
+	}
+}
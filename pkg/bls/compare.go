@@ -0,0 +1,348 @@
+package bls
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReleaseSnapshot holds the figures parsed from one news release, so it can be
+// compared with the next release of the same series.
+type ReleaseSnapshot struct {
+	// Key is the key of the event the release was published for.
+	Key string `json:"key"`
+	// Release is the registry summary of the release, shared by every month's
+	// release of the same series.
+	Release   string    `json:"release"`
+	Start     time.Time `json:"start"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Metrics   []Metric  `json:"metrics,omitempty"`
+	Series    []Series  `json:"series,omitempty"`
+}
+
+// NewReleaseSnapshot parses the tables and, when the release has an extractor,
+// the headline figures of a release, looking the release up in the default
+// registry.
+func NewReleaseSnapshot(event Event, html string, fetchedAt time.Time) ReleaseSnapshot {
+	return DefaultClient.NewReleaseSnapshot(event, html, fetchedAt)
+}
+
+// NewReleaseSnapshot parses the tables and, when the release has an extractor,
+// the headline figures of a release. The release is looked up in the client's
+// registry. Parts that can't be parsed are left out, so the snapshot may be
+// empty.
+func (c *Client) NewReleaseSnapshot(event Event, html string, fetchedAt time.Time) ReleaseSnapshot {
+	snapshot := ReleaseSnapshot{
+		Key:       event.Key(),
+		Release:   c.releaseSummary(event),
+		FetchedAt: fetchedAt,
+	}
+	if event.Start != nil {
+		snapshot.Start = *event.Start
+	}
+
+	if headline, err := c.ExtractHeadline(event, html); err == nil {
+		snapshot.Metrics = headline.Metrics()
+	}
+	if tables, err := ParseTables(html); err == nil {
+		for _, table := range tables {
+			snapshot.Series = append(snapshot.Series, table.Series()...)
+		}
+	}
+	return snapshot
+}
+
+// Revision is a figure for an earlier period that changed between releases.
+type Revision struct {
+	Series   string  `json:"series"`
+	Period   string  `json:"period"`
+	Unit     string  `json:"unit,omitempty"`
+	Previous float64 `json:"previous"`
+	Current  float64 `json:"current"`
+	// Headline is set for revisions to the release's headline figures, as
+	// opposed to other cells of its tables.
+	Headline bool `json:"headline,omitempty"`
+}
+
+// Change returns how much the figure was revised by.
+func (r Revision) Change() float64 {
+	return r.Current - r.Previous
+}
+
+// relativeChange returns the revision as a share of the previous figure, so
+// revisions to series in different units can be ranked.
+func (r Revision) relativeChange() float64 {
+	if r.Previous == 0 {
+		return math.Abs(r.Change())
+	}
+	return math.Abs(r.Change() / r.Previous)
+}
+
+// String describes the revision for a prompt, e.g.
+// "Total nonfarm (Nov. 2024): revised down by 15, from 227 to 212 (In thousands)".
+func (r Revision) String() string {
+	direction := "up"
+	if r.Change() < 0 {
+		direction = "down"
+	}
+	s := fmt.Sprintf("%s (%s): revised %s by %s, from %s to %s", r.Series, r.Period, direction,
+		formatFigure(math.Abs(r.Change())), formatFigure(r.Previous), formatFigure(r.Current))
+	if r.Unit != "" {
+		s += " (" + r.Unit + ")"
+	}
+	return s
+}
+
+// Trend is a headline figure's latest reading next to the previous release's.
+type Trend struct {
+	Name           string  `json:"name"`
+	Unit           string  `json:"unit,omitempty"`
+	PreviousPeriod string  `json:"previous_period"`
+	Previous       float64 `json:"previous"`
+	CurrentPeriod  string  `json:"current_period"`
+	Current        float64 `json:"current"`
+}
+
+// Reversal reports whether the figure changed sign, e.g. prices that rose
+// last month and fell this month.
+func (t Trend) Reversal() bool {
+	return (t.Previous > 0 && t.Current < 0) || (t.Previous < 0 && t.Current > 0)
+}
+
+// String describes the trend for a prompt, e.g.
+// "CPI all items, m/m: 0.4 percent in Dec. 2024, up from 0.3 in Nov. 2024".
+func (t Trend) String() string {
+	current := formatFigure(t.Current)
+	if t.Unit != "" {
+		current += " " + t.Unit
+	}
+
+	direction := "unchanged from"
+	switch {
+	case t.Current > t.Previous:
+		direction = "up from"
+	case t.Current < t.Previous:
+		direction = "down from"
+	}
+
+	s := fmt.Sprintf("%s: %s in %s, %s %s in %s", t.Name, current, t.CurrentPeriod, direction, formatFigure(t.Previous), t.PreviousPeriod)
+	switch {
+	case t.Reversal() && t.Current < 0:
+		s += ", turning negative"
+	case t.Reversal():
+		s += ", turning positive"
+	}
+	return s
+}
+
+// Comparison is what changed between two releases of the same series.
+type Comparison struct {
+	PreviousKey string     `json:"previous_key"`
+	Revisions   []Revision `json:"revisions,omitempty"`
+	Trends      []Trend    `json:"trends,omitempty"`
+}
+
+// Empty reports whether the comparison found nothing worth mentioning.
+func (c Comparison) Empty() bool {
+	return len(c.Revisions) == 0 && len(c.Trends) == 0
+}
+
+// maxTableRevisions caps the revisions to table cells in the prompt. Annual
+// benchmark and seasonal factor revisions touch hundreds of cells, and only the
+// largest are worth a tweet.
+const maxTableRevisions = 5
+
+// Context formats the comparison for the tweet prompt, one finding per line.
+// Revisions to headline figures are always listed; revisions to other table
+// cells are limited to the maxTableRevisions largest relative to the previous
+// figure, with a count of the rest. Series of changes, such as payroll
+// changes, with several revised periods also get their combined revision,
+// which is meaningless for levels or rates.
+func (c Comparison) Context() string {
+	var lines []string
+	for _, t := range c.Trends {
+		lines = append(lines, "- "+t.String())
+	}
+
+	revisions, omitted := c.promptRevisions()
+	var order []string
+	bySeries := make(map[string][]Revision)
+	for _, r := range revisions {
+		lines = append(lines, "- "+r.String())
+		if _, ok := bySeries[r.Series]; !ok {
+			order = append(order, r.Series)
+		}
+		bySeries[r.Series] = append(bySeries[r.Series], r)
+	}
+	for _, series := range order {
+		revisions := bySeries[series]
+		if len(revisions) < 2 || !strings.Contains(strings.ToLower(series), "change") {
+			continue
+		}
+		lines = append(lines, "- "+combinedRevision(series, revisions))
+	}
+	if omitted > 0 {
+		lines = append(lines, fmt.Sprintf("- %d smaller revisions to other figures", omitted))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// promptRevisions returns the revisions worth a line in the prompt, in their
+// original order, and how many table revisions were left out.
+func (c Comparison) promptRevisions() ([]Revision, int) {
+	var table []int
+	for i, r := range c.Revisions {
+		if !r.Headline {
+			table = append(table, i)
+		}
+	}
+	if len(table) <= maxTableRevisions {
+		return c.Revisions, 0
+	}
+
+	sort.SliceStable(table, func(a, b int) bool {
+		return c.Revisions[table[a]].relativeChange() > c.Revisions[table[b]].relativeChange()
+	})
+	keep := make(map[int]bool, maxTableRevisions)
+	for _, i := range table[:maxTableRevisions] {
+		keep[i] = true
+	}
+
+	var revisions []Revision
+	for i, r := range c.Revisions {
+		if r.Headline || keep[i] {
+			revisions = append(revisions, r)
+		}
+	}
+	return revisions, len(table) - maxTableRevisions
+}
+
+// combinedRevision describes the sum of the revisions to a series, e.g.
+// "Nonfarm payroll change: October 2024 and November 2024 revised down by a
+// combined 8 (thousand)".
+func combinedRevision(series string, revisions []Revision) string {
+	periods := make([]string, len(revisions))
+	total := 0.0
+	for i, r := range revisions {
+		periods[i] = r.Period
+		total += r.Change()
+	}
+
+	direction := "up"
+	if total < 0 {
+		direction = "down"
+	}
+	s := fmt.Sprintf("%s: %s and %s revised %s by a combined %s", series,
+		strings.Join(periods[:len(periods)-1], ", "), periods[len(periods)-1], direction, formatFigure(math.Abs(total)))
+	if unit := revisions[0].Unit; unit != "" {
+		s += " (" + unit + ")"
+	}
+	return s
+}
+
+// CompareReleases compares a release with the previous release of the same
+// series. Figures for a period both releases cover are revisions when they
+// differ, and headline figures for a new period are reported next to the
+// previous reading. Columns are matched on period and adjustment rather than
+// their headers, which lose the "(p)" once a figure is no longer preliminary.
+func CompareReleases(previous, current ReleaseSnapshot) (Comparison, error) {
+	if previous.Release != current.Release {
+		return Comparison{}, fmt.Errorf("can't compare %q with %q", current.Release, previous.Release)
+	}
+	if previous.Key == current.Key {
+		return Comparison{}, errors.New("can't compare a release with itself")
+	}
+
+	comparison := Comparison{PreviousKey: previous.Key}
+
+	for _, m := range current.Metrics {
+		for _, p := range previous.Metrics {
+			if p.Name != m.Name || p.Adjustment != m.Adjustment {
+				continue
+			}
+			if p.Period == m.Period {
+				if math.Abs(p.Value-m.Value) > 1e-9 {
+					comparison.Revisions = append(comparison.Revisions, Revision{
+						Series:   m.Name,
+						Period:   m.Period,
+						Unit:     m.Unit,
+						Previous: p.Value,
+						Current:  m.Value,
+						Headline: true,
+					})
+				}
+				break
+			}
+			// Trends compare the headline readings, not revised earlier periods
+			if m.Previous == nil && p.Previous == nil {
+				comparison.Trends = append(comparison.Trends, Trend{
+					Name:           m.Name,
+					Unit:           m.Unit,
+					PreviousPeriod: p.Period,
+					Previous:       p.Value,
+					CurrentPeriod:  m.Period,
+					Current:        m.Value,
+				})
+				break
+			}
+		}
+	}
+
+	for _, s := range current.Series {
+		prev, ok := findSeries(previous.Series, s)
+		if !ok {
+			continue
+		}
+		for _, point := range s.Points {
+			for _, p := range prev.Points {
+				if p.Period != point.Period || p.Adjustment != point.Adjustment {
+					continue
+				}
+				if math.Abs(p.Value-point.Value) > 1e-9 {
+					comparison.Revisions = append(comparison.Revisions, Revision{
+						Series:   s.Name,
+						Period:   point.Period,
+						Unit:     s.Unit,
+						Previous: p.Value,
+						Current:  point.Value,
+					})
+				}
+				break
+			}
+		}
+	}
+
+	return comparison, nil
+}
+
+// findSeries finds the series with the same name and unit as s.
+func findSeries(series []Series, s Series) (Series, bool) {
+	for _, candidate := range series {
+		if strings.EqualFold(candidate.Name, s.Name) && candidate.Unit == s.Unit {
+			return candidate, true
+		}
+	}
+	return Series{}, false
+}
+
+// formatFigure formats a figure with thousands separators and no trailing
+// zeros. It rounds away floating point noise, e.g. from 0.4 - 0.3.
+func formatFigure(v float64) string {
+	s := strconv.FormatFloat(math.Round(v*1e6)/1e6, 'f', -1, 64)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	for i := len(whole) - 3; i > 0; i -= 3 {
+		whole = whole[:i] + "," + whole[i:]
+	}
+	if hasFrac {
+		whole += "." + frac
+	}
+	return sign + whole
+}
@@ -0,0 +1,160 @@
+package bls
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompareReleasesHeadlines(t *testing.T) {
+	from := func(v float64) *float64 { return &v }
+
+	previous := ReleaseSnapshot{
+		Key:     "empsit-2024-12",
+		Release: "Employment Situation",
+		Metrics: []Metric{
+			{Name: "Nonfarm payroll change", Period: "November 2024", Value: 227, Unit: "thousand", Adjustment: SeasonallyAdjusted},
+			{Name: "Unemployment rate", Period: "November 2024", Value: 4.2, Unit: "percent", Adjustment: SeasonallyAdjusted},
+			{Name: "Nonfarm payroll change", Period: "October 2024", Value: 36, Unit: "thousand", Adjustment: SeasonallyAdjusted, Previous: from(12)},
+		},
+	}
+	current := ReleaseSnapshot{
+		Key:     "empsit-2025-01",
+		Release: "Employment Situation",
+		Metrics: []Metric{
+			{Name: "Nonfarm payroll change", Period: "December 2024", Value: 256, Unit: "thousand", Adjustment: SeasonallyAdjusted},
+			{Name: "Unemployment rate", Period: "December 2024", Value: 4.1, Unit: "percent", Adjustment: SeasonallyAdjusted},
+			{Name: "Nonfarm payroll change", Period: "October 2024", Value: 43, Unit: "thousand", Adjustment: SeasonallyAdjusted, Previous: from(36)},
+			{Name: "Nonfarm payroll change", Period: "November 2024", Value: 212, Unit: "thousand", Adjustment: SeasonallyAdjusted, Previous: from(227)},
+		},
+	}
+
+	comparison, err := CompareReleases(previous, current)
+	if err != nil {
+		t.Fatalf("CompareReleases() returned an error: %v", err)
+	}
+	if comparison.PreviousKey != "empsit-2024-12" || comparison.Empty() {
+		t.Fatalf("Unexpected comparison %+v", comparison)
+	}
+
+	want := strings.Join([]string{
+		"- Nonfarm payroll change: 256 thousand in December 2024, up from 227 in November 2024",
+		"- Unemployment rate: 4.1 percent in December 2024, down from 4.2 in November 2024",
+		"- Nonfarm payroll change (October 2024): revised up by 7, from 36 to 43 (thousand)",
+		"- Nonfarm payroll change (November 2024): revised down by 15, from 227 to 212 (thousand)",
+		"- Nonfarm payroll change: October 2024 and November 2024 revised down by a combined 8 (thousand)",
+	}, "\n")
+	if got := comparison.Context(); got != want {
+		t.Errorf("Context() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCompareReleasesTables(t *testing.T) {
+	start := time.Date(2025, 1, 10, 13, 30, 0, 0, time.UTC)
+	event := Event{Summary: "Employment Situation", UID: "empsit", Start: &start}
+	current := NewReleaseSnapshot(event, readFixture(t, "empsit_release.html"), start)
+
+	if current.Release != "Employment Situation" || len(current.Metrics) == 0 || len(current.Series) == 0 {
+		t.Fatalf("Unexpected snapshot %+v", current)
+	}
+
+	// The previous release had a lower unemployment rate for November
+	previous := ReleaseSnapshot{Key: "empsit-previous", Release: current.Release}
+	for _, s := range current.Series {
+		s.Points = append([]Point(nil), s.Points...)
+		if s.Name == "Unemployment rate" {
+			for i := range s.Points {
+				if s.Points[i].Period == "Nov. 2024" {
+					s.Points[i].Value = 4.0
+				}
+			}
+		}
+		previous.Series = append(previous.Series, s)
+	}
+
+	comparison, err := CompareReleases(previous, current)
+	if err != nil {
+		t.Fatalf("CompareReleases() returned an error: %v", err)
+	}
+	if len(comparison.Revisions) != 1 {
+		t.Fatalf("Got revisions %+v, want one", comparison.Revisions)
+	}
+	want := "Unemployment rate (Nov. 2024): revised up by 0.2, from 4 to 4.2"
+	if got := comparison.Revisions[0].String(); got != want {
+		t.Errorf("Revision = %q, want %q", got, want)
+	}
+	// Rates aren't summed
+	if strings.Contains(comparison.Context(), "combined") {
+		t.Errorf("Context() shouldn't combine rate revisions: %s", comparison.Context())
+	}
+}
+
+func TestComparisonContextLimitsTableRevisions(t *testing.T) {
+	// A seasonal factor revision touches every cell of every table
+	comparison := Comparison{
+		Revisions: []Revision{
+			{Series: "Nonfarm payroll change", Period: "November 2024", Unit: "thousand", Previous: 227, Current: 212, Headline: true},
+		},
+	}
+	for i := 0; i < 200; i++ {
+		comparison.Revisions = append(comparison.Revisions, Revision{
+			Series:   fmt.Sprintf("Series %d", i),
+			Period:   "Nov. 2024",
+			Previous: 100,
+			Current:  100 + float64(i%50)/10,
+		})
+	}
+
+	lines := strings.Split(comparison.Context(), "\n")
+	if len(lines) != 1+maxTableRevisions+1 {
+		t.Fatalf("Context() has %d lines, want %d:\n%s", len(lines), 1+maxTableRevisions+1, strings.Join(lines, "\n"))
+	}
+	if !strings.HasPrefix(lines[0], "- Nonfarm payroll change (November 2024)") {
+		t.Errorf("Expected the headline revision first, got %q", lines[0])
+	}
+	// The largest revisions are those to series 49, 99, 149, 199 and then 48
+	for _, line := range lines[1 : 1+maxTableRevisions] {
+		if !strings.Contains(line, "revised up by 4.9") && !strings.Contains(line, "Series 48 ") {
+			t.Errorf("Expected one of the largest revisions, got %q", line)
+		}
+	}
+	if want := fmt.Sprintf("- %d smaller revisions to other figures", 200-maxTableRevisions); lines[len(lines)-1] != want {
+		t.Errorf("Last line = %q, want %q", lines[len(lines)-1], want)
+	}
+}
+
+func TestCompareReleasesErrors(t *testing.T) {
+	cpi := ReleaseSnapshot{Key: "cpi-1", Release: "Consumer Price Index"}
+	ppi := ReleaseSnapshot{Key: "ppi-1", Release: "Producer Price Index"}
+
+	if _, err := CompareReleases(cpi, ppi); err == nil {
+		t.Error("Expected an error comparing different releases")
+	}
+	if _, err := CompareReleases(cpi, cpi); err == nil {
+		t.Error("Expected an error comparing a release with itself")
+	}
+}
+
+func TestTrendReversal(t *testing.T) {
+	trend := Trend{Name: "CPI all items, m/m", Unit: "percent", PreviousPeriod: "Nov. 2024", Previous: 0.1, CurrentPeriod: "Dec. 2024", Current: -0.1}
+	want := "CPI all items, m/m: -0.1 percent in Dec. 2024, down from 0.1 in Nov. 2024, turning negative"
+	if got := trend.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatFigure(t *testing.T) {
+	testCases := map[float64]string{
+		159486:              "159,486",
+		-1234567.5:          "-1,234,567.5",
+		0.4 - 0.3:           "0.1",
+		42:                  "42",
+		0.10000000000000003: "0.1",
+	}
+	for v, want := range testCases {
+		if got := formatFigure(v); got != want {
+			t.Errorf("formatFigure(%v) = %q, want %q", v, got, want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package bls
+
+import (
+	"sort"
+	"time"
+)
+
+// CalendarDiff describes how a calendar changed between two copies.
+type CalendarDiff struct {
+	Added       []Event
+	Removed     []Event
+	Rescheduled []RescheduledEvent
+}
+
+// RescheduledEvent is an event whose start or end time moved.
+type RescheduledEvent struct {
+	Previous Event
+	Current  Event
+}
+
+// Empty reports whether nothing changed.
+func (d CalendarDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Rescheduled) == 0
+}
+
+// DiffEvents compares two versions of a calendar. Events are matched by UID so
+// a moved release shows up as rescheduled rather than removed and added; events
+// without a UID are matched by Key and so can only be added or removed. Each
+// list is ordered by start time.
+func DiffEvents(previous, current []Event) CalendarDiff {
+	var diff CalendarDiff
+
+	before := make(map[string]Event, len(previous))
+	for _, event := range previous {
+		before[eventIdentity(event)] = event
+	}
+
+	seen := make(map[string]bool, len(current))
+	for _, event := range current {
+		id := eventIdentity(event)
+		seen[id] = true
+
+		old, ok := before[id]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, event)
+		case !sameTime(old.Start, event.Start) || !sameTime(old.End, event.End):
+			diff.Rescheduled = append(diff.Rescheduled, RescheduledEvent{Previous: old, Current: event})
+		}
+	}
+
+	for _, event := range previous {
+		if !seen[eventIdentity(event)] {
+			diff.Removed = append(diff.Removed, event)
+		}
+	}
+
+	sortEvents(diff.Added)
+	sortEvents(diff.Removed)
+	sort.SliceStable(diff.Rescheduled, func(i, j int) bool {
+		return startsBefore(diff.Rescheduled[i].Current, diff.Rescheduled[j].Current)
+	})
+	return diff
+}
+
+func eventIdentity(event Event) string {
+	if event.UID != "" {
+		return "uid:" + event.UID
+	}
+	return "key:" + event.Key()
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func sortEvents(events []Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return startsBefore(events[i], events[j])
+	})
+}
+
+// startsBefore orders events by start time, with undated events last.
+func startsBefore(a, b Event) bool {
+	if a.Start == nil || b.Start == nil {
+		return a.Start != nil && b.Start == nil
+	}
+	return a.Start.Before(*b.Start)
+}
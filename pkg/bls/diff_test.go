@@ -0,0 +1,126 @@
+package bls
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiffEvents(t *testing.T) {
+	at := func(day int) *time.Time {
+		start := time.Date(2025, 1, day, 13, 30, 0, 0, time.UTC)
+		return &start
+	}
+
+	previous := []Event{
+		{Summary: "Consumer Price Index", UID: "cpi", Start: at(15)},
+		{Summary: "Producer Price Index", UID: "ppi", Start: at(16)},
+		{Summary: "Real Earnings", UID: "realer", Start: at(15)},
+		{Summary: "No UID", Start: at(20)},
+	}
+
+	testCases := []struct {
+		name            string
+		current         []Event
+		wantAdded       []string
+		wantRemoved     []string
+		wantRescheduled []string
+	}{
+		{
+			name:    "Unchanged",
+			current: previous,
+		},
+		{
+			name: "Added, removed and rescheduled",
+			current: []Event{
+				{Summary: "Consumer Price Index", UID: "cpi", Start: at(17)},
+				{Summary: "Real Earnings", UID: "realer", Start: at(15)},
+				{Summary: "Employment Situation", UID: "empsit", Start: at(10)},
+				{Summary: "No UID", Start: at(20)},
+			},
+			wantAdded:       []string{"empsit"},
+			wantRemoved:     []string{"ppi"},
+			wantRescheduled: []string{"cpi"},
+		},
+		{
+			name: "Events without a UID can't be rescheduled",
+			current: []Event{
+				{Summary: "Consumer Price Index", UID: "cpi", Start: at(15)},
+				{Summary: "Producer Price Index", UID: "ppi", Start: at(16)},
+				{Summary: "Real Earnings", UID: "realer", Start: at(15)},
+				{Summary: "No UID", Start: at(21)},
+			},
+			wantAdded:   []string{""},
+			wantRemoved: []string{""},
+		},
+	}
+
+	uids := func(events []Event) []string {
+		var ids []string
+		for _, e := range events {
+			ids = append(ids, e.UID)
+		}
+		return ids
+	}
+	equal := func(a, b []string) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			diff := DiffEvents(previous, tc.current)
+
+			if got := uids(diff.Added); !equal(got, tc.wantAdded) {
+				t.Errorf("Added = %v, want %v", got, tc.wantAdded)
+			}
+			if got := uids(diff.Removed); !equal(got, tc.wantRemoved) {
+				t.Errorf("Removed = %v, want %v", got, tc.wantRemoved)
+			}
+			var rescheduled []string
+			for _, r := range diff.Rescheduled {
+				rescheduled = append(rescheduled, r.Current.UID)
+			}
+			if !equal(rescheduled, tc.wantRescheduled) {
+				t.Errorf("Rescheduled = %v, want %v", rescheduled, tc.wantRescheduled)
+			}
+			if empty := tc.wantAdded == nil && tc.wantRemoved == nil && tc.wantRescheduled == nil; diff.Empty() != empty {
+				t.Errorf("Empty() = %v, want %v", diff.Empty(), empty)
+			}
+		})
+	}
+}
+
+func TestEventsBetween(t *testing.T) {
+	base := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) *time.Time {
+		start := base.Add(time.Duration(hours) * time.Hour)
+		return &start
+	}
+
+	events := []Event{
+		{UID: "late", Start: at(30)},
+		{UID: "second", Start: at(10)},
+		{UID: "cursor", Start: at(0)},
+		{UID: "first", Start: at(5)},
+		{UID: "edge", Start: at(24)},
+		{UID: "undated"},
+	}
+
+	got := EventsBetween(events, base, base.Add(24*time.Hour))
+	want := []string{"first", "second", "edge"}
+	if len(got) != len(want) {
+		t.Fatalf("EventsBetween() returned %d events, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].UID != want[i] {
+			t.Errorf("event %d = %q, want %q", i, got[i].UID, want[i])
+		}
+	}
+}
@@ -0,0 +1,444 @@
+package bls
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoExtractor is returned by ExtractHeadline for releases without a
+// release-specific extractor; callers should fall back to ExtractSummary.
+var ErrNoExtractor = errors.New("no headline extractor for release")
+
+// Metric is a single headline figure.
+type Metric struct {
+	Name       string
+	Period     string
+	Value      float64
+	Unit       string
+	Adjustment Adjustment
+	// Previous is the figure before it was revised, for revisions to an
+	// earlier period.
+	Previous *float64 `json:",omitempty"`
+}
+
+// String formats the metric for a prompt, e.g.
+// "CPI all items, m/m (Dec. 2024, seasonally adjusted): 0.4 percent".
+func (m Metric) String() string {
+	var qualifiers []string
+	if m.Period != "" {
+		qualifiers = append(qualifiers, m.Period)
+	}
+	switch m.Adjustment {
+	case SeasonallyAdjusted:
+		qualifiers = append(qualifiers, "seasonally adjusted")
+	case NotSeasonallyAdjusted:
+		qualifiers = append(qualifiers, "not seasonally adjusted")
+	}
+
+	s := m.Name
+	if len(qualifiers) > 0 {
+		s += " (" + strings.Join(qualifiers, ", ") + ")"
+	}
+	s += ": " + strconv.FormatFloat(m.Value, 'f', -1, 64) + " " + m.Unit
+	if m.Previous != nil {
+		s += ", revised from " + strconv.FormatFloat(*m.Previous, 'f', -1, 64)
+	}
+	return s
+}
+
+// FormatMetrics formats metrics one per line for a prompt.
+func FormatMetrics(metrics []Metric) string {
+	lines := make([]string, len(metrics))
+	for i, m := range metrics {
+		lines[i] = "- " + m.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Headline holds the key figures of a release.
+type Headline interface {
+	Metrics() []Metric
+}
+
+// headlineExtractors holds the release-specific extractors, keyed by the
+// release summaries in the registry.
+var headlineExtractors = map[string]func(html string) (Headline, error){
+	"Consumer Price Index":                   func(html string) (Headline, error) { return ExtractCPIHeadline(html) },
+	"Employment Situation":                   func(html string) (Headline, error) { return ExtractEmploymentSituationHeadline(html) },
+	"Producer Price Index":                   func(html string) (Headline, error) { return ExtractPPIHeadline(html) },
+	"Job Openings and Labor Turnover Survey": func(html string) (Headline, error) { return ExtractJOLTSHeadline(html) },
+}
+
+// HasHeadlineExtractor reports whether ExtractHeadline supports the event's
+// release, looked up in the default registry.
+func HasHeadlineExtractor(event Event) bool {
+	return DefaultClient.HasHeadlineExtractor(event)
+}
+
+// HasHeadlineExtractor reports whether ExtractHeadline supports the event's
+// release, looked up in the client's registry.
+func (c *Client) HasHeadlineExtractor(event Event) bool {
+	_, ok := headlineExtractors[c.releaseSummary(event)]
+	return ok
+}
+
+// ExtractHeadline runs the extractor for the event's release on its HTML,
+// looking the release up in the default registry.
+func ExtractHeadline(event Event, html string) (Headline, error) {
+	return DefaultClient.ExtractHeadline(event, html)
+}
+
+// ExtractHeadline runs the extractor for the event's release on its HTML. The
+// release is looked up in the client's registry, the same one ReleaseURL uses.
+// It returns ErrNoExtractor when the release doesn't have one.
+func (c *Client) ExtractHeadline(event Event, html string) (Headline, error) {
+	summary := c.releaseSummary(event)
+	extract, ok := headlineExtractors[summary]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoExtractor, summary)
+	}
+
+	headline, err := extract(html)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract %s headline: %w", summary, err)
+	}
+	return headline, nil
+}
+
+// releaseSummary returns the registry's summary for the event's release, so
+// slightly different calendar summaries still find their extractor.
+func (c *Client) releaseSummary(event Event) string {
+	if release, ok := c.LookupRelease(event.Summary); ok {
+		return release.Summary
+	}
+	return strings.TrimSpace(event.Summary)
+}
+
+// CPIHeadline holds the all items and core (all items less food and energy)
+// changes from the CPI release.
+type CPIHeadline struct {
+	Period string
+	// AllItemsMoM and CoreMoM are seasonally adjusted one-month percent changes.
+	AllItemsMoM float64
+	CoreMoM     float64
+	// AllItemsYoY and CoreYoY are unadjusted 12-month percent changes.
+	AllItemsYoY float64
+	CoreYoY     float64
+}
+
+// Metrics implements Headline.
+func (h *CPIHeadline) Metrics() []Metric {
+	return []Metric{
+		{Name: "CPI all items, m/m", Period: h.Period, Value: h.AllItemsMoM, Unit: "percent", Adjustment: SeasonallyAdjusted},
+		{Name: "CPI all items, y/y", Period: h.Period, Value: h.AllItemsYoY, Unit: "percent", Adjustment: NotSeasonallyAdjusted},
+		{Name: "CPI core (less food and energy), m/m", Period: h.Period, Value: h.CoreMoM, Unit: "percent", Adjustment: SeasonallyAdjusted},
+		{Name: "CPI core (less food and energy), y/y", Period: h.Period, Value: h.CoreYoY, Unit: "percent", Adjustment: NotSeasonallyAdjusted},
+	}
+}
+
+// ExtractCPIHeadline reads table A of the CPI release, the table of percent
+// changes whose last seasonally adjusted column is the latest month and whose
+// unadjusted column is the 12-month change.
+func ExtractCPIHeadline(html string) (*CPIHeadline, error) {
+	tables, err := ParseTables(html)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, table := range tables {
+		allItems, ok := table.Row("All items")
+		if !ok {
+			continue
+		}
+		core, ok := table.Row("All items less food and energy")
+		if !ok {
+			continue
+		}
+
+		mom, yoy := -1, -1
+		for i, col := range table.Columns {
+			switch col.Adjustment {
+			case SeasonallyAdjusted:
+				mom = i
+			case NotSeasonallyAdjusted:
+				if yoy == -1 {
+					yoy = i
+				}
+			}
+		}
+		if mom == -1 || yoy == -1 {
+			continue
+		}
+
+		h := &CPIHeadline{Period: table.Columns[mom].Period}
+		for _, f := range []struct {
+			row Row
+			col int
+			dst *float64
+		}{
+			{allItems, mom, &h.AllItemsMoM},
+			{allItems, yoy, &h.AllItemsYoY},
+			{core, mom, &h.CoreMoM},
+			{core, yoy, &h.CoreYoY},
+		} {
+			if f.col >= len(f.row.Cells) || !f.row.Cells[f.col].HasValue {
+				return nil, fmt.Errorf("missing %s figure in %q", f.row.Label, table.Title)
+			}
+			*f.dst = f.row.Cells[f.col].Value
+		}
+		return h, nil
+	}
+
+	return nil, errors.New("no table with all items and core CPI changes found")
+}
+
+// PayrollRevision is a revision to an earlier month's payroll change.
+type PayrollRevision struct {
+	Month string
+	// From and To are the previous and revised changes, in thousands.
+	From float64
+	To   float64
+}
+
+// EmploymentSituationHeadline holds the payroll and household survey headlines.
+type EmploymentSituationHeadline struct {
+	Period string
+	// PayrollChange is the change in total nonfarm payroll employment, in thousands.
+	PayrollChange float64
+	// UnemploymentRate is in percent.
+	UnemploymentRate float64
+	Revisions        []PayrollRevision
+}
+
+// Metrics implements Headline.
+func (h *EmploymentSituationHeadline) Metrics() []Metric {
+	metrics := []Metric{
+		{Name: "Nonfarm payroll change", Period: h.Period, Value: h.PayrollChange, Unit: "thousand", Adjustment: SeasonallyAdjusted},
+		{Name: "Unemployment rate", Period: h.Period, Value: h.UnemploymentRate, Unit: "percent", Adjustment: SeasonallyAdjusted},
+	}
+	for _, r := range h.Revisions {
+		from := r.From
+		metrics = append(metrics, Metric{
+			Name:       "Nonfarm payroll change",
+			Period:     revisionPeriod(r.Month, h.Period),
+			Value:      r.To,
+			Unit:       "thousand",
+			Adjustment: SeasonallyAdjusted,
+			Previous:   &from,
+		})
+	}
+	return metrics
+}
+
+// revisionPeriod adds the year to a revised month, e.g. "November" in the
+// "December 2024" release is "November 2024", and "December" in the "January
+// 2025" release is "December 2024".
+func revisionPeriod(month, period string) string {
+	revised, err := time.Parse("January", month)
+	if err != nil {
+		return month
+	}
+	current, err := time.Parse("January 2006", period)
+	if err != nil {
+		return month
+	}
+	year := current.Year()
+	if revised.Month() > current.Month() {
+		year--
+	}
+	return fmt.Sprintf("%s %d", revised.Month(), year)
+}
+
+var (
+	payrollPattern      = regexp.MustCompile(`(?i)total nonfarm payroll employment ` + changeVerb + `(?: by)? ([\d,]+)`)
+	unemploymentPattern = regexp.MustCompile(`(?i)unemployment rate[^.]*?(?:at|to) (\d+(?:\.\d+)?) percent`)
+	revisionPattern     = regexp.MustCompile(`(?i)change (?:in total nonfarm payroll employment )?for (\w+) was revised (?:up|down) by [\d,]+,? from ([+\-−]?[\d,]+) to ([+\-−]?[\d,]+)`)
+)
+
+// ExtractEmploymentSituationHeadline reads the opening paragraphs of the
+// Employment Situation release.
+func ExtractEmploymentSituationHeadline(html string) (*EmploymentSituationHeadline, error) {
+	text, err := releaseText(html)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &EmploymentSituationHeadline{Period: releasePeriod(text)}
+
+	m := payrollPattern.FindStringSubmatch(text)
+	if m == nil {
+		return nil, errors.New("payroll change not found")
+	}
+	change, err := signedNumber(m[1], m[2])
+	if err != nil {
+		return nil, err
+	}
+	h.PayrollChange = change / 1000
+
+	m = unemploymentPattern.FindStringSubmatch(text)
+	if m == nil {
+		return nil, errors.New("unemployment rate not found")
+	}
+	h.UnemploymentRate, _ = strconv.ParseFloat(m[1], 64)
+
+	for _, m := range revisionPattern.FindAllStringSubmatch(text, -1) {
+		from, err1 := parseNumber(m[2])
+		to, err2 := parseNumber(m[3])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		h.Revisions = append(h.Revisions, PayrollRevision{Month: m[1], From: from / 1000, To: to / 1000})
+	}
+
+	return h, nil
+}
+
+// PPIHeadline holds the final demand changes from the PPI release.
+type PPIHeadline struct {
+	Period string
+	// FinalDemandMoM is the seasonally adjusted one-month percent change.
+	FinalDemandMoM float64
+	// FinalDemandYoY is the unadjusted 12-month percent change, if stated.
+	FinalDemandYoY *float64
+}
+
+// Metrics implements Headline.
+func (h *PPIHeadline) Metrics() []Metric {
+	metrics := []Metric{
+		{Name: "PPI final demand, m/m", Period: h.Period, Value: h.FinalDemandMoM, Unit: "percent", Adjustment: SeasonallyAdjusted},
+	}
+	if h.FinalDemandYoY != nil {
+		metrics = append(metrics, Metric{Name: "PPI final demand, y/y", Period: h.Period, Value: *h.FinalDemandYoY, Unit: "percent", Adjustment: NotSeasonallyAdjusted})
+	}
+	return metrics
+}
+
+var (
+	ppiMoMPattern = regexp.MustCompile(`(?i)Producer Price Index for final demand ` + changeVerb + `(?: (\d+(?:\.\d+)?) percent)?`)
+	ppiYoYPattern = regexp.MustCompile(`(?i)` + changeVerb + ` (\d+(?:\.\d+)?) percent for the 12 months ended`)
+)
+
+// ExtractPPIHeadline reads the opening paragraphs of the PPI release.
+func ExtractPPIHeadline(html string) (*PPIHeadline, error) {
+	text, err := releaseText(html)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &PPIHeadline{Period: releasePeriod(text)}
+
+	m := ppiMoMPattern.FindStringSubmatch(text)
+	if m == nil {
+		return nil, errors.New("final demand change not found")
+	}
+	if h.FinalDemandMoM, err = signedNumber(m[1], m[2]); err != nil {
+		return nil, err
+	}
+
+	if m := ppiYoYPattern.FindStringSubmatch(text); m != nil {
+		if yoy, err := signedNumber(m[1], m[2]); err == nil {
+			h.FinalDemandYoY = &yoy
+		}
+	}
+
+	return h, nil
+}
+
+// JOLTSHeadline holds the job openings level from the JOLTS release.
+type JOLTSHeadline struct {
+	Period string
+	// Openings is the number of job openings, in millions.
+	Openings float64
+}
+
+// Metrics implements Headline.
+func (h *JOLTSHeadline) Metrics() []Metric {
+	return []Metric{
+		{Name: "Job openings", Period: h.Period, Value: h.Openings, Unit: "million", Adjustment: SeasonallyAdjusted},
+	}
+}
+
+var openingsPattern = regexp.MustCompile(`(?i)number of job openings[^.]*?(?:at|to) (\d+(?:\.\d+)?) million`)
+
+// ExtractJOLTSHeadline reads the opening paragraphs of the JOLTS release.
+func ExtractJOLTSHeadline(html string) (*JOLTSHeadline, error) {
+	text, err := releaseText(html)
+	if err != nil {
+		return nil, err
+	}
+
+	m := openingsPattern.FindStringSubmatch(text)
+	if m == nil {
+		return nil, errors.New("job openings not found")
+	}
+	openings, _ := strconv.ParseFloat(m[1], 64)
+
+	return &JOLTSHeadline{Period: releasePeriod(text), Openings: openings}, nil
+}
+
+// changeVerb captures how releases describe a change, for signedNumber.
+const changeVerb = `(increased|rose|advanced|climbed|edged up|jumped|decreased|declined|fell|edged down|dropped|was unchanged|were unchanged|was essentially unchanged|changed little)`
+
+// signedNumber applies the direction of verb to an unsigned figure. Verbs that
+// describe no change give zero when no figure is stated.
+func signedNumber(verb, figure string) (float64, error) {
+	verb = strings.ToLower(verb)
+	if figure == "" {
+		if strings.Contains(verb, "unchanged") {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("no figure after %q", verb)
+	}
+
+	value, err := parseNumber(figure)
+	if err != nil {
+		return 0, err
+	}
+	switch verb {
+	case "decreased", "declined", "fell", "edged down", "dropped":
+		value = -value
+	}
+	return value, nil
+}
+
+// parseNumber parses figures such as "256,000", "+227,000" or "−0.1".
+func parseNumber(s string) (float64, error) {
+	s = strings.NewReplacer(",", "", "−", "-", "+", "").Replace(strings.TrimSpace(s))
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid figure %q", s)
+	}
+	return value, nil
+}
+
+// releaseText returns the release's summary text with whitespace collapsed so
+// patterns can span the original line breaks.
+func releaseText(html string) (string, error) {
+	summary, err := ExtractSummary(html)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(strings.Fields(summary), " "), nil
+}
+
+var releasePeriodPattern = regexp.MustCompile(`(?i)\b(January|February|March|April|May|June|July|August|September|October|November|December|(?:FIRST|SECOND|THIRD|FOURTH) QUARTER) (\d{4})\b`)
+
+// releasePeriod finds the reference period in a release's title line, e.g.
+// "THE EMPLOYMENT SITUATION -- DECEMBER 2024" gives "December 2024".
+func releasePeriod(text string) string {
+	// The embargo line names the release date, so look for a title in capitals first
+	for _, m := range releasePeriodPattern.FindAllStringSubmatch(text, -1) {
+		if m[1] == strings.ToUpper(m[1]) {
+			words := strings.Fields(strings.ToLower(m[1]))
+			for i, w := range words {
+				words[i] = strings.ToUpper(w[:1]) + w[1:]
+			}
+			return strings.Join(words, " ") + " " + m[2]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,168 @@
+package bls
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestExtractHeadline(t *testing.T) {
+	yoy := 3.3
+
+	testCases := []struct {
+		summary string
+		fixture string
+		want    Headline
+	}{
+		{
+			summary: "Consumer Price Index",
+			fixture: "cpi_release.html",
+			want: &CPIHeadline{
+				Period:      "Dec. 2024",
+				AllItemsMoM: 0.4,
+				AllItemsYoY: 2.9,
+				CoreMoM:     0.2,
+				CoreYoY:     3.2,
+			},
+		},
+		{
+			summary: "Employment Situation",
+			fixture: "empsit_release.html",
+			want: &EmploymentSituationHeadline{
+				Period:           "December 2024",
+				PayrollChange:    256,
+				UnemploymentRate: 4.1,
+				Revisions: []PayrollRevision{
+					{Month: "October", From: 36, To: 43},
+					{Month: "November", From: 227, To: 212},
+				},
+			},
+		},
+		{
+			summary: "Producer Price Index",
+			fixture: "ppi_release.html",
+			want: &PPIHeadline{
+				Period:         "December 2024",
+				FinalDemandMoM: 0.2,
+				FinalDemandYoY: &yoy,
+			},
+		},
+		{
+			summary: "Job Openings and Labor Turnover Survey",
+			fixture: "jolts_release.html",
+			want: &JOLTSHeadline{
+				Period:   "November 2024",
+				Openings: 8.1,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.summary, func(t *testing.T) {
+			event := Event{Summary: tc.summary}
+			if !HasHeadlineExtractor(event) {
+				t.Fatalf("HasHeadlineExtractor() = false for %q", tc.summary)
+			}
+
+			got, err := ExtractHeadline(event, readFixture(t, tc.fixture))
+			if err != nil {
+				t.Fatalf("ExtractHeadline() returned an error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ExtractHeadline() = %+v, want %+v", got, tc.want)
+			}
+			if len(got.Metrics()) == 0 {
+				t.Error("Metrics() returned nothing")
+			}
+		})
+	}
+}
+
+func TestExtractHeadlineErrors(t *testing.T) {
+	t.Run("No extractor", func(t *testing.T) {
+		_, err := ExtractHeadline(Event{Summary: "Real Earnings"}, "<pre>text</pre>")
+		if !errors.Is(err, ErrNoExtractor) {
+			t.Errorf("Expected ErrNoExtractor, got %v", err)
+		}
+	})
+
+	t.Run("Release without the headline", func(t *testing.T) {
+		// The CPI fixture has no payroll figures
+		_, err := ExtractHeadline(Event{Summary: "Employment Situation"}, readFixture(t, "cpi_release.html"))
+		if err == nil || errors.Is(err, ErrNoExtractor) {
+			t.Errorf("Expected an extraction error, got %v", err)
+		}
+	})
+}
+
+func TestClientHeadlineRegistry(t *testing.T) {
+	registry, err := LoadRegistry(strings.NewReader(`{"releases": [
+		{"summary": "Consumer Price Index", "path": "/news.release/cpi.nr0.htm", "aliases": ["CPI"]}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient()
+	client.Registry = registry
+	event := Event{Summary: "CPI"}
+
+	// The alias is only known to the client's registry
+	if HasHeadlineExtractor(event) {
+		t.Fatal("Expected the default registry not to know the alias")
+	}
+	if !client.HasHeadlineExtractor(event) {
+		t.Error("HasHeadlineExtractor() = false with the client's registry")
+	}
+	if _, err := client.ExtractHeadline(event, readFixture(t, "cpi_release.html")); err != nil {
+		t.Errorf("ExtractHeadline() returned an error: %v", err)
+	}
+	if got := client.NewReleaseSnapshot(event, readFixture(t, "cpi_release.html"), time.Now()).Release; got != "Consumer Price Index" {
+		t.Errorf("Snapshot release = %q, want %q", got, "Consumer Price Index")
+	}
+}
+
+func TestSignedNumber(t *testing.T) {
+	testCases := []struct {
+		verb, figure string
+		want         float64
+		expectErr    bool
+	}{
+		{"increased", "0.4", 0.4, false},
+		{"Edged down", "0.1", -0.1, false},
+		{"fell", "1,200", -1200, false},
+		{"was unchanged", "", 0, false},
+		{"rose", "", 0, true},
+	}
+
+	for _, tc := range testCases {
+		got, err := signedNumber(tc.verb, tc.figure)
+		if (err != nil) != tc.expectErr || got != tc.want {
+			t.Errorf("signedNumber(%q, %q) = %v, %v; want %v (error %v)", tc.verb, tc.figure, got, err, tc.want, tc.expectErr)
+		}
+	}
+}
+
+func TestFormatMetrics(t *testing.T) {
+	h := &JOLTSHeadline{Period: "November 2024", Openings: 8.1}
+	got := FormatMetrics(h.Metrics())
+	want := "- Job openings (November 2024, seasonally adjusted): 8.1 million"
+	if got != want {
+		t.Errorf("FormatMetrics() = %q, want %q", got, want)
+	}
+	if !strings.HasPrefix(FormatMetrics((&CPIHeadline{}).Metrics()), "- CPI all items, m/m") {
+		t.Error("Expected CPI metrics to start with the all items change")
+	}
+}
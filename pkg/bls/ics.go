@@ -0,0 +1,671 @@
+package bls
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// This file holds a small synchronous RFC 5545 (iCalendar) parser. It only
+// understands what's needed to read release calendars: VEVENTs, their text
+// properties and start/end times, and VTIMEZONE definitions with yearly
+// BYMONTH/BYDAY rules, which is how every published US time zone is expressed.
+
+// ParseCalendar reads an iCalendar stream and returns its events in the order
+// they appear. Times with a TZID are resolved using the calendar's own VTIMEZONE
+// definitions, falling back to the IANA database for names such as
+// America/New_York. Floating times use the calendar's X-WR-TIMEZONE, or UTC.
+func ParseCalendar(r io.Reader) ([]Event, error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	calendars, err := buildComponents(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for _, cal := range calendars {
+		calEvents, err := cal.events()
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, calEvents...)
+	}
+	return events, nil
+}
+
+// contentLine is an unfolded line along with the physical line it started on.
+type contentLine struct {
+	num  int
+	text string
+}
+
+// unfoldLines splits the stream into logical content lines, joining lines that
+// begin with a space or tab onto the previous one (RFC 5545 section 3.1).
+func unfoldLines(r io.Reader) ([]contentLine, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var lines []contentLine
+	num := 0
+	for scanner.Scan() {
+		num++
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if num == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+
+		if len(text) > 0 && (text[0] == ' ' || text[0] == '\t') {
+			if len(lines) == 0 {
+				return nil, fmt.Errorf("line %d: continuation line with nothing to continue", num)
+			}
+			lines[len(lines)-1].text += text[1:]
+			continue
+		}
+		if text == "" {
+			continue
+		}
+		lines = append(lines, contentLine{num: num, text: text})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// property is a parsed content line: NAME;PARAM=value:VALUE.
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parseProperty parses a single content line. Names and parameter names are
+// upper-cased; quoted parameter values have their quotes removed.
+func parseProperty(line string) (property, error) {
+	var prop property
+
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return prop, fmt.Errorf("malformed content line %q", line)
+	}
+	prop.name = strings.ToUpper(line[:i])
+
+	for line[i] == ';' {
+		i++
+		eq := strings.IndexByte(line[i:], '=')
+		if eq <= 0 {
+			return prop, fmt.Errorf("malformed parameter in %s", prop.name)
+		}
+		name := strings.ToUpper(line[i : i+eq])
+		i += eq + 1
+
+		// The value runs to the next unquoted ';' or ':'
+		var value strings.Builder
+		quoted := false
+		for ; i < len(line); i++ {
+			c := line[i]
+			if c == '"' {
+				quoted = !quoted
+				continue
+			}
+			if !quoted && (c == ';' || c == ':') {
+				break
+			}
+			value.WriteByte(c)
+		}
+		if quoted || i == len(line) {
+			return prop, fmt.Errorf("unterminated parameter %s in %s", name, prop.name)
+		}
+
+		if prop.params == nil {
+			prop.params = make(map[string]string)
+		}
+		prop.params[name] = value.String()
+	}
+
+	prop.value = line[i+1:]
+	return prop, nil
+}
+
+// unescapeText reverses the TEXT escaping of RFC 5545 section 3.3.11.
+func unescapeText(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			// \\, \; and \, all stand for the character itself
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// component is a BEGIN/END block with its properties and nested blocks.
+type component struct {
+	name     string
+	line     int
+	props    []property
+	children []*component
+}
+
+// get returns the first property with the given name.
+func (c *component) get(name string) (property, bool) {
+	for _, p := range c.props {
+		if p.name == name {
+			return p, true
+		}
+	}
+	return property{}, false
+}
+
+// buildComponents assembles the content lines into a tree and returns the
+// top-level VCALENDAR components.
+func buildComponents(lines []contentLine) ([]*component, error) {
+	var calendars []*component
+	var stack []*component
+
+	for _, line := range lines {
+		prop, err := parseProperty(line.text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line.num, err)
+		}
+
+		switch prop.name {
+		case "BEGIN":
+			comp := &component{name: strings.ToUpper(prop.value), line: line.num}
+			if len(stack) == 0 {
+				if comp.name != "VCALENDAR" {
+					return nil, fmt.Errorf("line %d: expected BEGIN:VCALENDAR, got BEGIN:%s", line.num, prop.value)
+				}
+				calendars = append(calendars, comp)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, comp)
+			}
+			stack = append(stack, comp)
+		case "END":
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("line %d: END:%s without BEGIN", line.num, prop.value)
+			}
+			if top := stack[len(stack)-1]; top.name != strings.ToUpper(prop.value) {
+				return nil, fmt.Errorf("line %d: END:%s does not match BEGIN:%s on line %d", line.num, prop.value, top.name, top.line)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("line %d: property %s outside of VCALENDAR", line.num, prop.name)
+			}
+			top := stack[len(stack)-1]
+			top.props = append(top.props, prop)
+		}
+	}
+
+	if len(stack) > 0 {
+		top := stack[len(stack)-1]
+		return nil, fmt.Errorf("BEGIN:%s on line %d is never closed", top.name, top.line)
+	}
+	if len(calendars) == 0 {
+		return nil, fmt.Errorf("no VCALENDAR found")
+	}
+	return calendars, nil
+}
+
+// events converts the VEVENTs of a VCALENDAR component.
+func (c *component) events() ([]Event, error) {
+	zones := make(map[string]*vtimezone)
+	for _, child := range c.children {
+		if child.name != "VTIMEZONE" {
+			continue
+		}
+		tz, err := parseVTimezone(child)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", child.line, err)
+		}
+		zones[tz.id] = tz
+	}
+
+	// X-WR-TIMEZONE is a non-standard hint, so an unknown name is ignored
+	res := &zoneResolver{zones: zones, floating: utcZone{}}
+	if prop, ok := c.get("X-WR-TIMEZONE"); ok {
+		if zone, err := res.lookup(prop.value); err == nil {
+			res.floating = zone
+		}
+	}
+
+	var events []Event
+	for _, child := range c.children {
+		if child.name != "VEVENT" {
+			continue
+		}
+		event, err := res.event(child)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", child.line, err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// zone turns a wall clock time, carried in a UTC time.Time, into an instant.
+type zone interface {
+	at(wall time.Time) time.Time
+}
+
+// utcZone treats wall clock times as UTC.
+type utcZone struct{}
+
+func (utcZone) at(wall time.Time) time.Time { return wall }
+
+// locationZone resolves wall clock times with the IANA database.
+type locationZone struct{ loc *time.Location }
+
+func (z locationZone) at(wall time.Time) time.Time {
+	return time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, z.loc)
+}
+
+// zoneResolver maps TZIDs to zones for one calendar.
+type zoneResolver struct {
+	zones    map[string]*vtimezone
+	floating zone
+}
+
+// lookup prefers the calendar's own VTIMEZONE over the IANA database, since
+// calendars such as bls.gov's use non-IANA names like US-Eastern.
+func (r *zoneResolver) lookup(tzid string) (zone, error) {
+	if tz, ok := r.zones[tzid]; ok {
+		return tz, nil
+	}
+	loc, err := time.LoadLocation(tzid)
+	if err != nil || tzid == "" || tzid == "Local" {
+		return nil, fmt.Errorf("unknown time zone %q", tzid)
+	}
+	return locationZone{loc}, nil
+}
+
+// event converts a VEVENT component.
+func (r *zoneResolver) event(c *component) (Event, error) {
+	event := Event{Properties: make(map[string]string)}
+	for _, p := range c.props {
+		if _, seen := event.Properties[p.name]; !seen {
+			event.Properties[p.name] = unescapeText(p.value)
+		}
+	}
+
+	event.Summary = event.Properties["SUMMARY"]
+	event.Description = event.Properties["DESCRIPTION"]
+	event.Location = event.Properties["LOCATION"]
+	event.URL = event.Properties["URL"]
+	event.UID = event.Properties["UID"]
+
+	if p, ok := c.get("DTSTART"); ok {
+		start, err := r.dateTime(p)
+		if err != nil {
+			return event, err
+		}
+		event.Start = &start
+	}
+
+	if p, ok := c.get("DTEND"); ok {
+		end, err := r.dateTime(p)
+		if err != nil {
+			return event, err
+		}
+		event.End = &end
+	} else if p, ok := c.get("DURATION"); ok && event.Start != nil {
+		d, err := parseDuration(p.value)
+		if err != nil {
+			return event, fmt.Errorf("DURATION: %w", err)
+		}
+		end := event.Start.Add(d)
+		event.End = &end
+	}
+
+	return event, nil
+}
+
+// dateTime parses a DATE or DATE-TIME property value (RFC 5545 sections 3.3.4
+// and 3.3.5) into an instant.
+func (r *zoneResolver) dateTime(p property) (time.Time, error) {
+	value := p.value
+	if p.params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		wall, err := time.Parse("20060102", value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid %s date %q", p.name, value)
+		}
+		return r.resolve(p, wall)
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid %s time %q", p.name, value)
+		}
+		return t, nil
+	}
+
+	wall, err := time.Parse("20060102T150405", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s time %q", p.name, value)
+	}
+	return r.resolve(p, wall)
+}
+
+func (r *zoneResolver) resolve(p property, wall time.Time) (time.Time, error) {
+	tzid, ok := p.params["TZID"]
+	if !ok {
+		return r.floating.at(wall), nil
+	}
+	z, err := r.lookup(tzid)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", p.name, err)
+	}
+	return z.at(wall), nil
+}
+
+// durationPattern matches RFC 5545 DURATION values such as PT30M or P1DT2H.
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+func parseDuration(value string) (time.Duration, error) {
+	m := durationPattern.FindStringSubmatch(value)
+	if m == nil || strings.HasSuffix(value, "T") {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	found := false
+	for i, unit := range units {
+		if m[i+2] == "" {
+			continue
+		}
+		found = true
+		n, err := strconv.Atoi(m[i+2])
+		if err != nil || n > 100000 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		d += time.Duration(n) * unit
+	}
+	if !found {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+// vtimezone is a VTIMEZONE definition made up of STANDARD and DAYLIGHT observances.
+type vtimezone struct {
+	id          string
+	observances []observance
+}
+
+// observance is one STANDARD or DAYLIGHT block. Its onsets are wall clock times
+// in the offset in effect before the change (TZOFFSETFROM).
+type observance struct {
+	name  string
+	start time.Time
+	from  int
+	to    int
+	rule  *yearlyRule
+}
+
+// yearlyRule is the subset of RRULE used by time zone definitions: the nth
+// weekday of a month, every year, optionally until a given instant.
+type yearlyRule struct {
+	month   time.Month
+	week    int
+	weekday time.Weekday
+	until   time.Time
+}
+
+func parseVTimezone(c *component) (*vtimezone, error) {
+	id, ok := c.get("TZID")
+	if !ok || id.value == "" {
+		return nil, fmt.Errorf("VTIMEZONE without TZID")
+	}
+	tz := &vtimezone{id: id.value}
+
+	for _, child := range c.children {
+		if child.name != "STANDARD" && child.name != "DAYLIGHT" {
+			continue
+		}
+		obs, err := parseObservance(child)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", tz.id, child.name, err)
+		}
+		tz.observances = append(tz.observances, obs)
+	}
+
+	if len(tz.observances) == 0 {
+		return nil, fmt.Errorf("VTIMEZONE %s has no STANDARD or DAYLIGHT block", tz.id)
+	}
+	return tz, nil
+}
+
+func parseObservance(c *component) (observance, error) {
+	obs := observance{name: c.name}
+	if p, ok := c.get("TZNAME"); ok {
+		obs.name = unescapeText(p.value)
+	}
+
+	p, ok := c.get("DTSTART")
+	if !ok {
+		return obs, fmt.Errorf("missing DTSTART")
+	}
+	start, err := time.Parse("20060102T150405", p.value)
+	if err != nil {
+		return obs, fmt.Errorf("invalid DTSTART %q", p.value)
+	}
+	obs.start = start
+
+	for _, f := range []struct {
+		name string
+		dst  *int
+	}{{"TZOFFSETFROM", &obs.from}, {"TZOFFSETTO", &obs.to}} {
+		p, ok := c.get(f.name)
+		if !ok {
+			return obs, fmt.Errorf("missing %s", f.name)
+		}
+		offset, err := parseUTCOffset(p.value)
+		if err != nil {
+			return obs, fmt.Errorf("%s: %w", f.name, err)
+		}
+		*f.dst = offset
+	}
+
+	if p, ok := c.get("RRULE"); ok {
+		rule, err := parseYearlyRule(p.value)
+		if err != nil {
+			return obs, err
+		}
+		obs.rule = rule
+	}
+	return obs, nil
+}
+
+// parseUTCOffset parses a UTC-OFFSET value such as -0500 or +053000 into seconds.
+func parseUTCOffset(value string) (int, error) {
+	if (len(value) != 5 && len(value) != 7) || (value[0] != '+' && value[0] != '-') {
+		return 0, fmt.Errorf("invalid UTC offset %q", value)
+	}
+
+	var parts [3]int
+	for i := 0; i*2+1 < len(value); i++ {
+		n, err := strconv.Atoi(value[i*2+1 : i*2+3])
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid UTC offset %q", value)
+		}
+		parts[i] = n
+	}
+	if parts[0] > 23 || parts[1] > 59 || parts[2] > 59 {
+		return 0, fmt.Errorf("invalid UTC offset %q", value)
+	}
+
+	seconds := parts[0]*3600 + parts[1]*60 + parts[2]
+	if value[0] == '-' {
+		seconds = -seconds
+	}
+	return seconds, nil
+}
+
+var (
+	byDayPattern = regexp.MustCompile(`^([+-]?[1-5])(SU|MO|TU|WE|TH|FR|SA)$`)
+	weekdays     = map[string]time.Weekday{
+		"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+		"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+	}
+)
+
+func parseYearlyRule(value string) (*yearlyRule, error) {
+	parts := make(map[string]string)
+	for _, part := range strings.Split(value, ";") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("malformed RRULE %q", value)
+		}
+		parts[strings.ToUpper(k)] = strings.ToUpper(v)
+	}
+
+	if parts["FREQ"] != "YEARLY" {
+		return nil, fmt.Errorf("unsupported RRULE %q: only FREQ=YEARLY is supported", value)
+	}
+
+	rule := &yearlyRule{}
+	month, err := strconv.Atoi(parts["BYMONTH"])
+	if err != nil || month < 1 || month > 12 {
+		return nil, fmt.Errorf("unsupported RRULE %q: BYMONTH must be a single month", value)
+	}
+	rule.month = time.Month(month)
+
+	m := byDayPattern.FindStringSubmatch(parts["BYDAY"])
+	if m == nil {
+		return nil, fmt.Errorf("unsupported RRULE %q: BYDAY must be a single ordinal weekday", value)
+	}
+	rule.week, _ = strconv.Atoi(m[1])
+	rule.weekday = weekdays[m[2]]
+
+	if until, ok := parts["UNTIL"]; ok {
+		t, err := time.Parse("20060102T150405Z", until)
+		if err != nil {
+			t, err = time.Parse("20060102", until)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid UNTIL in RRULE %q", value)
+		}
+		rule.until = t
+	}
+	return rule, nil
+}
+
+// onset returns the wall clock time this observance takes effect in year, if it does.
+func (o observance) onset(year int) (time.Time, bool) {
+	if o.rule == nil {
+		return o.start, o.start.Year() == year
+	}
+	if year < o.start.Year() {
+		return time.Time{}, false
+	}
+
+	day, ok := nthWeekday(year, o.rule.month, o.rule.week, o.rule.weekday)
+	if !ok {
+		return time.Time{}, false
+	}
+	onset := time.Date(year, o.rule.month, day, o.start.Hour(), o.start.Minute(), o.start.Second(), 0, time.UTC)
+	if onset.Before(o.start) {
+		return time.Time{}, false
+	}
+	// UNTIL is an instant, onsets are wall clock times in the old offset
+	if !o.rule.until.IsZero() && onset.Add(-time.Duration(o.from)*time.Second).After(o.rule.until) {
+		return time.Time{}, false
+	}
+	return onset, true
+}
+
+// lastOnset returns the latest wall clock time up to wall at which this
+// observance took effect, however many years back that is.
+func (o observance) lastOnset(wall time.Time) (time.Time, bool) {
+	if o.rule == nil {
+		return o.start, !o.start.After(wall)
+	}
+
+	// A rule that ended stopped producing onsets in its UNTIL year
+	year := wall.Year()
+	if !o.rule.until.IsZero() && o.rule.until.Year() < year {
+		year = o.rule.until.Year()
+	}
+	for ; year >= o.start.Year(); year-- {
+		if onset, ok := o.onset(year); ok && !onset.After(wall) {
+			return onset, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// nthWeekday returns the day of the month of the nth weekday, counting from the
+// end of the month when n is negative.
+func nthWeekday(year int, month time.Month, n int, weekday time.Weekday) (int, bool) {
+	var day int
+	if n > 0 {
+		first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		day = 1 + (int(weekday)-int(first.Weekday())+7)%7 + (n-1)*7
+	} else {
+		last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+		day = last.Day() - (int(last.Weekday())-int(weekday)+7)%7 + (n+1)*7
+	}
+
+	daysInMonth := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return day, day >= 1 && day <= daysInMonth
+}
+
+// at resolves a wall clock time using the most recent observance onset.
+func (tz *vtimezone) at(wall time.Time) time.Time {
+	var current *observance
+	var currentOnset time.Time
+	for i := range tz.observances {
+		obs := &tz.observances[i]
+		onset, ok := obs.lastOnset(wall)
+		if !ok {
+			continue
+		}
+		if current == nil || onset.After(currentOnset) {
+			current, currentOnset = obs, onset
+		}
+	}
+
+	// Before the first onset the zone is at the earliest observance's old offset
+	offset, name := 0, tz.id
+	if current != nil {
+		offset, name = current.to, current.name
+	} else {
+		earliest := tz.observances[0]
+		for _, obs := range tz.observances[1:] {
+			if obs.start.Before(earliest.start) {
+				earliest = obs
+			}
+		}
+		offset = earliest.from
+	}
+
+	loc := time.FixedZone(name, offset)
+	return time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, loc)
+}
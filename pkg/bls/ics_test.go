@@ -0,0 +1,432 @@
+package bls
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestParseCalendarFixtures(t *testing.T) {
+	type wantEvent struct {
+		summary     string
+		description string
+		location    string
+		url         string
+		uid         string
+		start       string // RFC 3339, empty for none
+		end         string // RFC 3339, empty for none
+		zone        string // name of the start time's zone, empty to skip
+	}
+
+	testCases := []struct {
+		file string
+		want []wantEvent
+	}{
+		{
+			file: "bls_sample.ics",
+			want: []wantEvent{
+				{
+					summary:     "Consumer Price Index",
+					description: "Consumer Price Index for December 2024, with annual averages.\nSee the full release for details.",
+					location:    "Washington, DC",
+					url:         "https://www.bls.gov/news.release/cpi.nr0.htm",
+					uid:         "cpi-2025-01@bls.gov",
+					start:       "2025-01-15T08:30:00-05:00",
+					end:         "2025-01-15T09:00:00-05:00",
+					zone:        "EST",
+				},
+				{
+					summary:     "Employment Situation",
+					description: "Employment Situation for June 2025",
+					uid:         "empsit-2025-07@bls.gov",
+					start:       "2025-07-03T08:30:00-04:00",
+					end:         "2025-07-03T09:00:00-04:00",
+					zone:        "EDT",
+				},
+				{
+					// Floating time, resolved with X-WR-TIMEZONE
+					summary: "Job Openings and Labor Turnover Survey",
+					uid:     "jolts-2025-11@bls.gov",
+					start:   "2025-11-04T10:00:00-05:00",
+					zone:    "EST",
+				},
+			},
+		},
+		{
+			file: "iana_tzid.ics",
+			want: []wantEvent{
+				{
+					summary: "Producer Price Index",
+					uid:     "ppi-2025-03@example.com",
+					start:   "2025-03-13T08:30:00-04:00",
+					end:     "2025-03-13T09:00:00-04:00",
+				},
+				{
+					summary: "Independence Day",
+					uid:     "holiday-2025@example.com",
+					start:   "2025-07-04T00:00:00Z",
+				},
+			},
+		},
+		{
+			file: "utc_multi.ics",
+			want: []wantEvent{
+				{
+					summary: "Real\nEarnings; monthly",
+					uid:     "realer-2025-02@example.com",
+					start:   "2025-02-12T13:30:00Z",
+					end:     "2025-02-12T14:00:00Z",
+				},
+				{
+					summary: "Employment Cost Index",
+					uid:     "eci-2025-01@example.com",
+					start:   "2025-01-31T13:30:00Z",
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.file, func(t *testing.T) {
+			f, err := os.Open(filepath.Join("testdata", tc.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			events, err := ParseCalendar(f)
+			if err != nil {
+				t.Fatalf("ParseCalendar() returned an error: %v", err)
+			}
+			if len(events) != len(tc.want) {
+				t.Fatalf("ParseCalendar() returned %d events, want %d", len(events), len(tc.want))
+			}
+
+			for i, want := range tc.want {
+				got := events[i]
+				if got.Summary != want.summary {
+					t.Errorf("event %d: Summary = %q, want %q", i, got.Summary, want.summary)
+				}
+				if got.Description != want.description {
+					t.Errorf("event %d: Description = %q, want %q", i, got.Description, want.description)
+				}
+				if got.Location != want.location {
+					t.Errorf("event %d: Location = %q, want %q", i, got.Location, want.location)
+				}
+				if got.URL != want.url {
+					t.Errorf("event %d: URL = %q, want %q", i, got.URL, want.url)
+				}
+				if got.UID != want.uid {
+					t.Errorf("event %d: UID = %q, want %q", i, got.UID, want.uid)
+				}
+
+				checkTime := func(field string, got *time.Time, want string) {
+					if want == "" {
+						if got != nil {
+							t.Errorf("event %d: %s = %v, want none", i, field, got)
+						}
+						return
+					}
+					if got == nil {
+						t.Errorf("event %d: %s is nil, want %s", i, field, want)
+						return
+					}
+					if !got.Equal(mustTime(t, want)) {
+						t.Errorf("event %d: %s = %v, want %s", i, field, got, want)
+					}
+				}
+				checkTime("Start", got.Start, want.start)
+				checkTime("End", got.End, want.end)
+
+				if want.zone != "" && got.Start != nil {
+					if name, _ := got.Start.Zone(); name != want.zone {
+						t.Errorf("event %d: Start zone = %q, want %q", i, name, want.zone)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseCalendarProperties(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "bls_sample.ics"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	events, err := ParseCalendar(f)
+	if err != nil {
+		t.Fatalf("ParseCalendar() returned an error: %v", err)
+	}
+
+	props := events[0].Properties
+	if props["CATEGORIES"] != "Prices" {
+		t.Errorf("CATEGORIES = %q, want %q", props["CATEGORIES"], "Prices")
+	}
+	if props["CLASS"] != "PUBLIC" {
+		t.Errorf("CLASS = %q, want %q", props["CLASS"], "PUBLIC")
+	}
+	// The VALARM's DESCRIPTION must not leak into the event
+	if !strings.HasPrefix(props["DESCRIPTION"], "Consumer Price Index") {
+		t.Errorf("DESCRIPTION = %q, want the event's own description", props["DESCRIPTION"])
+	}
+}
+
+func TestParseCalendarErrors(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       string
+		expectedErr string
+	}{
+		{
+			name:        "Empty input",
+			input:       "",
+			expectedErr: "no VCALENDAR found",
+		},
+		{
+			name:        "Unclosed calendar",
+			input:       "BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VEVENT\n",
+			expectedErr: "never closed",
+		},
+		{
+			name:        "Mismatched END",
+			input:       "BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VCALENDAR\n",
+			expectedErr: "does not match",
+		},
+		{
+			name:        "Line without colon",
+			input:       "BEGIN:VCALENDAR\nNOT A CONTENT LINE\nEND:VCALENDAR\n",
+			expectedErr: "line 2",
+		},
+		{
+			name:        "Unterminated quoted parameter",
+			input:       "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;TZID=\"US-Eastern:20250115T083000\nEND:VEVENT\nEND:VCALENDAR\n",
+			expectedErr: "unterminated parameter",
+		},
+		{
+			name:        "Unknown TZID",
+			input:       "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;TZID=Mars/Olympus:20250115T083000\nEND:VEVENT\nEND:VCALENDAR\n",
+			expectedErr: "unknown time zone",
+		},
+		{
+			name:        "Invalid DTSTART",
+			input:       "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:2025-01-15\nEND:VEVENT\nEND:VCALENDAR\n",
+			expectedErr: "invalid DTSTART",
+		},
+		{
+			name: "Unsupported time zone rule",
+			input: "BEGIN:VCALENDAR\nBEGIN:VTIMEZONE\nTZID:Odd\nBEGIN:STANDARD\nDTSTART:20000101T000000\n" +
+				"RRULE:FREQ=MONTHLY;BYDAY=1SU\nTZOFFSETFROM:+0000\nTZOFFSETTO:+0100\nEND:STANDARD\nEND:VTIMEZONE\nEND:VCALENDAR\n",
+			expectedErr: "only FREQ=YEARLY",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseCalendar(strings.NewReader(tc.input))
+			if err == nil {
+				t.Fatal("Expected an error but got nil")
+			}
+			if !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Errorf("Expected error containing '%s', but got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestVTimezoneTransitions(t *testing.T) {
+	input := `BEGIN:VCALENDAR
+BEGIN:VTIMEZONE
+TZID:US-Eastern
+BEGIN:STANDARD
+DTSTART:16011104T020000
+RRULE:FREQ=YEARLY;BYDAY=1SU;BYMONTH=11
+TZOFFSETFROM:-0400
+TZOFFSETTO:-0500
+END:STANDARD
+BEGIN:DAYLIGHT
+DTSTART:16010311T020000
+RRULE:FREQ=YEARLY;BYDAY=2SU;BYMONTH=3
+TZOFFSETFROM:-0500
+TZOFFSETTO:-0400
+END:DAYLIGHT
+END:VTIMEZONE
+BEGIN:VEVENT
+DTSTART;TZID=US-Eastern:20250309T015959
+END:VEVENT
+BEGIN:VEVENT
+DTSTART;TZID=US-Eastern:20250309T030000
+END:VEVENT
+BEGIN:VEVENT
+DTSTART;TZID=US-Eastern:20251102T005959
+END:VEVENT
+BEGIN:VEVENT
+DTSTART;TZID=US-Eastern:20251102T020000
+END:VEVENT
+BEGIN:VEVENT
+DTSTART;TZID=US-Eastern:20251231T235959
+END:VEVENT
+END:VCALENDAR
+`
+	want := []string{
+		"2025-03-09T01:59:59-05:00",
+		"2025-03-09T03:00:00-04:00",
+		"2025-11-02T00:59:59-04:00",
+		"2025-11-02T02:00:00-05:00",
+		"2025-12-31T23:59:59-05:00",
+	}
+
+	events, err := ParseCalendar(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseCalendar() returned an error: %v", err)
+	}
+	if len(events) != len(want) {
+		t.Fatalf("ParseCalendar() returned %d events, want %d", len(events), len(want))
+	}
+
+	// The VTIMEZONE must agree with the IANA rules for the same zone
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, w := range want {
+		got := *events[i].Start
+		if !got.Equal(mustTime(t, w)) {
+			t.Errorf("event %d: Start = %v, want %s", i, got, w)
+		}
+		_, offset := got.Zone()
+		if _, ianaOffset := got.In(newYork).Zone(); ianaOffset != offset {
+			t.Errorf("event %d: offset disagrees with America/New_York", i)
+		}
+	}
+}
+
+func TestVTimezoneOldObservances(t *testing.T) {
+	testCases := []struct {
+		name     string
+		timezone string
+		start    string
+		want     string
+	}{
+		{
+			name: "Single observance from decades back",
+			timezone: `BEGIN:STANDARD
+DTSTART:19451015T000000
+TZOFFSETFROM:+0630
+TZOFFSETTO:+0530
+TZNAME:IST
+END:STANDARD`,
+			start: "20250115T083000",
+			want:  "2025-01-15T08:30:00+05:30",
+		},
+		{
+			name: "Rules that ended years back",
+			timezone: `BEGIN:STANDARD
+DTSTART:19701025T030000
+RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=10;UNTIL=20141026T000000Z
+TZOFFSETFROM:+0400
+TZOFFSETTO:+0300
+END:STANDARD
+BEGIN:DAYLIGHT
+DTSTART:19700329T020000
+RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=3;UNTIL=20140330T000000Z
+TZOFFSETFROM:+0300
+TZOFFSETTO:+0400
+END:DAYLIGHT`,
+			start: "20250715T083000",
+			want:  "2025-07-15T08:30:00+03:00",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			input := "BEGIN:VCALENDAR\nBEGIN:VTIMEZONE\nTZID:Old\n" + tc.timezone +
+				"\nEND:VTIMEZONE\nBEGIN:VEVENT\nDTSTART;TZID=Old:" + tc.start + "\nEND:VEVENT\nEND:VCALENDAR\n"
+
+			events, err := ParseCalendar(strings.NewReader(input))
+			if err != nil {
+				t.Fatalf("ParseCalendar() returned an error: %v", err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("ParseCalendar() returned %d events, want 1", len(events))
+			}
+			if got := *events[0].Start; !got.Equal(mustTime(t, tc.want)) {
+				t.Errorf("Start = %v, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNthWeekday(t *testing.T) {
+	testCases := []struct {
+		year    int
+		month   time.Month
+		n       int
+		weekday time.Weekday
+		want    int
+		ok      bool
+	}{
+		{2025, time.March, 2, time.Sunday, 9, true},
+		{2025, time.November, 1, time.Sunday, 2, true},
+		{2025, time.October, -1, time.Sunday, 26, true},
+		{2025, time.March, -1, time.Sunday, 30, true},
+		{2025, time.February, 5, time.Monday, 0, false},
+	}
+
+	for _, tc := range testCases {
+		got, ok := nthWeekday(tc.year, tc.month, tc.n, tc.weekday)
+		if ok != tc.ok || (ok && got != tc.want) {
+			t.Errorf("nthWeekday(%d, %s, %d, %s) = %d, %v; want %d, %v",
+				tc.year, tc.month, tc.n, tc.weekday, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func FuzzParseCalendar(f *testing.F) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "*.ics"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, path := range fixtures {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(string(data))
+	}
+	f.Add("BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;TZID=\"a;b:c\":20250101T000000\nDURATION:-P1W2DT3H\nEND:VEVENT\nEND:VCALENDAR\n")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		events, err := ParseCalendar(strings.NewReader(input))
+		if err != nil {
+			return
+		}
+
+		// Parsing must be deterministic
+		again, err := ParseCalendar(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("second parse failed: %v", err)
+		}
+		if len(events) != len(again) {
+			t.Fatalf("parse returned %d events, then %d", len(events), len(again))
+		}
+		for i := range events {
+			if events[i].Key() != again[i].Key() {
+				t.Fatalf("event %d: key %q, then %q", i, events[i].Key(), again[i].Key())
+			}
+		}
+	})
+}
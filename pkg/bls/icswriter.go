@@ -0,0 +1,98 @@
+package bls
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// calendarProdID identifies calendars written by WriteCalendar.
+const calendarProdID = "-//bls_agent//BLS release schedule//EN"
+
+// maxLineOctets is the longest content line RFC 5545 allows before folding.
+const maxLineOctets = 75
+
+// WriteCalendar writes events as an iCalendar stream that ParseCalendar and
+// calendar apps can read. Times are written in UTC, and name, when set, is
+// shown by most apps as the calendar's title.
+func WriteCalendar(w io.Writer, name string, events []Event) error {
+	bw := bufio.NewWriter(w)
+	write := func(line string) {
+		bw.WriteString(foldLine(line))
+	}
+
+	write("BEGIN:VCALENDAR")
+	write("VERSION:2.0")
+	write("PRODID:" + calendarProdID)
+	write("CALSCALE:GREGORIAN")
+	if name != "" {
+		write("X-WR-CALNAME:" + escapeText(name))
+	}
+
+	stamp := formatUTC(time.Now())
+	for _, event := range events {
+		write("BEGIN:VEVENT")
+		uid := event.UID
+		if uid == "" {
+			uid = event.Key()
+		}
+		write("UID:" + escapeText(uid))
+		write("DTSTAMP:" + stamp)
+		if event.Start != nil {
+			write("DTSTART:" + formatUTC(*event.Start))
+		}
+		if event.End != nil {
+			write("DTEND:" + formatUTC(*event.End))
+		}
+		write("SUMMARY:" + escapeText(event.Summary))
+		if event.Description != "" {
+			write("DESCRIPTION:" + escapeText(event.Description))
+		}
+		if event.Location != "" {
+			write("LOCATION:" + escapeText(event.Location))
+		}
+		if event.URL != "" {
+			// URL values are URIs, not TEXT, so they aren't escaped
+			write("URL:" + event.URL)
+		}
+		write("END:VEVENT")
+	}
+
+	write("END:VCALENDAR")
+	return bw.Flush()
+}
+
+// formatUTC formats t as an RFC 5545 UTC DATE-TIME.
+func formatUTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeText applies the TEXT escaping of RFC 5545 section 3.3.11, the reverse
+// of unescapeText.
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// foldLine ends a content line with CRLF, folding it onto continuation lines
+// that begin with a space so no line is longer than maxLineOctets. Multi-byte
+// characters are never split.
+func foldLine(line string) string {
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space counts towards the continuation line's length
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package bls
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteCalendar(t *testing.T) {
+	start := time.Date(2025, 1, 15, 13, 30, 0, 0, time.UTC)
+	end := start.Add(30 * time.Minute)
+	events := []Event{
+		{
+			Summary:     "Consumer Price Index",
+			Description: "Prices, energy; food, and \\ more\nSecond line",
+			URL:         "https://www.bls.gov/news.release/cpi.nr0.htm",
+			UID:         "cpi-2025-01@bls.gov",
+			Start:       &start,
+			End:         &end,
+		},
+		{
+			Summary: "Productivity and Costs by Industry: Selected Service-Providing Industries — revised estimates",
+			Start:   &start,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCalendar(&buf, "BLS releases", events); err != nil {
+		t.Fatalf("WriteCalendar() returned an error: %v", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("Line longer than %d octets: %q", maxLineOctets, line)
+		}
+	}
+	if !strings.Contains(buf.String(), "X-WR-CALNAME:BLS releases\r\n") {
+		t.Errorf("Expected the calendar name, got:\n%s", buf.String())
+	}
+
+	parsed, err := ParseCalendar(&buf)
+	if err != nil {
+		t.Fatalf("ParseCalendar() returned an error: %v", err)
+	}
+	if len(parsed) != len(events) {
+		t.Fatalf("Got %d events back, want %d", len(parsed), len(events))
+	}
+	for i, want := range events {
+		got := parsed[i]
+		if got.Summary != want.Summary || got.Description != want.Description || got.URL != want.URL {
+			t.Errorf("Event %d = %+v, want %+v", i, got, want)
+		}
+		if got.Start == nil || !got.Start.Equal(*want.Start) {
+			t.Errorf("Event %d start = %v, want %v", i, got.Start, want.Start)
+		}
+	}
+	if parsed[0].UID != "cpi-2025-01@bls.gov" || parsed[0].End == nil || !parsed[0].End.Equal(end) {
+		t.Errorf("Event 0 = %+v, want its UID and end time kept", parsed[0])
+	}
+	// Events without a UID get their key
+	if parsed[1].UID != events[1].Key() {
+		t.Errorf("Event 1 UID = %q, want %q", parsed[1].UID, events[1].Key())
+	}
+}
+
+func TestFoldLine(t *testing.T) {
+	testCases := map[string]string{
+		"SUMMARY:Short":               "SUMMARY:Short\r\n",
+		strings.Repeat("a", 75):       strings.Repeat("a", 75) + "\r\n",
+		strings.Repeat("a", 76):       strings.Repeat("a", 75) + "\r\n a\r\n",
+		strings.Repeat("a", 74) + "é": strings.Repeat("a", 74) + "\r\n é\r\n",
+	}
+	for line, want := range testCases {
+		if got := foldLine(line); got != want {
+			t.Errorf("foldLine(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
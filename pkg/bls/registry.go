@@ -0,0 +1,277 @@
+package bls
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// releasesJSON is the registry shipped with the package. It can be replaced at
+// runtime with LoadRegistryFile, e.g. when bls.gov moves a release.
+//
+//go:embed releases.json
+var releasesJSON []byte
+
+// minSimilarity is how close a calendar summary must be to a registered one to
+// count as a fuzzy match.
+const minSimilarity = 0.85
+
+// maxTypos is the most edits a summary may be off by to match on spelling.
+const maxTypos = 2
+
+// Release maps a calendar event summary to its news release.
+type Release struct {
+	// Summary is the event summary as it appears in the calendar.
+	Summary string `json:"summary"`
+	// Path is the news release path relative to the client's base URL.
+	Path string `json:"path"`
+	// Aliases are other summaries the release has been published under.
+	Aliases []string `json:"aliases,omitempty"`
+	// Family groups related releases, e.g. "prices" for the CPI, PPI and
+	// import and export price indexes.
+	Family string `json:"family,omitempty"`
+}
+
+// Code returns the release's short code, e.g. "cpi" for
+// /news.release/cpi.nr0.htm.
+func (r Release) Code() string {
+	name := r.Path[strings.LastIndex(r.Path, "/")+1:]
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// Registry looks up the news release for calendar events. The zero value is
+// not usable; create one with LoadRegistry.
+type Registry struct {
+	releases []Release
+	// index maps normalized summaries and aliases to positions in releases.
+	index map[string]int
+}
+
+// registryFile is the layout of a registry file.
+type registryFile struct {
+	Releases []Release `json:"releases"`
+}
+
+// LoadRegistry reads a registry from JSON and validates it. Every release
+// needs a summary and a path to a .htm page, and no two summaries or aliases
+// may normalize to the same text.
+func LoadRegistry(r io.Reader) (*Registry, error) {
+	var file registryFile
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to decode release registry: %w", err)
+	}
+	if len(file.Releases) == 0 {
+		return nil, fmt.Errorf("release registry is empty")
+	}
+
+	registry := &Registry{releases: file.Releases, index: make(map[string]int)}
+	for i, release := range file.Releases {
+		if strings.TrimSpace(release.Summary) == "" {
+			return nil, fmt.Errorf("release %d has no summary", i)
+		}
+		if !strings.HasPrefix(release.Path, "/") || !strings.HasSuffix(release.Path, ".htm") {
+			return nil, fmt.Errorf("release %q has an invalid path %q", release.Summary, release.Path)
+		}
+		for _, name := range append([]string{release.Summary}, release.Aliases...) {
+			key := normalizeSummary(name)
+			if other, ok := registry.index[key]; ok {
+				return nil, fmt.Errorf("release %q conflicts with %q", name, file.Releases[other].Summary)
+			}
+			registry.index[key] = i
+		}
+	}
+	return registry, nil
+}
+
+// LoadRegistryFile reads a registry from a JSON file.
+func LoadRegistryFile(path string) (*Registry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open release registry: %w", err)
+	}
+	defer f.Close()
+	return LoadRegistry(f)
+}
+
+var (
+	defaultRegistry     *Registry
+	defaultRegistryErr  error
+	defaultRegistryOnce sync.Once
+)
+
+// DefaultRegistry returns the registry embedded in the package.
+func DefaultRegistry() *Registry {
+	defaultRegistryOnce.Do(func() {
+		defaultRegistry, defaultRegistryErr = LoadRegistry(bytes.NewReader(releasesJSON))
+	})
+	if defaultRegistryErr != nil {
+		// The embedded file is covered by the tests, so this is a build problem
+		panic(fmt.Sprintf("invalid embedded release registry: %v", defaultRegistryErr))
+	}
+	return defaultRegistry
+}
+
+// Releases returns every release in the registry.
+func (r *Registry) Releases() []Release {
+	return append([]Release(nil), r.releases...)
+}
+
+// Lookup finds the release for an event summary. Summaries are compared after
+// normalizing case, punctuation and spacing. When nothing matches exactly the
+// closest release is used, as long as it is similar enough and no other
+// release is just as close.
+func (r *Registry) Lookup(summary string) (Release, bool) {
+	key := normalizeSummary(summary)
+	if key == "" {
+		return Release{}, false
+	}
+	if i, ok := r.index[key]; ok {
+		return r.releases[i], true
+	}
+
+	best, bestScore, tied := -1, 0.0, false
+	for name, i := range r.index {
+		score := similarity(key, name)
+		switch {
+		case score > bestScore:
+			best, bestScore, tied = i, score, false
+		case score == bestScore && i != best:
+			tied = true
+		}
+	}
+	if best < 0 || bestScore < minSimilarity || tied {
+		return Release{}, false
+	}
+	return r.releases[best], true
+}
+
+// Families returns the distinct release families in the registry, sorted.
+func (r *Registry) Families() []string {
+	seen := make(map[string]bool)
+	var families []string
+	for _, release := range r.releases {
+		if release.Family != "" && !seen[release.Family] {
+			seen[release.Family] = true
+			families = append(families, release.Family)
+		}
+	}
+	sort.Strings(families)
+	return families
+}
+
+// InFamilies returns the events whose release belongs to one of families,
+// compared case-insensitively. Events without a release are left out. With no
+// families every event is returned.
+func (r *Registry) InFamilies(events []Event, families ...string) []Event {
+	if len(families) == 0 {
+		return events
+	}
+
+	var filtered []Event
+	for _, event := range events {
+		release, ok := r.Lookup(event.Summary)
+		if !ok {
+			continue
+		}
+		for _, family := range families {
+			if strings.EqualFold(strings.TrimSpace(family), release.Family) {
+				filtered = append(filtered, event)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// Unmapped returns the distinct summaries of events that have no release in
+// the registry, sorted.
+func (r *Registry) Unmapped(events []Event) []string {
+	seen := make(map[string]bool)
+	var unmapped []string
+	for _, event := range events {
+		summary := strings.TrimSpace(event.Summary)
+		if seen[summary] {
+			continue
+		}
+		seen[summary] = true
+		if _, ok := r.Lookup(summary); !ok {
+			unmapped = append(unmapped, summary)
+		}
+	}
+	sort.Strings(unmapped)
+	return unmapped
+}
+
+// normalizeSummary lowercases s, turns punctuation into spaces and collapses
+// runs of spaces.
+func normalizeSummary(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// similarity scores two normalized summaries between 0 and 1. It takes the
+// better of the word overlap, which tolerates extra or reordered words, and
+// the edit distance, which tolerates typos. Only a couple of edits count as a
+// typo; over a long summary more would also accept e.g. "Annual" for "Monthly".
+func similarity(a, b string) float64 {
+	wordsA, wordsB := strings.Fields(a), strings.Fields(b)
+	counts := make(map[string]int, len(wordsA))
+	for _, w := range wordsA {
+		counts[w]++
+	}
+	common := 0
+	for _, w := range wordsB {
+		if counts[w] > 0 {
+			counts[w]--
+			common++
+		}
+	}
+	dice := 2 * float64(common) / float64(len(wordsA)+len(wordsB))
+
+	ra, rb := []rune(a), []rune(b)
+	distance := levenshtein(ra, rb)
+	if distance > maxTypos {
+		return dice
+	}
+	edit := 1 - float64(distance)/float64(max(len(ra), len(rb)))
+
+	return max(dice, edit)
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(min(prev[j]+1, curr[j-1]+1), prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
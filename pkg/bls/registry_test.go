@@ -0,0 +1,134 @@
+package bls
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultRegistry(t *testing.T) {
+	registry := DefaultRegistry()
+
+	for _, release := range registry.Releases() {
+		if !strings.HasPrefix(release.Path, "/news.release/") || !strings.HasSuffix(release.Path, ".nr0.htm") {
+			t.Errorf("Release %q has a path that isn't a news release: %s", release.Summary, release.Path)
+		}
+	}
+
+	// These used to point at the wrong pages
+	fixed := map[string]string{
+		"State Unemployment (Annual)":                 "/news.release/srgune.nr0.htm",
+		"State Employment and Unemployment (Monthly)": "/news.release/laus.nr0.htm",
+	}
+	for summary, want := range fixed {
+		release, ok := registry.Lookup(summary)
+		if !ok || release.Path != want {
+			t.Errorf("Lookup(%q) = %+v, %v; want path %s", summary, release, ok, want)
+		}
+	}
+}
+
+func TestRegistryLookup(t *testing.T) {
+	registry := DefaultRegistry()
+
+	testCases := []struct {
+		summary string
+		want    string
+	}{
+		{"Consumer Price Index", "Consumer Price Index"},
+		{"  consumer price INDEX ", "Consumer Price Index"},
+		{"Consumer Price Index (CPI)", "Consumer Price Index"},
+		{"Consumer Prise Index", "Consumer Price Index"},
+		{"Job Openings and Labor Turnover Survey (JOLTS)", "Job Openings and Labor Turnover Survey"},
+		{"Employment Situation of Veterans", "Employment Situation of Veterans"},
+		{"State Employment and Unemployment - Monthly", "State Employment and Unemployment (Monthly)"},
+		// Close in spelling but a different release
+		{"State Employment and Unemployment (Annual)", ""},
+		{"Employment Situation Summary Table", ""},
+		// Just as close to the national and the state release
+		{"Job Openings and Labor Turnover", ""},
+		{"Gross Domestic Product", ""},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.summary, func(t *testing.T) {
+			release, ok := registry.Lookup(tc.summary)
+			if ok != (tc.want != "") || release.Summary != tc.want {
+				t.Errorf("Lookup(%q) = %q, %v; want %q", tc.summary, release.Summary, ok, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadRegistry(t *testing.T) {
+	testCases := []struct {
+		name      string
+		json      string
+		expectErr string
+	}{
+		{
+			name: "Valid with aliases",
+			json: `{"releases": [{"summary": "Consumer Price Index", "path": "/news.release/cpi.nr0.htm", "aliases": ["CPI"]}]}`,
+		},
+		{
+			name:      "Empty",
+			json:      `{"releases": []}`,
+			expectErr: "empty",
+		},
+		{
+			name:      "Missing summary",
+			json:      `{"releases": [{"path": "/news.release/cpi.nr0.htm"}]}`,
+			expectErr: "no summary",
+		},
+		{
+			name:      "Invalid path",
+			json:      `{"releases": [{"summary": "Consumer Price Index", "path": "https://www.bls.gov/cpi/"}]}`,
+			expectErr: "invalid path",
+		},
+		{
+			name:      "Duplicate summary",
+			json:      `{"releases": [{"summary": "Real Earnings", "path": "/news.release/realer.nr0.htm"}, {"summary": "real earnings", "path": "/news.release/realer2.nr0.htm"}]}`,
+			expectErr: "conflicts",
+		},
+		{
+			name:      "Unknown field",
+			json:      `{"releases": [{"summary": "Real Earnings", "url": "/news.release/realer.nr0.htm"}]}`,
+			expectErr: "failed to decode",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			registry, err := LoadRegistry(strings.NewReader(tc.json))
+			if tc.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectErr) {
+					t.Errorf("Expected error containing %q, got %v", tc.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadRegistry() returned an error: %v", err)
+			}
+			release, ok := registry.Lookup("cpi")
+			if !ok || release.Code() != "cpi" {
+				t.Errorf("Lookup(\"cpi\") = %+v, %v", release, ok)
+			}
+		})
+	}
+}
+
+func TestRegistryUnmapped(t *testing.T) {
+	events := []Event{
+		{Summary: "Consumer Price Index"},
+		{Summary: "Gross Domestic Product"},
+		{Summary: "Consumer Price Index"},
+		{Summary: "Gross Domestic Product "},
+		{Summary: "County Business Patterns"},
+	}
+
+	got := DefaultRegistry().Unmapped(events)
+	want := []string{"County Business Patterns", "Gross Domestic Product"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Unmapped() = %v, want %v", got, want)
+	}
+}
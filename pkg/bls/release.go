@@ -0,0 +1,57 @@
+package bls
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// releaseDatePattern matches dates such as "Wednesday, January 15, 2025".
+var releaseDatePattern = regexp.MustCompile(`(January|February|March|April|May|June|July|August|September|October|November|December)\s+(\d{1,2}),\s+(\d{4})`)
+
+// releaseLocation is where bls.gov publishes from; release dates are Eastern.
+var releaseLocation = func() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}()
+
+// ReleaseDate returns the publication date from the embargo notice at the top
+// of a news release, e.g. "8:30 a.m. (ET) Wednesday, January 15, 2025". The
+// result is midnight of that day in UTC.
+func ReleaseDate(html string) (time.Time, error) {
+	text, err := ExtractSummary(html)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// The embargo notice comes first, but releases without one still start with
+	// their publication date
+	if i := strings.Index(strings.ToLower(text), "embargoed until"); i >= 0 {
+		text = text[i:]
+	}
+	m := releaseDatePattern.FindStringSubmatch(text)
+	if m == nil {
+		return time.Time{}, fmt.Errorf("no release date found")
+	}
+
+	date, err := time.Parse("January 2 2006", m[1]+" "+m[2]+" "+m[3])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid release date %q: %w", m[0], err)
+	}
+	return date, nil
+}
+
+// ReleaseDay returns the day the event is published on, in Eastern time, as
+// midnight UTC so it compares with ReleaseDate. It returns false for events
+// without a start time.
+func (e Event) ReleaseDay() (time.Time, bool) {
+	if e.Start == nil {
+		return time.Time{}, false
+	}
+	y, m, d := e.Start.In(releaseLocation).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC), true
+}
@@ -0,0 +1,92 @@
+package bls
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReleaseDate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		html      string
+		want      time.Time
+		expectErr bool
+	}{
+		{
+			name: "CPI",
+			html: readFixture(t, "cpi_release.html"),
+			want: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "JOLTS",
+			html: readFixture(t, "jolts_release.html"),
+			want: time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Embargo notice takes precedence",
+			html: "<pre>Data for December 31, 2024\nembargoed until\n8:30 a.m. (ET) Friday, February 7, 2025\n</pre>",
+			want: time.Date(2025, 2, 7, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "No embargo notice",
+			html: "<pre>For release 10:00 a.m. (ET) Thursday, March 6, 2025\n</pre>",
+			want: time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "No date",
+			html:      "<pre>CONSUMER PRICE INDEX - DECEMBER 2024</pre>",
+			expectErr: true,
+		},
+		{
+			name:      "Not a release",
+			html:      "<html><body>Access Denied</body></html>",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ReleaseDate(tc.html)
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReleaseDate() returned an error: %v", err)
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("ReleaseDate() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEventReleaseDay(t *testing.T) {
+	at := func(s string) *time.Time {
+		start, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &start
+	}
+
+	testCases := []struct {
+		start *time.Time
+		want  time.Time
+		ok    bool
+	}{
+		{at("2025-01-15T13:30:00Z"), time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), true},
+		// Late in the evening Eastern is already the next day in UTC
+		{at("2025-01-16T02:00:00Z"), time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), true},
+		{nil, time.Time{}, false},
+	}
+
+	for _, tc := range testCases {
+		got, ok := Event{Start: tc.start}.ReleaseDay()
+		if ok != tc.ok || !got.Equal(tc.want) {
+			t.Errorf("ReleaseDay() for %v = %v, %v; want %v, %v", tc.start, got, ok, tc.want, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,414 @@
+package bls
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Adjustment records whether figures are seasonally adjusted.
+type Adjustment string
+
+const (
+	AdjustmentUnknown     Adjustment = ""
+	SeasonallyAdjusted    Adjustment = "seasonally_adjusted"
+	NotSeasonallyAdjusted Adjustment = "not_seasonally_adjusted"
+)
+
+// Table is a data table from a news release.
+type Table struct {
+	ID    string
+	Title string
+	// Unit is the unit stated in the title, e.g. "In thousands" or "percent".
+	// Empty when the title doesn't say.
+	Unit string
+	// Adjustment applies to columns that don't state their own.
+	Adjustment Adjustment
+	Columns    []Column
+	Rows       []Row
+}
+
+// Column is a data column, i.e. every column after the row labels.
+type Column struct {
+	// Headers are the header cells above the column, outermost first.
+	Headers []string
+	// Period is the month, quarter or year the column covers, e.g. "Dec. 2024"
+	// or "Nov. 2024-Dec. 2024". Empty when the headers don't name one.
+	Period      string
+	Adjustment  Adjustment
+	Preliminary bool
+}
+
+// Label returns the column's headers joined into one string.
+func (c Column) Label() string {
+	return strings.Join(c.Headers, " / ")
+}
+
+// Row is a row of a table, with one cell per column.
+type Row struct {
+	Label string
+	// Level is the indentation of the label; nested categories have higher levels.
+	Level int
+	Cells []Cell
+}
+
+// Cell is a single figure.
+type Cell struct {
+	Text string
+	// Value is the parsed figure. It is only meaningful when HasValue is set;
+	// cells such as "-" or "(NA)" have no value.
+	Value    float64
+	HasValue bool
+}
+
+// Series is one row of a table as a typed series of observations.
+type Series struct {
+	Table string
+	Name  string
+	Unit  string
+	// Points holds an entry for every column with a value, in column order.
+	Points []Point
+}
+
+// Point is a single observation of a series.
+type Point struct {
+	Column      string
+	Period      string
+	Adjustment  Adjustment
+	Preliminary bool
+	Value       float64
+}
+
+// Series returns the table's rows that hold at least one figure.
+func (t Table) Series() []Series {
+	var series []Series
+	for _, row := range t.Rows {
+		s := Series{Table: t.Title, Name: row.Label, Unit: t.Unit}
+		for i, cell := range row.Cells {
+			if !cell.HasValue || i >= len(t.Columns) {
+				continue
+			}
+			col := t.Columns[i]
+			adjustment := col.Adjustment
+			if adjustment == AdjustmentUnknown {
+				adjustment = t.Adjustment
+			}
+			s.Points = append(s.Points, Point{
+				Column:      col.Label(),
+				Period:      col.Period,
+				Adjustment:  adjustment,
+				Preliminary: col.Preliminary,
+				Value:       cell.Value,
+			})
+		}
+		if len(s.Points) > 0 {
+			series = append(series, s)
+		}
+	}
+	return series
+}
+
+// Row returns the first row whose label matches, ignoring case and surrounding space.
+func (t Table) Row(label string) (Row, bool) {
+	for _, row := range t.Rows {
+		if strings.EqualFold(row.Label, strings.TrimSpace(label)) {
+			return row, true
+		}
+	}
+	return Row{}, false
+}
+
+// ParseTables extracts the data tables from a news release. Tables without a
+// single figure, such as layout tables, are skipped.
+func ParseTables(html string) ([]Table, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+
+	var tables []Table
+	// Nested tables are found and parsed on their own
+	doc.Find("table").Each(func(_ int, sel *goquery.Selection) {
+		if table, ok := parseTable(sel); ok {
+			tables = append(tables, table)
+		}
+	})
+	return tables, nil
+}
+
+// gridCell is a cell placed on the table grid after applying rowspan and colspan.
+type gridCell struct {
+	text   string
+	header bool
+	level  int
+}
+
+// maxSpan bounds rowspan and colspan so a malformed table can't blow up the grid.
+const maxSpan = 64
+
+func parseTable(sel *goquery.Selection) (Table, bool) {
+	table := Table{ID: sel.AttrOr("id", "")}
+	table.Title = cellText(sel.ChildrenFiltered("caption").First())
+
+	var headRows, bodyRows []*goquery.Selection
+	sel.ChildrenFiltered("thead").ChildrenFiltered("tr").Each(func(_ int, tr *goquery.Selection) {
+		headRows = append(headRows, tr)
+	})
+	hasHead := len(headRows) > 0
+	rows := sel.ChildrenFiltered("tr").AddSelection(sel.ChildrenFiltered("tbody").ChildrenFiltered("tr"))
+	rows.Each(func(_ int, tr *goquery.Selection) {
+		// Without a thead, leading rows made only of header cells are the header
+		if !hasHead && len(bodyRows) == 0 && tr.ChildrenFiltered("td").Length() == 0 && tr.ChildrenFiltered("th").Length() > 0 {
+			headRows = append(headRows, tr)
+			return
+		}
+		bodyRows = append(bodyRows, tr)
+	})
+
+	head := expandRows(headRows)
+	body := expandRows(bodyRows)
+
+	// The row labels are the leading header cells of the body rows
+	stub := 1
+	for _, row := range body {
+		n := 0
+		for n < len(row) && row[n].header {
+			n++
+		}
+		if n > 0 && n < len(row) {
+			stub = n
+			break
+		}
+	}
+
+	width := 0
+	for _, row := range append(head, body...) {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+
+	table.Unit = unitFromTitle(table.Title)
+	table.Adjustment = adjustmentFrom(table.Title)
+
+	for c := stub; c < width; c++ {
+		var col Column
+		for _, row := range head {
+			if c >= len(row) || row[c].text == "" {
+				continue
+			}
+			// Spanning cells repeat down and across the grid
+			if n := len(col.Headers); n > 0 && col.Headers[n-1] == row[c].text {
+				continue
+			}
+			col.Headers = append(col.Headers, row[c].text)
+		}
+		for i := len(col.Headers) - 1; i >= 0 && col.Period == ""; i-- {
+			col.Period = periodFrom(col.Headers[i])
+		}
+		for i := len(col.Headers) - 1; i >= 0 && col.Adjustment == AdjustmentUnknown; i-- {
+			col.Adjustment = adjustmentFrom(col.Headers[i])
+		}
+		for _, h := range col.Headers {
+			if preliminaryPattern.MatchString(h) {
+				col.Preliminary = true
+			}
+		}
+		table.Columns = append(table.Columns, col)
+	}
+
+	hasValue := false
+	for _, cells := range body {
+		if len(cells) == 0 {
+			continue
+		}
+		var row Row
+		var labels []string
+		for i := 0; i < stub && i < len(cells); i++ {
+			if cells[i].text != "" {
+				labels = append(labels, cells[i].text)
+			}
+			row.Level = cells[i].level
+		}
+		row.Label = strings.Join(labels, " ")
+
+		for c := stub; c < width; c++ {
+			// Header cells past the labels are section headings spanning the row
+			var cell Cell
+			if c < len(cells) && !cells[c].header {
+				cell = parseCell(cells[c].text)
+			}
+			hasValue = hasValue || cell.HasValue
+			row.Cells = append(row.Cells, cell)
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table, hasValue
+}
+
+// expandRows lays out rows on a grid, repeating cells that span rows or columns.
+func expandRows(rows []*goquery.Selection) [][]gridCell {
+	grid := make([][]gridCell, len(rows))
+	filled := make([][]bool, len(rows))
+
+	place := func(r, c int, cell gridCell) {
+		for len(grid[r]) <= c {
+			grid[r] = append(grid[r], gridCell{})
+			filled[r] = append(filled[r], false)
+		}
+		grid[r][c] = cell
+		filled[r][c] = true
+	}
+
+	for r, tr := range rows {
+		c := 0
+		tr.ChildrenFiltered("th, td").Each(func(_ int, td *goquery.Selection) {
+			for c < len(filled[r]) && filled[r][c] {
+				c++
+			}
+
+			cell := gridCell{text: cellText(td), header: goquery.NodeName(td) == "th", level: cellLevel(td)}
+			colspan := spanAttr(td, "colspan")
+			rowspan := spanAttr(td, "rowspan")
+			for dr := 0; dr < rowspan && r+dr < len(rows); dr++ {
+				for dc := 0; dc < colspan; dc++ {
+					place(r+dr, c+dc, cell)
+				}
+			}
+			c += colspan
+		})
+	}
+	return grid
+}
+
+func spanAttr(sel *goquery.Selection, name string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(sel.AttrOr(name, "1")))
+	if err != nil || n < 1 {
+		return 1
+	}
+	if n > maxSpan {
+		return maxSpan
+	}
+	return n
+}
+
+var (
+	// levelPattern matches the sub0..sub9 classes BLS uses to indent row labels
+	levelPattern = regexp.MustCompile(`\bsub(\d)\b`)
+	// brokenWordPattern matches words split across lines, e.g. "Un-<br>adjusted"
+	brokenWordPattern = regexp.MustCompile(`([a-z])- ([a-z])`)
+	// brokenRangePattern matches ranges split across lines, e.g. "2024-<br>Dec."
+	brokenRangePattern = regexp.MustCompile(`(\d)- ([A-Z])`)
+)
+
+func cellLevel(sel *goquery.Selection) int {
+	classes := sel.AttrOr("class", "")
+	sel.Find("[class]").Each(func(_ int, child *goquery.Selection) {
+		classes += " " + child.AttrOr("class", "")
+	})
+	if m := levelPattern.FindStringSubmatch(classes); m != nil {
+		level, _ := strconv.Atoi(m[1])
+		return level
+	}
+	return 0
+}
+
+// cellText returns the text of a cell with line breaks turned into spaces and
+// runs of whitespace collapsed.
+func cellText(sel *goquery.Selection) string {
+	if sel.Length() == 0 {
+		return ""
+	}
+	sel = sel.Clone()
+	sel.Find("br").ReplaceWithHtml(" ")
+	text := strings.Join(strings.Fields(sel.Text()), " ")
+	text = brokenWordPattern.ReplaceAllString(text, "$1$2")
+	text = brokenRangePattern.ReplaceAllString(text, "$1-$2")
+	return text
+}
+
+var (
+	numberPattern      = regexp.MustCompile(`^([-+]?)\$?(\d[\d,]*(?:\.\d+)?|\.\d+)\s*(?:\([A-Za-z0-9]+\)|[pP])?$`)
+	preliminaryPattern = regexp.MustCompile(`(?i)\(p\)|\bpreliminary\b`)
+)
+
+// parseCell parses figures such as "1,234", "-0.1", "$1,165" or "4.2(p)".
+// Footnote markers are ignored; placeholders such as "-" or "(NA)" have no value.
+func parseCell(text string) Cell {
+	cell := Cell{Text: text}
+
+	normalized := strings.NewReplacer("−", "-", "–", "-", " ", " ").Replace(strings.TrimSpace(text))
+	// Parenthesised cells such as "(4)" or "(NA)" are footnote references
+	m := numberPattern.FindStringSubmatch(normalized)
+	if m == nil {
+		return cell
+	}
+
+	value, err := strconv.ParseFloat(strings.ReplaceAll(m[2], ",", ""), 64)
+	if err != nil {
+		return cell
+	}
+	if m[1] == "-" {
+		value = -value
+	}
+	cell.Value = value
+	cell.HasValue = true
+	return cell
+}
+
+var (
+	monthPattern  = `(?:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Sept|Oct|Nov|Dec)[a-z]*\.?`
+	monthYear     = monthPattern + `\s*\d{4}`
+	quarterYear   = `(?:(?:I|II|III|IV|1st|2nd|3rd|4th)\s+(?:quarter|qtr\.?)?\s*\d{4}|Q[1-4]\s*\d{4})`
+	periodPattern = regexp.MustCompile(`(?i)` + monthYear + `(?:\s*-\s*` + monthYear + `)?|` + quarterYear + `(?:\s*-\s*` + quarterYear + `)?`)
+	yearPattern   = regexp.MustCompile(`\b(?:19|20)\d{2}\b`)
+)
+
+// periodFrom finds the period named in a header, preferring months and quarters to years.
+func periodFrom(header string) string {
+	header = preliminaryPattern.ReplaceAllString(header, "")
+	if p := periodPattern.FindString(header); p != "" {
+		return strings.TrimSpace(p)
+	}
+	if yearPattern.MatchString(header) && len(strings.Fields(header)) == 1 {
+		return yearPattern.FindString(header)
+	}
+	return ""
+}
+
+// adjustmentFrom reads the seasonal adjustment stated in a header or title.
+func adjustmentFrom(text string) Adjustment {
+	lower := strings.ToLower(text)
+	switch {
+	case strings.Contains(lower, "not seasonally adjusted"), strings.Contains(lower, "unadjusted"):
+		return NotSeasonallyAdjusted
+	case strings.Contains(lower, "seasonally adjusted"):
+		return SeasonallyAdjusted
+	}
+	return AdjustmentUnknown
+}
+
+var (
+	parenPattern = regexp.MustCompile(`\(([^()]+)\)`)
+	unitWords    = []string{"thousand", "million", "billion", "percent", "dollar", "=100", "rate", "hours"}
+)
+
+// unitFromTitle finds the unit of a table in its title, e.g. "(In thousands)".
+func unitFromTitle(title string) string {
+	for _, m := range parenPattern.FindAllStringSubmatch(title, -1) {
+		lower := strings.ToLower(m[1])
+		for _, word := range unitWords {
+			if strings.Contains(lower, word) {
+				return strings.TrimSpace(m[1])
+			}
+		}
+	}
+	if strings.Contains(strings.ToLower(title), "percent change") {
+		return "percent"
+	}
+	return ""
+}
@@ -0,0 +1,237 @@
+package bls
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func parseFixtureTables(t *testing.T, name string) []Table {
+	t.Helper()
+	html, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tables, err := ParseTables(string(html))
+	if err != nil {
+		t.Fatalf("ParseTables() returned an error: %v", err)
+	}
+	return tables
+}
+
+func TestParseTablesCPI(t *testing.T) {
+	tables := parseFixtureTables(t, "cpi_release.html")
+	if len(tables) != 1 {
+		t.Fatalf("ParseTables() returned %d tables, want 1", len(tables))
+	}
+	table := tables[0]
+
+	if table.ID != "cpipress1" {
+		t.Errorf("ID = %q, want %q", table.ID, "cpipress1")
+	}
+	if table.Unit != "percent" {
+		t.Errorf("Unit = %q, want %q", table.Unit, "percent")
+	}
+
+	wantColumns := []Column{
+		{Headers: []string{"Seasonally adjusted changes from preceding month", "Oct. 2024"}, Period: "Oct. 2024", Adjustment: SeasonallyAdjusted},
+		{Headers: []string{"Seasonally adjusted changes from preceding month", "Nov. 2024"}, Period: "Nov. 2024", Adjustment: SeasonallyAdjusted},
+		{Headers: []string{"Seasonally adjusted changes from preceding month", "Dec. 2024"}, Period: "Dec. 2024", Adjustment: SeasonallyAdjusted},
+		{Headers: []string{"Unadjusted 12-mos. ended Dec. 2024"}, Period: "Dec. 2024", Adjustment: NotSeasonallyAdjusted},
+	}
+	if len(table.Columns) != len(wantColumns) {
+		t.Fatalf("Got %d columns, want %d: %+v", len(table.Columns), len(wantColumns), table.Columns)
+	}
+	for i, want := range wantColumns {
+		got := table.Columns[i]
+		if got.Label() != want.Label() || got.Period != want.Period || got.Adjustment != want.Adjustment {
+			t.Errorf("Column %d = %+v, want %+v", i, got, want)
+		}
+	}
+
+	testCases := []struct {
+		label  string
+		level  int
+		values []float64
+		valid  []bool
+	}{
+		{"All items", 0, []float64{0.2, 0.3, 0.4, 2.9}, []bool{true, true, true, true}},
+		{"Food", 1, []float64{0.2, 0.4, 0.3, 2.5}, []bool{true, true, true, true}},
+		{"Energy services", 2, []float64{-0.2, 0.2, -0.1, 3.0}, []bool{true, true, true, true}},
+		{"Airline fares", 2, []float64{3.2, 0.4, 0, 0}, []bool{true, true, false, false}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			row, ok := table.Row(tc.label)
+			if !ok {
+				t.Fatalf("Row %q not found", tc.label)
+			}
+			if row.Level != tc.level {
+				t.Errorf("Level = %d, want %d", row.Level, tc.level)
+			}
+			for i, cell := range row.Cells {
+				if cell.HasValue != tc.valid[i] || (cell.HasValue && cell.Value != tc.values[i]) {
+					t.Errorf("Cell %d = %+v, want value %v (valid %v)", i, cell, tc.values[i], tc.valid[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseTablesEmploymentSituation(t *testing.T) {
+	tables := parseFixtureTables(t, "empsit_release.html")
+	// The navigation table has no figures and is skipped
+	if len(tables) != 2 {
+		t.Fatalf("ParseTables() returned %d tables, want 2", len(tables))
+	}
+
+	household := tables[0]
+	if household.Adjustment != SeasonallyAdjusted {
+		t.Errorf("Household Adjustment = %q, want %q", household.Adjustment, SeasonallyAdjusted)
+	}
+	if got := household.Columns[4].Period; got != "Nov. 2024-Dec. 2024" {
+		t.Errorf("Change column period = %q, want %q", got, "Nov. 2024-Dec. 2024")
+	}
+	if section, ok := household.Row("Employment status"); !ok || len(section.Cells) != 5 || section.Cells[0].HasValue {
+		t.Errorf("Expected a section row without figures, got %+v", section)
+	}
+	labor, ok := household.Row("Civilian labor force")
+	if !ok {
+		t.Fatal("Row \"Civilian labor force\" not found")
+	}
+	if got := labor.Cells[3].Value; got != 168116 {
+		t.Errorf("Civilian labor force Dec. 2024 = %v, want 168116", got)
+	}
+
+	establishment := tables[1]
+	if establishment.Unit != "In thousands" {
+		t.Errorf("Unit = %q, want %q", establishment.Unit, "In thousands")
+	}
+	if establishment.Columns[1].Preliminary || !establishment.Columns[3].Preliminary {
+		t.Errorf("Expected only the last two columns to be preliminary, got %+v", establishment.Columns)
+	}
+	if got := establishment.Columns[3].Period; got != "Dec. 2024" {
+		t.Errorf("Preliminary column period = %q, want %q", got, "Dec. 2024")
+	}
+
+	series := establishment.Series()
+	if len(series) != 2 {
+		t.Fatalf("Series() returned %d series, want 2", len(series))
+	}
+	earnings := series[1]
+	if earnings.Name != "Average hourly earnings, total private" || len(earnings.Points) != 4 {
+		t.Fatalf("Unexpected series %+v", earnings)
+	}
+	last := earnings.Points[3]
+	if last.Value != 35.69 || last.Period != "Dec. 2024" || !last.Preliminary || last.Adjustment != SeasonallyAdjusted {
+		t.Errorf("Last point = %+v", last)
+	}
+}
+
+func TestParseTablesPPI(t *testing.T) {
+	tables := parseFixtureTables(t, "ppi_release.html")
+	if len(tables) != 1 {
+		t.Fatalf("ParseTables() returned %d tables, want 1", len(tables))
+	}
+	table := tables[0]
+
+	if table.Adjustment != SeasonallyAdjusted {
+		t.Errorf("Adjustment = %q, want %q", table.Adjustment, SeasonallyAdjusted)
+	}
+	if got := table.Columns[3].Adjustment; got != NotSeasonallyAdjusted {
+		t.Errorf("12-month column Adjustment = %q, want %q", got, NotSeasonallyAdjusted)
+	}
+
+	// PPI lists months as rows, preliminary ones marked in the label
+	latest, ok := table.Row("Dec. 2024(p)")
+	if !ok {
+		t.Fatal("Row \"Dec. 2024(p)\" not found")
+	}
+	want := []float64{0.2, 0.6, 0, 3.3}
+	for i, cell := range latest.Cells {
+		if !cell.HasValue || cell.Value != want[i] {
+			t.Errorf("Cell %d = %+v, want %v", i, cell, want[i])
+		}
+	}
+}
+
+func TestParseTablesJOLTS(t *testing.T) {
+	tables := parseFixtureTables(t, "jolts_release.html")
+	if len(tables) != 1 {
+		t.Fatalf("ParseTables() returned %d tables, want 1", len(tables))
+	}
+	table := tables[0]
+
+	if table.Unit != "Levels in thousands" {
+		t.Errorf("Unit = %q, want %q", table.Unit, "Levels in thousands")
+	}
+	// A capitalized "Preliminary" header marks the column without leaking into its period
+	latest := table.Columns[3]
+	if !latest.Preliminary || latest.Period != "Nov. 2024" {
+		t.Errorf("Latest column = %+v, want a preliminary Nov. 2024 column", latest)
+	}
+	if table.Columns[2].Preliminary {
+		t.Errorf("Expected only the last column to be preliminary, got %+v", table.Columns)
+	}
+
+	series := table.Series()
+	if len(series) != 2 {
+		t.Fatalf("Series() returned %d series, want 2", len(series))
+	}
+	openings := series[0].Points[3]
+	if openings.Value != 8098 || !openings.Preliminary || openings.Adjustment != SeasonallyAdjusted {
+		t.Errorf("Latest job openings point = %+v", openings)
+	}
+}
+
+func TestParseCell(t *testing.T) {
+	testCases := []struct {
+		text     string
+		want     float64
+		hasValue bool
+	}{
+		{"1,234", 1234, true},
+		{"-0.1", -0.1, true},
+		{"−0.2", -0.2, true},
+		{"$35.69", 35.69, true},
+		{"4.2(p)", 4.2, true},
+		{"256 (2)", 256, true},
+		{".5", 0.5, true},
+		{"-", 0, false},
+		{"(1)", 0, false},
+		{"(NA)", 0, false},
+		{"", 0, false},
+		{"Dec. 2024", 0, false},
+	}
+
+	for _, tc := range testCases {
+		got := parseCell(tc.text)
+		if got.HasValue != tc.hasValue || got.Value != tc.want {
+			t.Errorf("parseCell(%q) = %v (%v), want %v (%v)", tc.text, got.Value, got.HasValue, tc.want, tc.hasValue)
+		}
+	}
+}
+
+func TestPeriodFrom(t *testing.T) {
+	testCases := []struct {
+		header string
+		want   string
+	}{
+		{"Dec. 2024", "Dec. 2024"},
+		{"Dec. 2024(p)", "Dec. 2024"},
+		{"Preliminary Nov. 2024", "Nov. 2024"},
+		{"December 2024", "December 2024"},
+		{"Nov. 2024-Dec. 2024", "Nov. 2024-Dec. 2024"},
+		{"Unadjusted 12-mos. ended Dec. 2024", "Dec. 2024"},
+		{"IV quarter 2024", "IV quarter 2024"},
+		{"2024", "2024"},
+		{"Change from: 2023 to 2024 levels", ""},
+		{"Category", ""},
+	}
+
+	for _, tc := range testCases {
+		if got := periodFrom(tc.header); got != tc.want {
+			t.Errorf("periodFrom(%q) = %q, want %q", tc.header, got, tc.want)
+		}
+	}
+}
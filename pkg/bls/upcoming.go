@@ -0,0 +1,80 @@
+package bls
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrNoUpcomingRelease is returned by NextRelease when the calendar has no
+// future event for a release.
+var ErrNoUpcomingRelease = errors.New("no upcoming release")
+
+// FindUpcomingEvents finds events starting within the next window using the
+// DefaultClient.
+func FindUpcomingEvents(window time.Duration) ([]Event, error) {
+	return DefaultClient.FindUpcomingEvents(context.Background(), window)
+}
+
+// FindUpcomingEvents finds events starting within the next window, ordered by
+// start time. It is the forward-looking counterpart of FindEvents.
+func (c *Client) FindUpcomingEvents(ctx context.Context, window time.Duration) ([]Event, error) {
+	allEvents, err := c.GetAllEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return EventsBetween(allEvents, now, now.Add(window)), nil
+}
+
+// NextRelease finds the next event for a release using the DefaultClient.
+func NextRelease(summary string) (*Event, error) {
+	return DefaultClient.NextRelease(context.Background(), summary)
+}
+
+// NextRelease finds the next event for the release with the given summary, which
+// is looked up in the registry so aliases and small differences in spelling
+// match. It returns ErrNoUpcomingRelease if the calendar has none.
+func (c *Client) NextRelease(ctx context.Context, summary string) (*Event, error) {
+	allEvents, err := c.GetAllEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	event, ok := nextRelease(c.registry(), allEvents, summary, time.Now())
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoUpcomingRelease, strings.TrimSpace(summary))
+	}
+	return event, nil
+}
+
+// nextRelease returns the first event after now for the same release as
+// summary. Summaries without a release only match events with the same
+// normalized summary.
+func nextRelease(registry *Registry, events []Event, summary string, now time.Time) (*Event, bool) {
+	if normalizeSummary(summary) == "" {
+		return nil, false
+	}
+	release, mapped := registry.Lookup(summary)
+	matches := func(event Event) bool {
+		if !mapped {
+			return normalizeSummary(event.Summary) == normalizeSummary(summary)
+		}
+		other, ok := registry.Lookup(event.Summary)
+		return ok && other.Summary == release.Summary
+	}
+
+	var next *Event
+	for i, event := range events {
+		if event.Start == nil || !event.Start.After(now) || !matches(event) {
+			continue
+		}
+		if next == nil || event.Start.Before(*next.Start) {
+			next = &events[i]
+		}
+	}
+	return next, next != nil
+}
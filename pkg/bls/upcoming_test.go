@@ -0,0 +1,134 @@
+package bls
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientUpcoming(t *testing.T) {
+	now := time.Now()
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d).Truncate(time.Second)
+		return &t
+	}
+	events := []Event{
+		{Summary: "Consumer Price Index", UID: "cpi-past", Start: at(-24 * time.Hour)},
+		{Summary: "Employment Situation", UID: "empsit", Start: at(5 * 24 * time.Hour)},
+		{Summary: "Consumer Price Index", UID: "cpi", Start: at(2 * 24 * time.Hour)},
+		{Summary: "Producer Price Index", UID: "ppi", Start: at(10 * 24 * time.Hour)},
+	}
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		WriteCalendar(w, "", events)
+	}))
+
+	upcoming, err := client.FindUpcomingEvents(context.Background(), 7*24*time.Hour)
+	if err != nil {
+		t.Fatalf("FindUpcomingEvents() returned an error: %v", err)
+	}
+	var uids []string
+	for _, event := range upcoming {
+		uids = append(uids, event.UID)
+	}
+	if strings.Join(uids, ",") != "cpi,empsit" {
+		t.Errorf("FindUpcomingEvents() = %v, want the CPI and then the jobs report", uids)
+	}
+
+	next, err := client.NextRelease(context.Background(), "Producer Price Index")
+	if err != nil {
+		t.Fatalf("NextRelease() returned an error: %v", err)
+	}
+	if next.UID != "ppi" {
+		t.Errorf("NextRelease() = %s, want ppi", next.UID)
+	}
+
+	if _, err := client.NextRelease(context.Background(), "Real Earnings"); !errors.Is(err, ErrNoUpcomingRelease) {
+		t.Errorf("Expected ErrNoUpcomingRelease, got %v", err)
+	}
+}
+
+func TestNextRelease(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	at := func(day int) *time.Time {
+		t := time.Date(2025, 1, day, 13, 30, 0, 0, time.UTC)
+		return &t
+	}
+	events := []Event{
+		{Summary: "Consumer Price Index", UID: "cpi-dec", Start: at(3)},
+		{Summary: "Producer Price Index", UID: "ppi", Start: at(14)},
+		{Summary: "Consumer Price Index", UID: "cpi-feb", Start: at(28)},
+		{Summary: "Consumer Price Index", UID: "cpi-jan", Start: at(15)},
+		{Summary: "Consumer Price Index", UID: "cpi-unscheduled"},
+		{Summary: "Gross Domestic Product", UID: "gdp", Start: at(30)},
+	}
+
+	testCases := []struct {
+		summary string
+		wantUID string
+	}{
+		{"Consumer Price Index", "cpi-jan"},
+		{"consumer price index (CPI)", "cpi-jan"},
+		{"Producer Price Index", "ppi"},
+		// Not in the registry, but still on the calendar
+		{"Gross Domestic Product", "gdp"},
+		{"Employment Situation", ""},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.summary, func(t *testing.T) {
+			event, ok := nextRelease(DefaultRegistry(), events, tc.summary, now)
+			if ok != (tc.wantUID != "") {
+				t.Fatalf("nextRelease(%q) found = %v, want %v", tc.summary, ok, tc.wantUID != "")
+			}
+			if ok && event.UID != tc.wantUID {
+				t.Errorf("nextRelease(%q) = %s, want %s", tc.summary, event.UID, tc.wantUID)
+			}
+		})
+	}
+}
+
+func TestRegistryFamilies(t *testing.T) {
+	registry := DefaultRegistry()
+
+	families := registry.Families()
+	if strings.Join(families, ",") != "employment,pay,prices,productivity,safety,spending" {
+		t.Errorf("Families() = %v", families)
+	}
+	for _, release := range registry.Releases() {
+		if release.Family == "" {
+			t.Errorf("Release %q has no family", release.Summary)
+		}
+	}
+
+	events := []Event{
+		{Summary: "Consumer Price Index"},
+		{Summary: "Employment Situation"},
+		{Summary: "Producer Price Index"},
+		{Summary: "Gross Domestic Product"},
+		{Summary: "Employment Cost Index"},
+	}
+
+	testCases := []struct {
+		families []string
+		want     []string
+	}{
+		{nil, []string{"Consumer Price Index", "Employment Situation", "Producer Price Index", "Gross Domestic Product", "Employment Cost Index"}},
+		{[]string{"prices"}, []string{"Consumer Price Index", "Producer Price Index"}},
+		{[]string{" Prices", "pay"}, []string{"Consumer Price Index", "Producer Price Index", "Employment Cost Index"}},
+		{[]string{"weather"}, nil},
+	}
+
+	for _, tc := range testCases {
+		var got []string
+		for _, event := range registry.InFamilies(events, tc.families...) {
+			got = append(got, event.Summary)
+		}
+		if strings.Join(got, "|") != strings.Join(tc.want, "|") {
+			t.Errorf("InFamilies(%v) = %v, want %v", tc.families, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package calendar
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// DefaultBEAScheduleURL is the BEA release schedule page.
+const DefaultBEAScheduleURL = "https://www.bea.gov/news/schedule"
+
+// beaSeries are the BEA releases BEASource lists. Personal consumption
+// expenditures (PCE) and the PCE price index are published in Personal Income
+// and Outlays.
+var beaSeries = []series{
+	{
+		name:     "Gross Domestic Product",
+		url:      "https://www.bea.gov/data/gdp/gross-domestic-product",
+		prefixes: []string{"gdp", "gross domestic product"},
+		excludes: []string{"by state", "by county", "by industry", "metropolitan"},
+	},
+	{
+		name:     "Personal Income and Outlays",
+		url:      "https://www.bea.gov/data/income-saving/personal-income",
+		prefixes: []string{"personal income and outlays"},
+	},
+	{
+		name:     "U.S. International Trade in Goods and Services",
+		url:      "https://www.bea.gov/data/intl-trade-investment/international-trade-goods-and-services",
+		prefixes: []string{"u.s. international trade in goods and services"},
+	},
+}
+
+// BEASource lists BEA releases from the BEA release schedule page.
+type BEASource struct {
+	// HTTPClient sends the requests. Nil means a client with bls.DefaultTimeout.
+	HTTPClient *http.Client
+	// URL is the schedule page. Empty means DefaultBEAScheduleURL.
+	URL string
+}
+
+// Agency implements Source.
+func (s *BEASource) Agency() string {
+	return AgencyBEA
+}
+
+// Events implements Source.
+func (s *BEASource) Events(ctx context.Context) ([]Event, error) {
+	url := s.URL
+	if url == "" {
+		url = DefaultBEAScheduleURL
+	}
+	html, err := fetch(ctx, s.HTTPClient, url)
+	if err != nil {
+		return nil, err
+	}
+	return parseScheduleTable(html, AgencyBEA, beaSeries, time.Now())
+}
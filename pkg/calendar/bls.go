@@ -0,0 +1,67 @@
+package calendar
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gflarity/bls_agent/pkg/bls"
+)
+
+// BLSSource lists BLS releases from the bls.gov release calendar. Events are
+// named after their release in the client's registry; events without one keep
+// their calendar summary and have no release URL.
+type BLSSource struct {
+	// Client fetches the calendar. Nil means bls.DefaultClient.
+	Client *bls.Client
+	// Calendar, when set, is read instead of fetching through Client.
+	Calendar *bls.CalendarCache
+}
+
+// Agency implements Source.
+func (s *BLSSource) Agency() string {
+	return AgencyBLS
+}
+
+// Events implements Source.
+func (s *BLSSource) Events(ctx context.Context) ([]Event, error) {
+	client := s.Client
+	if client == nil {
+		client = bls.DefaultClient
+	}
+
+	var blsEvents []bls.Event
+	if s.Calendar != nil {
+		snapshot, err := s.Calendar.Fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		blsEvents = snapshot.Events
+	} else {
+		var err error
+		blsEvents, err = client.GetAllEvents(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var events []Event
+	for _, e := range blsEvents {
+		if e.Start == nil {
+			continue
+		}
+		title := strings.TrimSpace(e.Summary)
+		event := Event{
+			Agency: AgencyBLS,
+			Series: title,
+			Title:  title,
+			Start:  *e.Start,
+			UID:    e.UID,
+		}
+		if release, ok := client.LookupRelease(e.Summary); ok {
+			event.Series = release.Summary
+			event.ReleaseURL = client.PathURL(release.Path)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
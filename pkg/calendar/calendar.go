@@ -0,0 +1,180 @@
+// Package calendar gathers the release schedules of several statistical
+// agencies into one list of normalized events, so the same workflows can cover
+// releases from BLS, BEA, the Census Bureau and the FOMC.
+package calendar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gflarity/bls_agent/pkg/bls"
+)
+
+// Agencies with a Source in this package.
+const (
+	AgencyBLS    = "BLS"
+	AgencyBEA    = "BEA"
+	AgencyCensus = "Census"
+	AgencyFOMC   = "FOMC"
+)
+
+// agencyNames are the full names of the agencies with a Source.
+var agencyNames = map[string]string{
+	AgencyBLS:    "Bureau of Labor Statistics",
+	AgencyBEA:    "Bureau of Economic Analysis",
+	AgencyCensus: "Census Bureau",
+	AgencyFOMC:   "Federal Open Market Committee",
+}
+
+// AgencyName returns the full name of an agency, e.g. "Bureau of Economic
+// Analysis" for AgencyBEA, or the agency as given when it isn't known.
+func AgencyName(agency string) string {
+	if name, ok := agencyNames[agency]; ok {
+		return name
+	}
+	return agency
+}
+
+// Event is a scheduled release, normalized across agencies.
+type Event struct {
+	// Agency publishes the release, e.g. AgencyBEA.
+	Agency string `json:"agency"`
+	// Series is the release's name, the same for every month's release, e.g.
+	// "Gross Domestic Product".
+	Series string `json:"series"`
+	// Title is the release as it appears in the agency's schedule, e.g.
+	// "GDP (Advance Estimate), 4th Quarter and Year 2024".
+	Title string    `json:"title"`
+	Start time.Time `json:"start"`
+	// ReleaseURL is where the release is published. It may be empty for series
+	// the source doesn't know.
+	ReleaseURL string `json:"release_url,omitempty"`
+	// UID is the event's identifier in the agency's calendar, if it has one.
+	UID string `json:"uid,omitempty"`
+}
+
+// Key returns a stable identifier for the event built from its agency, series
+// and start time, so a rescheduled release gets a new key.
+func (e Event) Key() string {
+	series := strings.Join(strings.Fields(strings.ToLower(e.Series)), "-")
+	return strings.ToLower(e.Agency) + "-" + series + "-" + e.Start.UTC().Format("20060102T150405Z")
+}
+
+// BLSEvent converts the event to the bls.Event the summary workflows take.
+func (e Event) BLSEvent() bls.Event {
+	start := e.Start
+	return bls.Event{
+		Summary: e.Series,
+		URL:     e.ReleaseURL,
+		UID:     e.UID,
+		Start:   &start,
+	}
+}
+
+// Source is an agency's release schedule.
+type Source interface {
+	// Agency names the agency whose releases the source lists.
+	Agency() string
+	// Events returns the scheduled releases, past and upcoming.
+	Events(ctx context.Context) ([]Event, error)
+}
+
+// Collect returns the events of every source, ordered by start time. A source
+// that fails doesn't stop the others; its error is returned along with the
+// events that could be collected.
+func Collect(ctx context.Context, sources ...Source) ([]Event, error) {
+	var events []Event
+	var errs []error
+	for _, source := range sources {
+		sourceEvents, err := source.Events(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get %s events: %w", source.Agency(), err))
+			continue
+		}
+		events = append(events, sourceEvents...)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+	return events, errors.Join(errs...)
+}
+
+// eastern is where every agency here publishes from; schedules are in Eastern time.
+var eastern = func() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}()
+
+// series is a release a source knows, matched against schedule titles.
+type series struct {
+	name string
+	url  string
+	// prefixes match the start of a lowercased title.
+	prefixes []string
+	// excludes rule out titles containing them, e.g. "by state" for GDP.
+	excludes []string
+}
+
+// matchSeries finds the series a schedule title belongs to.
+func matchSeries(known []series, title string) (series, bool) {
+	lower := strings.ToLower(strings.Join(strings.Fields(title), " "))
+	for _, s := range known {
+		excluded := false
+		for _, exclude := range s.excludes {
+			if strings.Contains(lower, exclude) {
+				excluded = true
+				break
+			}
+		}
+		if excluded {
+			continue
+		}
+		for _, prefix := range s.prefixes {
+			if strings.HasPrefix(lower, prefix) {
+				return s, true
+			}
+		}
+	}
+	return series{}, false
+}
+
+// fetch gets a schedule page with browser-like headers, since agency sites
+// reject many non-browser clients.
+func fetch(ctx context.Context, client *http.Client, url string) (string, error) {
+	if client == nil {
+		client = &http.Client{Timeout: bls.DefaultTimeout}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", bls.DefaultUserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/calendar,*/*")
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("bad status from %s: %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	return string(body), nil
+}
@@ -0,0 +1,223 @@
+package calendar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gflarity/bls_agent/pkg/bls"
+)
+
+// serveFixture returns the URL of a server answering every request with a
+// file from testdata.
+func serveFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// summarize formats events as "series@start" for comparison.
+func summarize(events []Event) []string {
+	var got []string
+	for _, e := range events {
+		got = append(got, e.Series+"@"+e.Start.UTC().Format(time.RFC3339))
+	}
+	return got
+}
+
+func TestSources(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source Source
+		want   []string
+	}{
+		{
+			name:   "BEA",
+			source: &BEASource{URL: serveFixture(t, "bea_schedule.html")},
+			want: []string{
+				"Gross Domestic Product@2025-01-30T13:30:00Z",
+				"Personal Income and Outlays@2025-01-31T13:30:00Z",
+				"U.S. International Trade in Goods and Services@2025-02-05T13:30:00Z",
+			},
+		},
+		{
+			name:   "Census",
+			source: &CensusSource{URL: serveFixture(t, "census_calendar.html")},
+			want: []string{
+				"Advance Retail Sales@2025-01-16T13:30:00Z",
+				"New Residential Construction@2025-01-17T13:30:00Z",
+				"Advance Durable Goods@2025-01-28T13:30:00Z",
+				"New Residential Sales@2025-01-27T15:00:00Z",
+			},
+		},
+		{
+			name:   "FOMC",
+			source: &FOMCSource{URL: serveFixture(t, "fomc_calendars.htm")},
+			want: []string{
+				"FOMC Statement@2025-01-29T19:00:00Z",
+				"FOMC Statement@2025-03-19T18:00:00Z",
+				"FOMC Statement@2025-05-01T18:00:00Z",
+				"FOMC Statement@2024-12-18T19:00:00Z",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			events, err := tc.source.Events(context.Background())
+			if err != nil {
+				t.Fatalf("Events() returned an error: %v", err)
+			}
+			got := summarize(events)
+			if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+				t.Errorf("Events() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tc.want, "\n"))
+			}
+			for _, e := range events {
+				if e.Agency != tc.source.Agency() || e.ReleaseURL == "" || e.Title == "" {
+					t.Errorf("Event %+v is missing its agency, title or release URL", e)
+				}
+			}
+		})
+	}
+}
+
+func TestFOMCReleaseURL(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "fomc_calendars.htm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := parseFOMCCalendar(string(data))
+	if err != nil {
+		t.Fatalf("parseFOMCCalendar() returned an error: %v", err)
+	}
+	want := "https://www.federalreserve.gov/newsevents/pressreleases/monetary20250129a.htm"
+	if len(events) == 0 || events[0].ReleaseURL != want {
+		t.Errorf("First event = %+v, want release URL %s", events, want)
+	}
+}
+
+func TestBLSSource(t *testing.T) {
+	start := time.Date(2025, 1, 15, 13, 30, 0, 0, time.UTC)
+	calendar := []bls.Event{
+		{Summary: "Consumer Price Index (CPI)", UID: "cpi@bls.gov", Start: &start},
+		{Summary: "County Business Patterns", UID: "cbp@bls.gov", Start: &start},
+		{Summary: "Reminder"},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bls.WriteCalendar(w, "", calendar)
+	}))
+	defer server.Close()
+
+	client := bls.NewClient()
+	client.HTTPClient = server.Client()
+	client.BaseURL = server.URL
+
+	events, err := (&BLSSource{Client: client}).Events(context.Background())
+	if err != nil {
+		t.Fatalf("Events() returned an error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Got %d events, want the two with a start time", len(events))
+	}
+	if events[0].Series != "Consumer Price Index" || events[0].ReleaseURL != server.URL+"/news.release/cpi.nr0.htm" {
+		t.Errorf("Mapped event = %+v", events[0])
+	}
+	if events[1].Series != "County Business Patterns" || events[1].ReleaseURL != "" {
+		t.Errorf("Unmapped event = %+v", events[1])
+	}
+
+	converted := events[0].BLSEvent()
+	if converted.Summary != "Consumer Price Index" || converted.Key() != "cpi@bls.gov-20250115T133000Z" {
+		t.Errorf("BLSEvent() = %+v", converted)
+	}
+}
+
+// failingSource is a Source whose schedule can't be read.
+type failingSource struct{}
+
+func (failingSource) Agency() string { return "Nowhere" }
+
+func (failingSource) Events(ctx context.Context) ([]Event, error) {
+	return nil, context.DeadlineExceeded
+}
+
+func TestCollect(t *testing.T) {
+	sources := []Source{
+		&FOMCSource{URL: serveFixture(t, "fomc_calendars.htm")},
+		failingSource{},
+		&BEASource{URL: serveFixture(t, "bea_schedule.html")},
+	}
+
+	events, err := Collect(context.Background(), sources...)
+	if err == nil || !strings.Contains(err.Error(), "Nowhere") {
+		t.Errorf("Expected the failing source's error, got %v", err)
+	}
+	if len(events) != 7 {
+		t.Fatalf("Got %d events, want 7", len(events))
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].Start.Before(events[i-1].Start) {
+			t.Errorf("Events aren't sorted: %v", summarize(events))
+			break
+		}
+	}
+}
+
+func TestScheduleTimeWithoutYear(t *testing.T) {
+	testCases := []struct {
+		when string
+		now  time.Time
+		want string
+	}{
+		{"January 7 8:30 AM", time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC), "2025-01-07T13:30:00Z"},
+		{"December 20 10:00 a.m.", time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), "2024-12-20T15:00:00Z"},
+		{"Jun. 5 12:15 PM", time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), "2025-06-05T16:15:00Z"},
+	}
+	for _, tc := range testCases {
+		got, ok := scheduleTime(tc.when, 0, tc.now)
+		if !ok || got.UTC().Format(time.RFC3339) != tc.want {
+			t.Errorf("scheduleTime(%q) = %v, %v; want %s", tc.when, got, ok, tc.want)
+		}
+	}
+
+	if _, ok := scheduleTime("January 7", 2025, time.Now()); ok {
+		t.Error("Expected no time without a time of day")
+	}
+}
+
+func TestEventKey(t *testing.T) {
+	e := Event{Agency: AgencyBEA, Series: "Gross Domestic Product", Start: time.Date(2025, 1, 30, 8, 30, 0, 0, eastern)}
+	if got, want := e.Key(), "bea-gross-domestic-product-20250130T133000Z"; got != want {
+		t.Errorf("Key() = %q, want %q", got, want)
+	}
+}
+
+func TestAgencyName(t *testing.T) {
+	testCases := []struct {
+		agency string
+		want   string
+	}{
+		{AgencyBLS, "Bureau of Labor Statistics"},
+		{AgencyBEA, "Bureau of Economic Analysis"},
+		{AgencyCensus, "Census Bureau"},
+		{AgencyFOMC, "Federal Open Market Committee"},
+		{"ECB", "ECB"},
+	}
+	for _, tc := range testCases {
+		if got := AgencyName(tc.agency); got != tc.want {
+			t.Errorf("AgencyName(%q) = %q, want %q", tc.agency, got, tc.want)
+		}
+	}
+}
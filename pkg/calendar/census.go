@@ -0,0 +1,61 @@
+package calendar
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// DefaultCensusCalendarURL is the Census Bureau economic indicator calendar.
+const DefaultCensusCalendarURL = "https://www.census.gov/economic-indicators/calendar-listview.html"
+
+// censusSeries are the Census Bureau releases CensusSource lists. Trade is
+// published jointly with BEA and listed by BEASource.
+var censusSeries = []series{
+	{
+		name:     "Advance Retail Sales",
+		url:      "https://www.census.gov/retail/sales.html",
+		prefixes: []string{"advance monthly sales for retail", "advance monthly retail"},
+	},
+	{
+		name:     "Advance Durable Goods",
+		url:      "https://www.census.gov/manufacturing/m3/adv/current/index.html",
+		prefixes: []string{"advance report on durable goods"},
+	},
+	{
+		name:     "New Residential Construction",
+		url:      "https://www.census.gov/construction/nrc/current/index.html",
+		prefixes: []string{"new residential construction"},
+	},
+	{
+		name:     "New Residential Sales",
+		url:      "https://www.census.gov/construction/nrs/current/index.html",
+		prefixes: []string{"new residential sales"},
+	},
+}
+
+// CensusSource lists Census Bureau releases from its economic indicator calendar.
+type CensusSource struct {
+	// HTTPClient sends the requests. Nil means a client with bls.DefaultTimeout.
+	HTTPClient *http.Client
+	// URL is the calendar page. Empty means DefaultCensusCalendarURL.
+	URL string
+}
+
+// Agency implements Source.
+func (s *CensusSource) Agency() string {
+	return AgencyCensus
+}
+
+// Events implements Source.
+func (s *CensusSource) Events(ctx context.Context) ([]Event, error) {
+	url := s.URL
+	if url == "" {
+		url = DefaultCensusCalendarURL
+	}
+	html, err := fetch(ctx, s.HTTPClient, url)
+	if err != nil {
+		return nil, err
+	}
+	return parseScheduleTable(html, AgencyCensus, censusSeries, time.Now())
+}
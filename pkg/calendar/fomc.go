@@ -0,0 +1,99 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// DefaultFOMCCalendarURL is the Federal Reserve's FOMC meeting calendar.
+const DefaultFOMCCalendarURL = "https://www.federalreserve.gov/monetarypolicy/fomccalendars.htm"
+
+// fomcStatementSeries names the event for the statement released after each meeting.
+const fomcStatementSeries = "FOMC Statement"
+
+var (
+	// fomcMonthPattern matches the last month of a meeting, e.g. "May" in "Apr/May".
+	fomcMonthPattern = regexp.MustCompile(`(Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec)[a-z]*\s*$`)
+	// fomcDayPattern matches the last day of a meeting, e.g. "29" in "28-29*".
+	fomcDayPattern = regexp.MustCompile(`(\d{1,2})\D*$`)
+)
+
+// FOMCSource lists the statements released after each scheduled FOMC meeting,
+// at 2:00 p.m. Eastern on its last day.
+type FOMCSource struct {
+	// HTTPClient sends the requests. Nil means a client with bls.DefaultTimeout.
+	HTTPClient *http.Client
+	// URL is the calendar page. Empty means DefaultFOMCCalendarURL.
+	URL string
+}
+
+// Agency implements Source.
+func (s *FOMCSource) Agency() string {
+	return AgencyFOMC
+}
+
+// Events implements Source.
+func (s *FOMCSource) Events(ctx context.Context) ([]Event, error) {
+	url := s.URL
+	if url == "" {
+		url = DefaultFOMCCalendarURL
+	}
+	html, err := fetch(ctx, s.HTTPClient, url)
+	if err != nil {
+		return nil, err
+	}
+	return parseFOMCCalendar(html)
+}
+
+// parseFOMCCalendar reads the meetings from the FOMC calendar page, which lists
+// them in a panel per year. Notation votes and unscheduled meetings are skipped.
+func parseFOMCCalendar(html string) ([]Event, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse FOMC calendar: %w", err)
+	}
+
+	var events []Event
+	year := 0
+	doc.Find(".panel-heading, .fomc-meeting").Each(func(_ int, sel *goquery.Selection) {
+		if sel.HasClass("panel-heading") {
+			if m := yearPattern.FindStringSubmatch(sel.Text()); m != nil {
+				year, _ = strconv.Atoi(m[1])
+			}
+			return
+		}
+
+		monthText := strings.TrimSpace(sel.Find(".fomc-meeting__month").First().Text())
+		dayText := strings.ToLower(strings.TrimSpace(sel.Find(".fomc-meeting__date").First().Text()))
+		if year == 0 || strings.Contains(dayText, "notation") || strings.Contains(dayText, "unscheduled") {
+			return
+		}
+		m := fomcMonthPattern.FindStringSubmatch(monthText)
+		d := fomcDayPattern.FindStringSubmatch(dayText)
+		if m == nil || d == nil {
+			return
+		}
+		month, err := time.Parse("Jan", m[1])
+		if err != nil {
+			return
+		}
+		day, _ := strconv.Atoi(d[1])
+
+		start := time.Date(year, month.Month(), day, 14, 0, 0, 0, eastern)
+		events = append(events, Event{
+			Agency:     AgencyFOMC,
+			Series:     fomcStatementSeries,
+			Title:      fmt.Sprintf("FOMC Meeting, %s %s", monthText, strings.Join(strings.Fields(dayText), " ")),
+			Start:      start,
+			ReleaseURL: "https://www.federalreserve.gov/newsevents/pressreleases/monetary" + start.Format("20060102") + "a.htm",
+		})
+	})
+	return events, nil
+}
@@ -0,0 +1,140 @@
+package calendar
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var (
+	// scheduleDatePattern matches dates such as "January 30", "Jan. 30" and
+	// "January 30, 2025".
+	scheduleDatePattern = regexp.MustCompile(`\b(January|February|March|April|May|June|July|August|September|October|November|December|Jan|Feb|Mar|Apr|Jun|Jul|Aug|Sept|Sep|Oct|Nov|Dec)\.?\s+(\d{1,2})(?:,\s*(\d{4}))?\b`)
+	// scheduleTimePattern matches times such as "8:30 AM" and "10:00 a.m.".
+	scheduleTimePattern = regexp.MustCompile(`\b(\d{1,2}):(\d{2})\s*([AaPp])\.?\s*[Mm]\b\.?`)
+	// yearPattern matches the year in headings such as "2025 Release Schedule".
+	yearPattern = regexp.MustCompile(`\b(20\d{2})\b`)
+)
+
+// parseScheduleTable reads release schedules laid out as table rows holding a
+// release title, a date and a time, such as the BEA and Census schedules.
+// Dates without a year take it from the closest heading or caption above the
+// row, or else the year that puts them nearest to now. Rows without a time and
+// titles that aren't a known series are skipped.
+func parseScheduleTable(html string, agency string, known []series, now time.Time) ([]Event, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schedule: %w", err)
+	}
+
+	var events []Event
+	year := 0
+	doc.Find("h1, h2, h3, h4, caption, tr").Each(func(_ int, sel *goquery.Selection) {
+		if !sel.Is("tr") {
+			text := strings.TrimSpace(sel.Text())
+			if m := yearPattern.FindStringSubmatch(text); m != nil && len(text) < 80 {
+				year, _ = strconv.Atoi(m[1])
+			}
+			return
+		}
+
+		var title, when string
+		sel.Find("td, th").Each(func(_ int, cell *goquery.Selection) {
+			text := spacedText(cell)
+			switch {
+			case text == "":
+			case scheduleDatePattern.MatchString(text) || scheduleTimePattern.MatchString(text):
+				when += " " + text
+			case title == "":
+				title = text
+			}
+		})
+
+		s, ok := matchSeries(known, title)
+		if !ok {
+			return
+		}
+		start, ok := scheduleTime(when, year, now)
+		if !ok {
+			return
+		}
+		events = append(events, Event{
+			Agency:     agency,
+			Series:     s.name,
+			Title:      title,
+			Start:      start,
+			ReleaseURL: s.url,
+		})
+	})
+	return events, nil
+}
+
+// spacedText returns the text of sel with a space between elements, so a date
+// and a time in separate elements don't run together as they do with Text.
+func spacedText(sel *goquery.Selection) string {
+	var parts []string
+	sel.Contents().Each(func(_ int, child *goquery.Selection) {
+		if goquery.NodeName(child) == "#text" {
+			parts = append(parts, child.Text())
+		} else {
+			parts = append(parts, spacedText(child))
+		}
+	})
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+}
+
+// scheduleTime reads the date and time found in a row as an instant in
+// Eastern time.
+func scheduleTime(when string, year int, now time.Time) (time.Time, bool) {
+	d := scheduleDatePattern.FindStringSubmatch(when)
+	c := scheduleTimePattern.FindStringSubmatch(when)
+	if d == nil || c == nil {
+		return time.Time{}, false
+	}
+
+	month, err := time.Parse("Jan", d[1][:3])
+	if err != nil {
+		return time.Time{}, false
+	}
+	day, _ := strconv.Atoi(d[2])
+	hour, _ := strconv.Atoi(c[1])
+	minute, _ := strconv.Atoi(c[2])
+	if hour == 12 {
+		hour = 0
+	}
+	if strings.EqualFold(c[3], "p") {
+		hour += 12
+	}
+
+	if d[3] != "" {
+		year, _ = strconv.Atoi(d[3])
+	}
+	if year == 0 {
+		return nearestYear(month.Month(), day, hour, minute, now), true
+	}
+	return time.Date(year, month.Month(), day, hour, minute, 0, 0, eastern), true
+}
+
+// nearestYear picks the year that puts a date without one closest to now, so
+// a schedule read in December can list January releases.
+func nearestYear(month time.Month, day, hour, minute int, now time.Time) time.Time {
+	best := time.Time{}
+	for year := now.Year() - 1; year <= now.Year()+1; year++ {
+		t := time.Date(year, month, day, hour, minute, 0, 0, eastern)
+		if best.IsZero() || absDuration(t.Sub(now)) < absDuration(best.Sub(now)) {
+			best = t
+		}
+	}
+	return best
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,176 @@
+// Package factcheck verifies that the figures in a generated text appear in the
+// source it was generated from. It is deliberately simple: every number,
+// percentage and month mentioned in the draft must be found in the source,
+// allowing for rounding and for "256K" versus "256,000" style formatting.
+package factcheck
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Kind is the kind of figure a claim refers to
+type Kind string
+
+const (
+	KindNumber  Kind = "number"
+	KindPercent Kind = "percent"
+	KindMonth   Kind = "month"
+)
+
+// Claim is a single figure mentioned in a text
+type Claim struct {
+	// Text is the figure as written, e.g. "0.4%" or "256K"
+	Text string `json:"text"`
+	Kind Kind   `json:"kind"`
+	// Value is the figure with any scale applied, so "256K" is 256000. Months
+	// are numbered from 1.
+	Value float64 `json:"value"`
+	// Mantissa is the figure as written without its scale, e.g. 256 for "256K"
+	Mantissa float64 `json:"mantissa"`
+	// Decimals is the number of decimal places the figure was written with
+	Decimals int `json:"decimals"`
+}
+
+// Report is the outcome of checking a draft against its source
+type Report struct {
+	Claims      []Claim `json:"claims"`
+	Unsupported []Claim `json:"unsupported"`
+}
+
+// OK reports whether every claim in the draft was found in the source
+func (r Report) OK() bool {
+	return len(r.Unsupported) == 0
+}
+
+// String lists the unsupported claims
+func (r Report) String() string {
+	if r.OK() {
+		return fmt.Sprintf("all %d claims supported", len(r.Claims))
+	}
+	texts := make([]string, len(r.Unsupported))
+	for i, c := range r.Unsupported {
+		texts[i] = fmt.Sprintf("%q", c.Text)
+	}
+	return fmt.Sprintf("%d of %d claims unsupported: %s", len(r.Unsupported), len(r.Claims), strings.Join(texts, ", "))
+}
+
+var (
+	numberRe = regexp.MustCompile(`(?i)(\d{1,3}(?:,\d{3})+(?:\.\d+)?|\d+(?:\.\d+)?|\.\d+)(\s*(?:%|percentage points?|percent|pct\b|k\b|thousand|mn\b|m\b|million|bn\b|b\b|billion))?`)
+	monthRe  = regexp.MustCompile(`\b(Jan(?:uary)?|Feb(?:ruary)?|Mar(?:ch)?|Apr(?:il)?|May|June?|July?|Aug(?:ust)?|Sep(?:t(?:ember)?)?|Oct(?:ober)?|Nov(?:ember)?|Dec(?:ember)?)\b`)
+)
+
+var months = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+// Extract returns every number, percentage and month mentioned in text, in the
+// order they appear. Digits that are part of a word, like "Q4" or "1st", are
+// not figures and are skipped.
+func Extract(text string) []Claim {
+	var claims []Claim
+
+	for _, m := range numberRe.FindAllStringSubmatchIndex(text, -1) {
+		start, end := m[0], m[1]
+		if start > 0 && isWordByte(text[start-1]) {
+			continue
+		}
+		if end < len(text) && isWordByte(text[end]) {
+			continue
+		}
+		digits := strings.ReplaceAll(text[m[2]:m[3]], ",", "")
+		mantissa, err := strconv.ParseFloat(digits, 64)
+		if err != nil {
+			continue
+		}
+		decimals := 0
+		if i := strings.IndexByte(digits, '.'); i >= 0 {
+			decimals = len(digits) - i - 1
+		}
+
+		claim := Claim{Text: text[start:end], Kind: KindNumber, Value: mantissa, Mantissa: mantissa, Decimals: decimals}
+		if m[4] >= 0 {
+			switch suffix := strings.ToLower(strings.TrimSpace(text[m[4]:m[5]])); {
+			case suffix == "%" || strings.HasPrefix(suffix, "percent") || suffix == "pct":
+				claim.Kind = KindPercent
+			case suffix == "k" || suffix == "thousand":
+				claim.Value = mantissa * 1e3
+			case suffix == "m" || suffix == "mn" || suffix == "million":
+				claim.Value = mantissa * 1e6
+			case suffix == "b" || suffix == "bn" || suffix == "billion":
+				claim.Value = mantissa * 1e9
+			}
+		}
+		claims = append(claims, claim)
+	}
+
+	for _, m := range monthRe.FindAllStringIndex(text, -1) {
+		name := text[m[0]:m[1]]
+		month := months[strings.ToLower(name[:3])]
+		claims = append(claims, Claim{Text: name, Kind: KindMonth, Value: float64(month), Mantissa: float64(month)})
+	}
+
+	return claims
+}
+
+// Check extracts the claims in draft and reports the ones that can't be found
+// in source. A number is supported if the source has the same figure, either
+// with or without its scale, or one that rounds to it at the draft's
+// precision. Signs are ignored because "fell 0.1%" and "-0.1%" state the same
+// figure.
+func Check(draft, source string) Report {
+	report := Report{Claims: Extract(draft)}
+	sourceClaims := Extract(source)
+
+	for _, claim := range report.Claims {
+		if !supported(claim, sourceClaims) {
+			report.Unsupported = append(report.Unsupported, claim)
+		}
+	}
+	return report
+}
+
+// supported reports whether claim matches any of the source claims
+func supported(claim Claim, source []Claim) bool {
+	for _, s := range source {
+		if (claim.Kind == KindMonth) != (s.Kind == KindMonth) {
+			continue
+		}
+		if claim.Kind == KindMonth {
+			if claim.Value == s.Value {
+				return true
+			}
+			continue
+		}
+		if matches(claim.Value, claim.Decimals, s.Value) || matches(claim.Mantissa, claim.Decimals, s.Mantissa) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether the source value equals value once rounded to the
+// given number of decimals. Rounding a non-zero figure down to zero doesn't
+// count, otherwise "0%" would be supported by any small change.
+func matches(value float64, decimals int, source float64) bool {
+	value, source = math.Abs(value), math.Abs(source)
+	if value == source {
+		return true
+	}
+	scale := math.Pow(10, float64(decimals))
+	rounded := math.Round(source*scale) / scale
+	if rounded == 0 && source != 0 {
+		return false
+	}
+	return math.Abs(rounded-value) < 1e-9
+}
+
+// isWordByte reports whether b can be part of a word, so figures glued to
+// letters are skipped
+func isWordByte(b byte) bool {
+	return b == '_' || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z') || ('0' <= b && b <= '9')
+}
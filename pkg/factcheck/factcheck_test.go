@@ -0,0 +1,98 @@
+package factcheck
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	testCases := []struct {
+		text string
+		want []Claim
+	}{
+		{
+			text: "CPI rose 0.4% in Dec, 2.9% y/y",
+			want: []Claim{
+				{Text: "0.4%", Kind: KindPercent, Value: 0.4, Mantissa: 0.4, Decimals: 1},
+				{Text: "2.9%", Kind: KindPercent, Value: 2.9, Mantissa: 2.9, Decimals: 1},
+				{Text: "Dec", Kind: KindMonth, Value: 12, Mantissa: 12},
+			},
+		},
+		{
+			text: "Payrolls +256K, openings 8.1 million, 1,234 claims",
+			want: []Claim{
+				{Text: "256K", Kind: KindNumber, Value: 256000, Mantissa: 256},
+				{Text: "8.1 million", Kind: KindNumber, Value: 8.1e6, Mantissa: 8.1, Decimals: 1},
+				{Text: "1,234", Kind: KindNumber, Value: 1234, Mantissa: 1234},
+			},
+		},
+		{
+			text: "Q4 was the 1st quarter with #CPI2025 below 3 percent",
+			want: []Claim{
+				{Text: "3 percent", Kind: KindPercent, Value: 3, Mantissa: 3},
+			},
+		},
+		{
+			text: "September and Sept. and may",
+			want: []Claim{
+				{Text: "September", Kind: KindMonth, Value: 9, Mantissa: 9},
+				{Text: "Sept", Kind: KindMonth, Value: 9, Mantissa: 9},
+			},
+		},
+		{
+			text: "No figures here",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.text, func(t *testing.T) {
+			got := Extract(tc.text)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Extract() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	source := "The Consumer Price Index for All Urban Consumers (CPI-U) increased 0.4 percent on a seasonally adjusted basis in December, after rising 0.3 percent in November. Over the last 12 months, the all items index increased 2.9 percent before seasonal adjustment. Total nonfarm payroll employment increased by 256,000 in December, and the number of job openings was 8.1 million."
+
+	testCases := []struct {
+		name            string
+		draft           string
+		wantUnsupported []string
+	}{
+		{"All supported", "CPI up 0.4% in December, 2.9% over 12 months", nil},
+		{"Scaled figures", "Payrolls +256K in Dec, 8.1M openings", nil},
+		{"Rounding", "Inflation near 3% y/y, openings 8M", nil},
+		{"Negative figures", "CPI -0.3% in Nov", nil},
+		{"Hallucinated number", "CPI up 0.5% in December", []string{"0.5%"}},
+		{"Wrong month", "CPI up 0.4% in January", []string{"January"}},
+		{"Rounding to zero", "CPI flat at 0% in December", []string{"0%"}},
+		{"Several", "Payrolls +300K, unemployment 4.3%", []string{"300K", "4.3%"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report := Check(tc.draft, source)
+			var got []string
+			for _, c := range report.Unsupported {
+				got = append(got, c.Text)
+			}
+			if !reflect.DeepEqual(got, tc.wantUnsupported) {
+				t.Errorf("Unsupported = %v, want %v", got, tc.wantUnsupported)
+			}
+			if report.OK() != (tc.wantUnsupported == nil) {
+				t.Errorf("OK() = %v, want %v", report.OK(), tc.wantUnsupported == nil)
+			}
+		})
+	}
+}
+
+func TestReportString(t *testing.T) {
+	report := Check("CPI up 0.5% in December", "CPI up 0.4 percent in December")
+	want := `1 of 2 claims unsupported: "0.5%"`
+	if got := report.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
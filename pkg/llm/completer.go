@@ -0,0 +1,174 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/openai/openai-go/v2"
+	"github.com/openai/openai-go/v2/option"
+	"github.com/openai/openai-go/v2/packages/respjson"
+)
+
+// Options tune a completion. Zero values leave the provider's default in place.
+type Options struct {
+	// Model is the model identifier for the API.
+	Model string
+	// Temperature is the sampling temperature, nil for the provider's default.
+	Temperature *float64
+	// MaxTokens caps the number of tokens in the completion, 0 for no cap.
+	MaxTokens int
+	// Seed asks the provider for deterministic sampling where it supports it.
+	Seed *int64
+}
+
+// Request is a completion whose response must follow a JSON schema.
+type Request struct {
+	// Schema is the JSON schema string the response must follow.
+	Schema       string
+	SystemPrompt string
+	UserPrompt   string
+	Options      Options
+}
+
+// Response is the result of a completion.
+type Response struct {
+	// Content is the response as a JSON string.
+	Content string `json:"content"`
+	// Reasoning is the model's reasoning, when the provider returns it.
+	Reasoning string `json:"reasoning,omitempty"`
+}
+
+// Completer performs completions against a language model. Implementations
+// return a *TransportError when the API can't be reached and a *RefusalError
+// when the model gives no answer.
+type Completer interface {
+	Complete(ctx context.Context, req Request) (Response, error)
+}
+
+// OpenAICompleter is a Completer for OpenAI-compatible chat completion APIs.
+// It keeps one client, so it should be created once and reused.
+type OpenAICompleter struct {
+	client openai.Client
+}
+
+// NewOpenAICompleter creates a completer for the API at baseURL. Extra options
+// are passed to the OpenAI client, e.g. option.WithHTTPClient. The client's own
+// retries are turned off, wrap the completer in a RetryingCompleter instead.
+func NewOpenAICompleter(apiKey, baseURL string, opts ...option.RequestOption) *OpenAICompleter {
+	opts = append([]option.RequestOption{
+		option.WithAPIKey(apiKey),
+		option.WithBaseURL(baseURL),
+		option.WithMaxRetries(0),
+	}, opts...)
+	return &OpenAICompleter{client: openai.NewClient(opts...)}
+}
+
+// Complete asks for a chat completion in strict JSON schema mode. The schema is
+// also included in the system prompt for providers that ignore the response
+// format.
+func (c *OpenAICompleter) Complete(ctx context.Context, req Request) (Response, error) {
+	// Unmarshal the JSON schema string back to a map for the OpenAI API
+	var schemaMap map[string]interface{}
+	if err := json.Unmarshal([]byte(req.Schema), &schemaMap); err != nil {
+		return Response{}, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+
+	// Construct the system message.
+	systemMessage := fmt.Sprintf("%s Here's the json schema you need to adhere to: <schema>%s</schema>", req.SystemPrompt, req.Schema)
+
+	params := openai.ChatCompletionNewParams{
+		Model: req.Options.Model,
+		Messages: []openai.ChatCompletionMessageParamUnion{
+			openai.SystemMessage(systemMessage),
+			openai.UserMessage(req.UserPrompt),
+		},
+		ResponseFormat: openai.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONSchema: &openai.ResponseFormatJSONSchemaParam{
+				Type: "json_schema",
+				JSONSchema: openai.ResponseFormatJSONSchemaJSONSchemaParam{
+					Name:   "response",
+					Schema: schemaMap,
+					Strict: openai.Bool(true),
+				},
+			},
+		},
+	}
+	if req.Options.Temperature != nil {
+		params.Temperature = openai.Float(*req.Options.Temperature)
+	}
+	if req.Options.MaxTokens > 0 {
+		params.MaxCompletionTokens = openai.Int(int64(req.Options.MaxTokens))
+	}
+	if req.Options.Seed != nil {
+		params.Seed = openai.Int(*req.Options.Seed)
+	}
+
+	resp, err := c.client.Chat.Completions.New(ctx, params)
+	if err != nil {
+		return Response{}, newTransportError(err, time.Now())
+	}
+
+	// Check if the response contains any choices and content.
+	if len(resp.Choices) == 0 || resp.Choices[0].Message.Content == "" {
+		fullResponse, _ := json.MarshalIndent(resp, "", "  ")
+		log.Printf("Received an empty or invalid response from the API: %s\n", string(fullResponse))
+		refusal := ""
+		if len(resp.Choices) > 0 {
+			refusal = resp.Choices[0].Message.Refusal
+		}
+		return Response{}, &RefusalError{Refusal: refusal}
+	}
+
+	message := resp.Choices[0].Message
+	return Response{Content: message.Content, Reasoning: reasoningContent(message.JSON.ExtraFields)}, nil
+}
+
+// reasoningFields are the non-standard message fields reasoning models return
+// their reasoning in: reasoning_content for DeepSeek and vLLM, reasoning for
+// OpenRouter.
+var reasoningFields = []string{"reasoning_content", "reasoning"}
+
+// reasoningContent returns the reasoning from a message's extra fields, which
+// the official library keeps as raw JSON.
+func reasoningContent(fields map[string]respjson.Field) string {
+	for _, name := range reasoningFields {
+		field, ok := fields[name]
+		if !ok {
+			continue
+		}
+		var reasoning string
+		if err := json.Unmarshal([]byte(field.Raw()), &reasoning); err == nil && reasoning != "" {
+			return reasoning
+		}
+	}
+	return ""
+}
+
+// OpenAICompleters reuses one OpenAICompleter per API key and base URL, so
+// callers that resolve credentials per call don't build a client every time.
+// The zero value is ready to use.
+type OpenAICompleters struct {
+	mu         sync.Mutex
+	completers map[[2]string]*OpenAICompleter
+}
+
+// Get returns the completer for apiKey and baseURL, creating it on first use.
+func (p *OpenAICompleters) Get(apiKey, baseURL string) *OpenAICompleter {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := [2]string{apiKey, baseURL}
+	if c, ok := p.completers[key]; ok {
+		return c
+	}
+	if p.completers == nil {
+		p.completers = make(map[[2]string]*OpenAICompleter)
+	}
+	c := NewOpenAICompleter(apiKey, baseURL)
+	p.completers[key] = c
+	return c
+}
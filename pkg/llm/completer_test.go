@@ -0,0 +1,235 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testSchema = `{"type":"object","properties":{"tweet":{"type":"string"}},"required":["tweet"]}`
+
+// chatServer answers chat completions with content and records the last
+// request body.
+func chatServer(t *testing.T, content string, body *map[string]interface{}) *httptest.Server {
+	t.Helper()
+	var message map[string]interface{}
+	if content != "" {
+		message = map[string]interface{}{"role": "assistant", "content": content}
+	}
+	return messageServer(t, message, body)
+}
+
+// messageServer answers chat completions with message, or no choices when it's
+// nil, and records the last request body.
+func messageServer(t *testing.T, message map[string]interface{}, body *map[string]interface{}) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		choices := []map[string]interface{}{}
+		if message != nil {
+			choices = append(choices, map[string]interface{}{
+				"index":         0,
+				"finish_reason": "stop",
+				"message":       message,
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":      "chatcmpl-test",
+			"object":  "chat.completion",
+			"model":   "test-model",
+			"choices": choices,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOpenAICompleter(t *testing.T) {
+	temperature := 0.2
+	seed := int64(42)
+
+	tests := []struct {
+		name      string
+		options   Options
+		wantKeys  map[string]interface{}
+		wantUnset []string
+	}{
+		{
+			name:      "Defaults",
+			options:   Options{Model: "test-model"},
+			wantKeys:  map[string]interface{}{"model": "test-model"},
+			wantUnset: []string{"temperature", "max_completion_tokens", "seed"},
+		},
+		{
+			name:    "All options",
+			options: Options{Model: "test-model", Temperature: &temperature, MaxTokens: 300, Seed: &seed},
+			wantKeys: map[string]interface{}{
+				"model":                 "test-model",
+				"temperature":           0.2,
+				"max_completion_tokens": 300.0,
+				"seed":                  42.0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]interface{}
+			server := chatServer(t, `{"tweet":"hello"}`, &body)
+
+			completer := NewOpenAICompleter("test-key", server.URL)
+			resp, err := completer.Complete(context.Background(), Request{
+				Schema:       testSchema,
+				SystemPrompt: "Write a tweet.",
+				UserPrompt:   "Say hello.",
+				Options:      tt.options,
+			})
+			if err != nil {
+				t.Fatalf("Complete returned an error: %v", err)
+			}
+			if resp.Content != `{"tweet":"hello"}` {
+				t.Errorf("Got content %q", resp.Content)
+			}
+
+			for key, want := range tt.wantKeys {
+				if body[key] != want {
+					t.Errorf("Expected %s to be %v, got %v", key, want, body[key])
+				}
+			}
+			for _, key := range tt.wantUnset {
+				if _, ok := body[key]; ok {
+					t.Errorf("Expected %s to be left unset, got %v", key, body[key])
+				}
+			}
+			format, _ := body["response_format"].(map[string]interface{})
+			if format["type"] != "json_schema" {
+				t.Errorf("Expected a json_schema response format, got %v", body["response_format"])
+			}
+		})
+	}
+}
+
+func TestOpenAICompleterReasoning(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields map[string]interface{}
+		want   string
+	}{
+		{name: "DeepSeek and vLLM", fields: map[string]interface{}{"reasoning_content": "The abstract proposes a new kernel."}, want: "The abstract proposes a new kernel."},
+		{name: "OpenRouter", fields: map[string]interface{}{"reasoning": "Quantization cuts memory."}, want: "Quantization cuts memory."},
+		{name: "Both prefer reasoning_content", fields: map[string]interface{}{"reasoning_content": "first", "reasoning": "second"}, want: "first"},
+		{name: "Null reasoning", fields: map[string]interface{}{"reasoning_content": nil, "reasoning": "fallback"}, want: "fallback"},
+		{name: "No reasoning", fields: map[string]interface{}{}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := map[string]interface{}{"role": "assistant", "content": `{"tweet":"hello"}`}
+			for k, v := range tt.fields {
+				message[k] = v
+			}
+			var body map[string]interface{}
+			server := messageServer(t, message, &body)
+
+			resp, err := NewOpenAICompleter("test-key", server.URL).Complete(context.Background(), Request{Schema: testSchema})
+			if err != nil {
+				t.Fatalf("Complete returned an error: %v", err)
+			}
+			if resp.Reasoning != tt.want {
+				t.Errorf("Got reasoning %q, want %q", resp.Reasoning, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenAICompleterErrors(t *testing.T) {
+	t.Run("Empty response", func(t *testing.T) {
+		var body map[string]interface{}
+		server := chatServer(t, "", &body)
+
+		_, err := NewOpenAICompleter("test-key", server.URL).Complete(context.Background(), Request{Schema: testSchema})
+		if !errors.Is(err, LLMResponseError) {
+			t.Errorf("Expected LLMResponseError, got %v", err)
+		}
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"error":{"message":"bad request"}}`, http.StatusBadRequest)
+		}))
+		t.Cleanup(server.Close)
+
+		_, err := NewOpenAICompleter("test-key", server.URL).Complete(context.Background(), Request{Schema: testSchema})
+		var transportErr *TransportError
+		if !errors.As(err, &transportErr) {
+			t.Errorf("Expected a *TransportError, got %v", err)
+		}
+	})
+
+	t.Run("Invalid schema", func(t *testing.T) {
+		_, err := NewOpenAICompleter("test-key", "http://127.0.0.1:0").Complete(context.Background(), Request{Schema: "{"})
+		if err == nil {
+			t.Error("Expected an error for an invalid schema")
+		}
+	})
+}
+
+func TestOpenAICompleters(t *testing.T) {
+	var pool OpenAICompleters
+	a := pool.Get("key", "https://api.example.com/v1")
+	if pool.Get("key", "https://api.example.com/v1") != a {
+		t.Error("Expected the same completer for the same key and base URL")
+	}
+	if pool.Get("other-key", "https://api.example.com/v1") == a {
+		t.Error("Expected a different completer for a different key")
+	}
+}
+
+func TestFakeCompleter(t *testing.T) {
+	failure := errors.New("rate limited")
+	fake := NewFakeCompleter(FakeReply{Content: `{"tweet":"one"}`, Reasoning: "because"}).
+		Fail(failure).
+		Reply(`{"tweet":"two"}`)
+
+	ctx := context.Background()
+	wants := []struct {
+		content   string
+		reasoning string
+		err       error
+	}{
+		{content: `{"tweet":"one"}`, reasoning: "because"},
+		{err: failure},
+		{content: `{"tweet":"two"}`},
+		{err: ErrScriptExhausted},
+	}
+	for i, want := range wants {
+		resp, err := fake.Complete(ctx, Request{UserPrompt: "prompt", Options: Options{Model: "m"}})
+		if !errors.Is(err, want.err) {
+			t.Errorf("Call %d: expected error %v, got %v", i, want.err, err)
+		}
+		if resp.Content != want.content || resp.Reasoning != want.reasoning {
+			t.Errorf("Call %d: got %+v", i, resp)
+		}
+	}
+
+	requests := fake.Requests()
+	if len(requests) != len(wants) {
+		t.Fatalf("Expected %d recorded requests, got %d", len(wants), len(requests))
+	}
+	if requests[0].UserPrompt != "prompt" || requests[0].Options.Model != "m" {
+		t.Errorf("Recorded request doesn't match: %+v", requests[0])
+	}
+	if fake.Remaining() != 0 {
+		t.Errorf("Expected no remaining replies, got %d", fake.Remaining())
+	}
+}
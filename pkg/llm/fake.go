@@ -0,0 +1,87 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrScriptExhausted is returned by a FakeCompleter called more often than it
+// has scripted replies.
+var ErrScriptExhausted = errors.New("fake completer has no more scripted replies")
+
+// FakeReply is one scripted answer of a FakeCompleter.
+type FakeReply struct {
+	Content   string
+	Reasoning string
+	// Err, when set, is returned instead of a response.
+	Err error
+}
+
+// FakeCompleter is a Completer for tests that answers from a script instead of
+// calling a model. Each call returns the next reply, and every request is
+// recorded so tests can check the prompts and options. It's safe for
+// concurrent use.
+type FakeCompleter struct {
+	mu       sync.Mutex
+	replies  []FakeReply
+	requests []Request
+}
+
+// NewFakeCompleter creates a fake that answers with replies in order.
+func NewFakeCompleter(replies ...FakeReply) *FakeCompleter {
+	return &FakeCompleter{replies: replies}
+}
+
+// Reply queues a reply with the given content.
+func (f *FakeCompleter) Reply(content string) *FakeCompleter {
+	return f.Script(FakeReply{Content: content})
+}
+
+// Fail queues a reply that fails with err.
+func (f *FakeCompleter) Fail(err error) *FakeCompleter {
+	return f.Script(FakeReply{Err: err})
+}
+
+// Script queues replies after the ones already scripted.
+func (f *FakeCompleter) Script(replies ...FakeReply) *FakeCompleter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.replies = append(f.replies, replies...)
+	return f
+}
+
+// Complete records req and returns the next scripted reply, or
+// ErrScriptExhausted when there are none left.
+func (f *FakeCompleter) Complete(ctx context.Context, req Request) (Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, req)
+	if err := ctx.Err(); err != nil {
+		return Response{}, err
+	}
+	if len(f.replies) == 0 {
+		return Response{}, ErrScriptExhausted
+	}
+	reply := f.replies[0]
+	f.replies = f.replies[1:]
+	if reply.Err != nil {
+		return Response{}, reply.Err
+	}
+	return Response{Content: reply.Content, Reasoning: reply.Reasoning}, nil
+}
+
+// Requests returns the requests made so far, oldest first.
+func (f *FakeCompleter) Requests() []Request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Request(nil), f.requests...)
+}
+
+// Remaining returns how many scripted replies haven't been used.
+func (f *FakeCompleter) Remaining() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.replies)
+}
@@ -5,17 +5,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 
 	"github.com/invopop/jsonschema"
-	"github.com/openai/openai-go/v2"
-	"github.com/openai/openai-go/v2/option"
 )
 
 // LLMResponseError is a custom error type for when the LLM fails to generate a response.
 var LLMResponseError = errors.New("failed to generate a valid LLM response")
 
 // CompleteWithSchema performs a LLM completion with a specified JSON schema using the official OpenAI Go library.
+// It builds a new client on every call; callers making more than one completion
+// should create an OpenAICompleter once instead. Transient failures are retried
+// as by a RetryingCompleter.
 //
 // Parameters:
 //   - ctx: The context for the request.
@@ -29,7 +29,7 @@ var LLMResponseError = errors.New("failed to generate a valid LLM response")
 // Returns:
 //   - A tuple containing:
 //   - The response content as a JSON string.
-//   - An optional reasoning content string (remains empty as it's a non-standard field).
+//   - The reasoning content, for providers that return it, otherwise empty.
 //   - An error if the request fails.
 func CompleteWithSchema(
 	ctx context.Context,
@@ -40,63 +40,16 @@ func CompleteWithSchema(
 	userPrompt string,
 	model string,
 ) (string, string, error) {
-	// Initialize the OpenAI client using the official library's pattern.
-	// We use `option.WithBaseURL` to specify a custom endpoint.
-	client := openai.NewClient(
-		option.WithAPIKey(apiKey),
-		option.WithBaseURL(baseURL),
-	)
-
-	// Schema is already a JSON string, no need to marshal
-	schemaStr := schema
-
-	// Unmarshal the JSON schema string back to a map for the OpenAI API
-	var schemaMap map[string]interface{}
-	if err := json.Unmarshal([]byte(schema), &schemaMap); err != nil {
-		return "", "", fmt.Errorf("failed to unmarshal schema string to map: %w", err)
-	}
-
-	// Construct the system message.
-	systemMessage := fmt.Sprintf("%s Here's the json schema you need to adhere to: <schema>%s</schema>", systemPrompt, schemaStr)
-
-	// Create the chat completion request using the official library's builder-style API.
-	// The parameters are passed in a `ChatCompletionNewParams` struct.
-	params := openai.ChatCompletionNewParams{
-		Model: model,
-		Messages: []openai.ChatCompletionMessageParamUnion{
-			openai.SystemMessage(systemMessage),
-			openai.UserMessage(userPrompt),
-		},
-		// The response format structure is slightly different in the official library.
-		ResponseFormat: openai.ChatCompletionNewParamsResponseFormatUnion{
-			OfJSONSchema: &openai.ResponseFormatJSONSchemaParam{
-				Type: "json_schema",
-				JSONSchema: openai.ResponseFormatJSONSchemaJSONSchemaParam{
-					Name:   "response",
-					Schema: schemaMap,
-					Strict: openai.Bool(true),
-				},
-			},
-		},
-	}
-
-	// The official library's client methods are organized by API resource (e.g., Chat, Images).
-	resp, err := client.Chat.Completions.New(ctx, params)
+	resp, err := NewRetryingCompleter(NewOpenAICompleter(apiKey, baseURL)).Complete(ctx, Request{
+		Schema:       schema,
+		SystemPrompt: systemPrompt,
+		UserPrompt:   userPrompt,
+		Options:      Options{Model: model},
+	})
 	if err != nil {
-		return "", "", fmt.Errorf("chat completion request failed: %w", err)
-	}
-
-	// Check if the response contains any choices and content.
-	if len(resp.Choices) == 0 || resp.Choices[0].Message.Content == "" {
-		fullResponse, _ := json.MarshalIndent(resp, "", "  ")
-		log.Printf("Received an empty or invalid response from the API: %s\n", string(fullResponse))
-		return "", "", LLMResponseError
+		return "", "", err
 	}
-
-	content := resp.Choices[0].Message.Content
-	reasoning := "" // This non-standard field is not available in the official library.
-
-	return content, reasoning, nil
+	return resp.Content, resp.Reasoning, nil
 }
 
 // GenerateSchemaFromType generates a JSON schema from a Go struct type using jsonschema reflector
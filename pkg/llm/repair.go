@@ -0,0 +1,70 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultRepairAttempts is how many times a ValidatingCompleter asks the model
+// to fix a response before giving up.
+const DefaultRepairAttempts = 2
+
+// ValidatingCompleter is a Completer that checks each response against the
+// request's schema instead of trusting the provider's strict mode, which
+// gateways often ignore for keywords like maxLength. A response that breaks the
+// schema is sent back to the model with the problems for up to MaxRepairs
+// attempts; after that Complete fails with a *SchemaError.
+type ValidatingCompleter struct {
+	Completer  Completer
+	MaxRepairs int
+}
+
+// NewValidatingCompleter wraps c with DefaultRepairAttempts repair attempts.
+func NewValidatingCompleter(c Completer) *ValidatingCompleter {
+	return &ValidatingCompleter{Completer: c, MaxRepairs: DefaultRepairAttempts}
+}
+
+// Complete performs the completion, repairing responses that break the schema.
+// Transport errors and refusals are returned right away.
+func (v *ValidatingCompleter) Complete(ctx context.Context, req Request) (Response, error) {
+	repair := req
+	for attempt := 0; ; attempt++ {
+		resp, err := v.Completer.Complete(ctx, repair)
+		if err != nil {
+			return Response{}, err
+		}
+
+		err = Validate(req.Schema, resp.Content)
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			// Either valid or the schema itself is broken, which no repair fixes
+			return resp, err
+		}
+		if attempt >= v.MaxRepairs {
+			return Response{}, &SchemaError{
+				Content: resp.Content,
+				Err:     fmt.Errorf("still invalid after %d repair attempts: %w", attempt, validationErr),
+			}
+		}
+		repair.UserPrompt = repairPrompt(req.UserPrompt, resp.Content, validationErr)
+	}
+}
+
+// repairPrompt repeats the original prompt with the invalid response and what's
+// wrong with it.
+func repairPrompt(userPrompt, content string, err *ValidationError) string {
+	var b strings.Builder
+	b.WriteString(userPrompt)
+	b.WriteString("\n\nYour previous response was:\n<response>")
+	b.WriteString(content)
+	b.WriteString("</response>\nIt doesn't match the JSON schema:\n")
+	for _, problem := range err.Problems {
+		b.WriteString("- ")
+		b.WriteString(problem)
+		b.WriteString("\n")
+	}
+	b.WriteString("Respond again with JSON that fixes these problems and follows the schema exactly.")
+	return b.String()
+}
@@ -0,0 +1,197 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openai/openai-go/v2"
+)
+
+// ErrInvalidSchema is returned when a request's schema isn't valid JSON, which
+// no retry can fix.
+var ErrInvalidSchema = errors.New("invalid JSON schema")
+
+const (
+	// DefaultRetryAttempts is how many times a RetryingCompleter tries a
+	// request, including the first attempt.
+	DefaultRetryAttempts = 3
+	// DefaultRetryBaseDelay is the backoff before the first retry, doubled for
+	// every retry after it.
+	DefaultRetryBaseDelay = time.Second
+	// DefaultRetryMaxDelay caps the backoff. Longer Retry-After waits are left
+	// to the caller.
+	DefaultRetryMaxDelay = 30 * time.Second
+)
+
+// newTransportError classifies an error from the OpenAI library, keeping the
+// status, error code and Retry-After of API errors.
+func newTransportError(err error, now time.Time) *TransportError {
+	transportErr := &TransportError{Err: err}
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) {
+		transportErr.StatusCode = apiErr.StatusCode
+		transportErr.Code = apiErr.Code
+		if apiErr.Response != nil {
+			transportErr.RetryAfter = parseRetryAfter(apiErr.Response.Header, now)
+		}
+	}
+	return transportErr
+}
+
+// parseRetryAfter reads how long to wait from the retry-after-ms header OpenAI
+// sends or the standard Retry-After header, in seconds or as an HTTP date.
+func parseRetryAfter(header http.Header, now time.Time) time.Duration {
+	if ms, err := strconv.ParseFloat(header.Get("Retry-After-Ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+	value := header.Get("Retry-After")
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// ContextLengthExceeded reports whether the prompt was too long for the model.
+// Gateways don't agree on a code for it, so the message is checked too.
+func (e *TransportError) ContextLengthExceeded() bool {
+	if e.Code == "context_length_exceeded" {
+		return true
+	}
+	msg := strings.ToLower(e.Err.Error())
+	return strings.Contains(msg, "context length") || strings.Contains(msg, "context_length") ||
+		strings.Contains(msg, "maximum context")
+}
+
+// Retryable reports whether the request may succeed if tried again: network
+// failures, timeouts, rate limits and server errors. Authentication failures,
+// rejected requests such as a bad schema, and prompts over the context length
+// fail the same way every time.
+func (e *TransportError) Retryable() bool {
+	if e.ContextLengthExceeded() {
+		return false
+	}
+	switch {
+	case e.StatusCode == 0:
+		return true
+	case e.StatusCode == http.StatusRequestTimeout, e.StatusCode == http.StatusConflict,
+		e.StatusCode == http.StatusTooManyRequests:
+		return true
+	case e.StatusCode >= 500:
+		return true
+	}
+	return false
+}
+
+// IsRetryable reports whether a completion that failed with err may succeed if
+// tried again. Empty answers are retried, explicit refusals aren't, and
+// responses that broke the schema may come out differently next time.
+func IsRetryable(err error) bool {
+	var transportErr *TransportError
+	var refusalErr *RefusalError
+	switch {
+	case errors.Is(err, ErrInvalidSchema):
+		return false
+	case errors.As(err, &transportErr):
+		return transportErr.Retryable()
+	case errors.As(err, &refusalErr):
+		return refusalErr.Refusal == ""
+	}
+	return true
+}
+
+// RetryAfter returns how long the API asked to wait before trying again.
+func RetryAfter(err error) (time.Duration, bool) {
+	var transportErr *TransportError
+	if errors.As(err, &transportErr) && transportErr.RetryAfter > 0 {
+		return transportErr.RetryAfter, true
+	}
+	return 0, false
+}
+
+// RetryingCompleter is a Completer that retries transient failures. It waits
+// as long as the API asks with Retry-After and otherwise backs off
+// exponentially with jitter. A Retry-After longer than MaxDelay is returned to
+// the caller rather than waited out, so a worker isn't held up for minutes.
+type RetryingCompleter struct {
+	Completer Completer
+	// MaxAttempts is how many times a request is tried, including the first.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+
+	// sleep waits for d or until ctx is done. Tests replace it.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewRetryingCompleter wraps c with the default attempts and delays.
+func NewRetryingCompleter(c Completer) *RetryingCompleter {
+	return &RetryingCompleter{
+		Completer:   c,
+		MaxAttempts: DefaultRetryAttempts,
+		BaseDelay:   DefaultRetryBaseDelay,
+		MaxDelay:    DefaultRetryMaxDelay,
+	}
+}
+
+// Complete performs the completion, retrying it while it fails with a
+// retryable error and attempts remain. It returns the last error.
+func (r *RetryingCompleter) Complete(ctx context.Context, req Request) (Response, error) {
+	sleep := r.sleep
+	if sleep == nil {
+		sleep = sleepContext
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := r.Completer.Complete(ctx, req)
+		if err == nil || !IsRetryable(err) || attempt >= r.MaxAttempts {
+			return resp, err
+		}
+
+		delay, ok := RetryAfter(err)
+		if ok && delay > r.MaxDelay {
+			return resp, err
+		}
+		if !ok {
+			delay = r.backoff(attempt)
+		}
+		if sleepErr := sleep(ctx, delay); sleepErr != nil {
+			return Response{}, fmt.Errorf("gave up retrying: %w (last error: %v)", sleepErr, err)
+		}
+	}
+}
+
+// backoff returns the jittered delay after the given attempt: a random
+// duration between half and all of BaseDelay doubled for each earlier retry,
+// capped at MaxDelay.
+func (r *RetryingCompleter) backoff(attempt int) time.Duration {
+	delay := r.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > r.MaxDelay {
+		delay = r.MaxDelay
+	}
+	half := int64(delay / 2)
+	if half <= 0 {
+		return delay
+	}
+	return time.Duration(half + rand.Int64N(half+1))
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
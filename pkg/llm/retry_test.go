@@ -0,0 +1,236 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTransportErrorClassification(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		header         map[string]string
+		body           string
+		wantRetryable  bool
+		wantRetryAfter time.Duration
+	}{
+		{name: "Unauthorized", status: http.StatusUnauthorized, body: `{"error":{"message":"invalid api key","code":"invalid_api_key"}}`},
+		{name: "Forbidden", status: http.StatusForbidden, body: `{"error":{"message":"forbidden"}}`},
+		{name: "Bad schema", status: http.StatusBadRequest, body: `{"error":{"message":"Invalid schema for response_format"}}`},
+		{name: "Context length", status: http.StatusBadRequest, body: `{"error":{"message":"too long","code":"context_length_exceeded"}}`},
+		{name: "Context length as a server error", status: http.StatusInternalServerError, body: `{"error":{"message":"This model's maximum context length is 8192 tokens"}}`},
+		{
+			name:           "Rate limited with Retry-After",
+			status:         http.StatusTooManyRequests,
+			header:         map[string]string{"Retry-After": "7"},
+			body:           `{"error":{"message":"slow down"}}`,
+			wantRetryable:  true,
+			wantRetryAfter: 7 * time.Second,
+		},
+		{
+			name:           "Rate limited with retry-after-ms",
+			status:         http.StatusTooManyRequests,
+			header:         map[string]string{"Retry-After-Ms": "1500", "Retry-After": "2"},
+			body:           `{"error":{"message":"slow down"}}`,
+			wantRetryable:  true,
+			wantRetryAfter: 1500 * time.Millisecond,
+		},
+		{name: "Rate limited", status: http.StatusTooManyRequests, body: `{"error":{"message":"slow down"}}`, wantRetryable: true},
+		{name: "Bad gateway", status: http.StatusBadGateway, body: `{"error":{"message":"upstream failed"}}`, wantRetryable: true},
+		{name: "Unavailable", status: http.StatusServiceUnavailable, body: `{"error":{"message":"overloaded"}}`, wantRetryable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			t.Cleanup(server.Close)
+
+			_, err := NewOpenAICompleter("test-key", server.URL).Complete(context.Background(), Request{Schema: testSchema})
+			var transportErr *TransportError
+			if !errors.As(err, &transportErr) {
+				t.Fatalf("Expected a *TransportError, got %v", err)
+			}
+			if transportErr.StatusCode != tt.status {
+				t.Errorf("StatusCode = %d, want %d", transportErr.StatusCode, tt.status)
+			}
+			if got := IsRetryable(err); got != tt.wantRetryable {
+				t.Errorf("IsRetryable = %v, want %v", got, tt.wantRetryable)
+			}
+			if got, _ := RetryAfter(err); got != tt.wantRetryAfter {
+				t.Errorf("RetryAfter = %v, want %v", got, tt.wantRetryAfter)
+			}
+			if requests != 1 {
+				t.Errorf("Expected the client not to retry on its own, got %d requests", requests)
+			}
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "Network failure", err: &TransportError{Err: errors.New("connection reset")}, want: true},
+		{name: "Empty response", err: &RefusalError{}, want: true},
+		{name: "Refusal", err: &RefusalError{Refusal: "I can't help with that"}, want: false},
+		{name: "Invalid response", err: &SchemaError{Err: errors.New("missing tweet")}, want: true},
+		{name: "Invalid schema", err: Validate("{", `{}`), want: false},
+		{name: "Other", err: errors.New("something else"), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 15, 13, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{name: "Seconds", header: http.Header{"Retry-After": {"20"}}, want: 20 * time.Second},
+		{name: "Fractional seconds", header: http.Header{"Retry-After": {"0.5"}}, want: 500 * time.Millisecond},
+		{name: "HTTP date", header: http.Header{"Retry-After": {"Wed, 15 Jan 2025 13:31:00 GMT"}}, want: time.Minute},
+		{name: "Date in the past", header: http.Header{"Retry-After": {"Wed, 15 Jan 2025 13:29:00 GMT"}}},
+		{name: "Garbage", header: http.Header{"Retry-After": {"soon"}}},
+		{name: "Missing", header: http.Header{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.header, now); got != tt.want {
+				t.Errorf("Got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newTestRetrier returns a retrier around fake that records its waits instead
+// of sleeping.
+func newTestRetrier(fake *FakeCompleter, waits *[]time.Duration) *RetryingCompleter {
+	r := NewRetryingCompleter(fake)
+	r.sleep = func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return ctx.Err()
+	}
+	return r
+}
+
+func TestRetryingCompleter(t *testing.T) {
+	ctx := context.Background()
+	unavailable := &TransportError{StatusCode: http.StatusServiceUnavailable, Err: errors.New("overloaded")}
+	rateLimited := &TransportError{StatusCode: http.StatusTooManyRequests, RetryAfter: 3 * time.Second, Err: errors.New("slow down")}
+	unauthorized := &TransportError{StatusCode: http.StatusUnauthorized, Err: errors.New("invalid api key")}
+
+	t.Run("Retries transient failures", func(t *testing.T) {
+		var waits []time.Duration
+		fake := NewFakeCompleter().Fail(unavailable).Fail(rateLimited).Reply(`{"tweet":"hi"}`)
+		resp, err := newTestRetrier(fake, &waits).Complete(ctx, Request{})
+		if err != nil {
+			t.Fatalf("Complete returned an error: %v", err)
+		}
+		if resp.Content != `{"tweet":"hi"}` {
+			t.Errorf("Got %q", resp.Content)
+		}
+		if len(waits) != 2 {
+			t.Fatalf("Expected 2 waits, got %v", waits)
+		}
+		if waits[0] < DefaultRetryBaseDelay/2 || waits[0] > DefaultRetryBaseDelay {
+			t.Errorf("First backoff %v outside [%v, %v]", waits[0], DefaultRetryBaseDelay/2, DefaultRetryBaseDelay)
+		}
+		if waits[1] != 3*time.Second {
+			t.Errorf("Expected to wait out Retry-After, waited %v", waits[1])
+		}
+	})
+
+	t.Run("Doesn't retry permanent failures", func(t *testing.T) {
+		var waits []time.Duration
+		fake := NewFakeCompleter().Fail(unauthorized).Reply(`{"tweet":"hi"}`)
+		_, err := newTestRetrier(fake, &waits).Complete(ctx, Request{})
+		if !errors.Is(err, unauthorized) {
+			t.Errorf("Expected the auth failure, got %v", err)
+		}
+		if len(waits) != 0 || fake.Remaining() != 1 {
+			t.Errorf("Expected a single attempt, waited %v", waits)
+		}
+	})
+
+	t.Run("Gives up after MaxAttempts", func(t *testing.T) {
+		var waits []time.Duration
+		fake := NewFakeCompleter().Fail(unavailable).Fail(unavailable).Fail(unavailable).Reply(`{"tweet":"hi"}`)
+		_, err := newTestRetrier(fake, &waits).Complete(ctx, Request{})
+		if !errors.Is(err, unavailable) {
+			t.Errorf("Expected the last failure, got %v", err)
+		}
+		if len(fake.Requests()) != DefaultRetryAttempts {
+			t.Errorf("Expected %d attempts, got %d", DefaultRetryAttempts, len(fake.Requests()))
+		}
+	})
+
+	t.Run("Leaves long Retry-After waits to the caller", func(t *testing.T) {
+		var waits []time.Duration
+		long := &TransportError{StatusCode: http.StatusTooManyRequests, RetryAfter: 5 * time.Minute, Err: errors.New("quota")}
+		fake := NewFakeCompleter().Fail(long).Reply(`{"tweet":"hi"}`)
+		_, err := newTestRetrier(fake, &waits).Complete(ctx, Request{})
+		if delay, ok := RetryAfter(err); !ok || delay != 5*time.Minute {
+			t.Errorf("Expected the Retry-After to be returned, got %v", err)
+		}
+		if len(waits) != 0 {
+			t.Errorf("Expected no wait, got %v", waits)
+		}
+	})
+
+	t.Run("Stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		// The fake checks the context itself, so fail regardless of it
+		r := NewRetryingCompleter(completerFunc(func(ctx context.Context, req Request) (Response, error) {
+			return Response{}, unavailable
+		}))
+		_, err := r.Complete(ctx, Request{})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the cancellation, got %v", err)
+		}
+	})
+}
+
+// completerFunc adapts a function to Completer.
+type completerFunc func(ctx context.Context, req Request) (Response, error)
+
+func (f completerFunc) Complete(ctx context.Context, req Request) (Response, error) {
+	return f(ctx, req)
+}
+
+func TestRetryingCompleterBackoff(t *testing.T) {
+	r := NewRetryingCompleter(nil)
+	for attempt := 1; attempt <= 10; attempt++ {
+		want := DefaultRetryBaseDelay << (attempt - 1)
+		if want > DefaultRetryMaxDelay {
+			want = DefaultRetryMaxDelay
+		}
+		for i := 0; i < 20; i++ {
+			if got := r.backoff(attempt); got < want/2 || got > want {
+				t.Fatalf("Attempt %d: backoff %v outside [%v, %v]", attempt, got, want/2, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// TransportError is returned when a completion request couldn't be made or the
+// API answered with an error.
+type TransportError struct {
+	// StatusCode is the HTTP status the API answered with, 0 when no answer
+	// arrived.
+	StatusCode int
+	// Code is the API's error code, e.g. "context_length_exceeded".
+	Code string
+	// RetryAfter is how long the API asked to wait before trying again, 0 when
+	// it didn't say.
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("chat completion request failed: %v", e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// RefusalError is returned when the model refused the request or answered with
+// nothing. It matches LLMResponseError with errors.Is.
+type RefusalError struct {
+	// Refusal is the model's explanation, empty when the response was empty.
+	Refusal string
+}
+
+func (e *RefusalError) Error() string {
+	if e.Refusal == "" {
+		return LLMResponseError.Error() + ": empty response"
+	}
+	return fmt.Sprintf("%v: model refused: %s", LLMResponseError, e.Refusal)
+}
+
+func (e *RefusalError) Unwrap() error {
+	return LLMResponseError
+}
+
+// SchemaError is returned when a response doesn't match the schema it was
+// asked to follow. It matches LLMResponseError with errors.Is.
+type SchemaError struct {
+	// Content is the response as received.
+	Content string
+	Err     error
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("response doesn't match the schema: %v", e.Err)
+}
+
+func (e *SchemaError) Unwrap() error {
+	return e.Err
+}
+
+func (e *SchemaError) Is(target error) bool {
+	return target == LLMResponseError
+}
+
+// SchemaFor returns the JSON schema string for T, which should be a struct.
+func SchemaFor[T any]() (string, error) {
+	var v T
+	return GenerateSchema(v)
+}
+
+// Decode decodes a response into T. Content that isn't a single JSON object
+// with only T's fields fails with a *SchemaError.
+func Decode[T any](content string) (T, error) {
+	var v T
+	dec := json.NewDecoder(bytes.NewReader([]byte(content)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&v); err != nil {
+		return v, &SchemaError{Content: content, Err: err}
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return v, &SchemaError{Content: content, Err: errors.New("unexpected data after the JSON value")}
+	}
+	return v, nil
+}
+
+// CompleteInto asks c for a response following T's schema, validates it and
+// decodes it into T. Wrap c in a ValidatingCompleter to have invalid responses
+// repaired rather than rejected. Errors are a *TransportError, a *RefusalError
+// or a *SchemaError, unless the schema for T can't be generated.
+func CompleteInto[T any](ctx context.Context, c Completer, systemPrompt, userPrompt string, opts Options) (T, error) {
+	var zero T
+	schema, err := SchemaFor[T]()
+	if err != nil {
+		return zero, err
+	}
+
+	resp, err := c.Complete(ctx, Request{
+		Schema:       schema,
+		SystemPrompt: systemPrompt,
+		UserPrompt:   userPrompt,
+		Options:      opts,
+	})
+	if err != nil {
+		return zero, err
+	}
+	if err := Validate(schema, resp.Content); err != nil {
+		return zero, &SchemaError{Content: resp.Content, Err: err}
+	}
+	return Decode[T](resp.Content)
+}
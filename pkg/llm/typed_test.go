@@ -0,0 +1,109 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type verdict struct {
+	Keep   bool   `json:"keep" jsonschema:"description=Whether to keep it"`
+	Reason string `json:"reason" jsonschema:"description=Why"`
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    verdict
+		wantErr bool
+	}{
+		{name: "Valid", content: `{"keep": true, "reason": "fast"}`, want: verdict{Keep: true, Reason: "fast"}},
+		{name: "Surrounding whitespace", content: " \n{\"keep\": false, \"reason\": \"slow\"}\n", want: verdict{Reason: "slow"}},
+		{name: "Truncated", content: `{"keep": true, "reason": "fa`, wantErr: true},
+		{name: "Unknown field", content: `{"is_relevant": true}`, wantErr: true},
+		{name: "Wrong type", content: `{"keep": "yes", "reason": "fast"}`, wantErr: true},
+		{name: "Trailing data", content: `{"keep": true, "reason": "fast"} {}`, wantErr: true},
+		{name: "Empty", content: ``, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode[verdict](tt.content)
+			if tt.wantErr {
+				var schemaErr *SchemaError
+				if !errors.As(err, &schemaErr) {
+					t.Fatalf("Expected a *SchemaError, got %v", err)
+				}
+				if schemaErr.Content != tt.content {
+					t.Errorf("Expected the error to keep the content, got %q", schemaErr.Content)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode returned an error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompleteInto(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Decodes the response and sends the schema", func(t *testing.T) {
+		fake := NewFakeCompleter().Reply(`{"keep": true, "reason": "fast"}`)
+		got, err := CompleteInto[verdict](ctx, fake, "system", "user", Options{Model: "m"})
+		if err != nil {
+			t.Fatalf("CompleteInto returned an error: %v", err)
+		}
+		if !got.Keep || got.Reason != "fast" {
+			t.Errorf("Got %+v", got)
+		}
+		req := fake.Requests()[0]
+		if !strings.Contains(req.Schema, `"keep"`) || !strings.Contains(req.Schema, `"reason"`) {
+			t.Errorf("Expected the schema for verdict, got %s", req.Schema)
+		}
+		if req.SystemPrompt != "system" || req.UserPrompt != "user" || req.Options.Model != "m" {
+			t.Errorf("Request doesn't match: %+v", req)
+		}
+	})
+
+	t.Run("Typed errors", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			reply FakeReply
+			check func(error) bool
+		}{
+			{
+				name:  "Transport",
+				reply: FakeReply{Err: &TransportError{Err: errors.New("connection refused")}},
+				check: func(err error) bool { var e *TransportError; return errors.As(err, &e) },
+			},
+			{
+				name:  "Refusal",
+				reply: FakeReply{Err: &RefusalError{Refusal: "no"}},
+				check: func(err error) bool {
+					var e *RefusalError
+					return errors.As(err, &e) && errors.Is(err, LLMResponseError)
+				},
+			},
+			{
+				name:  "Schema violation",
+				reply: FakeReply{Content: `{"keep": "maybe"}`},
+				check: func(err error) bool { var e *SchemaError; return errors.As(err, &e) },
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := CompleteInto[verdict](ctx, NewFakeCompleter(tt.reply), "system", "user", Options{})
+				if !tt.check(err) {
+					t.Errorf("Unexpected error %v", err)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,264 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError lists the ways a response breaks its schema.
+type ValidationError struct {
+	// Problems describes each violation with the JSON path it was found at,
+	// e.g. "$.tweet: longer than 280 characters".
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Problems, "; ")
+}
+
+// Validate checks content against a JSON schema string as produced by
+// GenerateSchemaFromType. It supports the keywords that generator emits: type,
+// properties, required, additionalProperties, items, enum, const, minLength,
+// maxLength, minimum, maximum, exclusiveMinimum, exclusiveMaximum, minItems and
+// maxItems. Other keywords are ignored. A violation is a *ValidationError.
+func Validate(schema, content string) error {
+	var s map[string]interface{}
+	if err := json.Unmarshal([]byte(schema), &s); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader([]byte(content)))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return &ValidationError{Problems: []string{fmt.Sprintf("$: not valid JSON: %v", err)}}
+	}
+	if dec.More() {
+		return &ValidationError{Problems: []string{"$: unexpected data after the JSON value"}}
+	}
+
+	var problems []string
+	validateValue(s, v, "$", &problems)
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validateValue appends the ways v breaks schema s to problems.
+func validateValue(s map[string]interface{}, v interface{}, path string, problems *[]string) {
+	report := func(format string, args ...interface{}) {
+		*problems = append(*problems, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if types := schemaTypes(s["type"]); len(types) > 0 && !matchesType(types, v) {
+		report("expected %s, got %s", strings.Join(types, " or "), jsonType(v))
+		return
+	}
+	if enum, ok := s["enum"].([]interface{}); ok && !containsValue(enum, v) {
+		report("must be one of %s", formatValues(enum))
+	}
+	if c, ok := s["const"]; ok && !equalValues(c, v) {
+		report("must be %s", formatValues([]interface{}{c}))
+	}
+
+	switch v := v.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if min, ok := schemaNumber(s["minLength"]); ok && float64(length) < min {
+			report("shorter than %s characters", formatFigure(min))
+		}
+		if max, ok := schemaNumber(s["maxLength"]); ok && float64(length) > max {
+			report("longer than %s characters", formatFigure(max))
+		}
+	case json.Number:
+		n, _ := v.Float64()
+		if min, ok := schemaNumber(s["minimum"]); ok && n < min {
+			report("less than the minimum of %s", formatFigure(min))
+		}
+		if max, ok := schemaNumber(s["maximum"]); ok && n > max {
+			report("more than the maximum of %s", formatFigure(max))
+		}
+		if min, ok := schemaNumber(s["exclusiveMinimum"]); ok && n <= min {
+			report("must be more than %s", formatFigure(min))
+		}
+		if max, ok := schemaNumber(s["exclusiveMaximum"]); ok && n >= max {
+			report("must be less than %s", formatFigure(max))
+		}
+	case []interface{}:
+		if min, ok := schemaNumber(s["minItems"]); ok && float64(len(v)) < min {
+			report("fewer than %s items", formatFigure(min))
+		}
+		if max, ok := schemaNumber(s["maxItems"]); ok && float64(len(v)) > max {
+			report("more than %s items", formatFigure(max))
+		}
+		if items, ok := s["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				validateValue(items, item, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	case map[string]interface{}:
+		validateObject(s, v, path, problems)
+	}
+}
+
+// validateObject checks the required, properties and additionalProperties
+// keywords, in a stable order.
+func validateObject(s map[string]interface{}, v map[string]interface{}, path string, problems *[]string) {
+	if required, ok := s["required"].([]interface{}); ok {
+		for _, name := range required {
+			if name, ok := name.(string); ok {
+				if _, present := v[name]; !present {
+					*problems = append(*problems, fmt.Sprintf("%s: missing required property %q", path, name))
+				}
+			}
+		}
+	}
+
+	properties, _ := s["properties"].(map[string]interface{})
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		child := path + "." + name
+		if property, ok := properties[name].(map[string]interface{}); ok {
+			validateValue(property, v[name], child, problems)
+			continue
+		}
+		switch additional := s["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				*problems = append(*problems, child+": unexpected property")
+			}
+		case map[string]interface{}:
+			validateValue(additional, v[name], child, problems)
+		}
+	}
+}
+
+// schemaTypes returns the type keyword, which is a string or a list of strings.
+func schemaTypes(t interface{}) []string {
+	switch t := t.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		var types []string
+		for _, name := range t {
+			if name, ok := name.(string); ok {
+				types = append(types, name)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// matchesType reports whether v is one of the JSON schema types.
+func matchesType(types []string, v interface{}) bool {
+	for _, t := range types {
+		switch t {
+		case "integer":
+			if n, ok := v.(json.Number); ok {
+				if f, err := n.Float64(); err == nil && f == math.Trunc(f) {
+					return true
+				}
+			}
+		default:
+			if jsonType(v) == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonType names the JSON type of a decoded value.
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// schemaNumber returns a numeric keyword's value.
+func schemaNumber(v interface{}) (float64, bool) {
+	n, ok := v.(float64)
+	return n, ok
+}
+
+// containsValue reports whether values, taken from a schema, contain v.
+func containsValue(values []interface{}, v interface{}) bool {
+	for _, candidate := range values {
+		if equalValues(candidate, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalValues compares a schema value, decoded without UseNumber, with a
+// response value decoded with it.
+func equalValues(schemaValue, v interface{}) bool {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		return err == nil && schemaValue == f
+	}
+	return reflect.DeepEqual(schemaValue, normalizeNumbers(v))
+}
+
+// normalizeNumbers converts json.Numbers to float64 so nested values compare
+// equal to values decoded from the schema.
+func normalizeNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = normalizeNumbers(item)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[k] = normalizeNumbers(item)
+		}
+		return out
+	}
+	return v
+}
+
+// formatValues formats allowed values for a problem description.
+func formatValues(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		b, _ := json.Marshal(v)
+		parts[i] = string(b)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatFigure formats a keyword's number without a trailing ".0".
+func formatFigure(v float64) string {
+	return fmt.Sprintf("%g", v)
+}
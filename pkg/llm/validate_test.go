@@ -0,0 +1,156 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// tweetSchema is what GenerateSchemaFromType produces for a struct with a
+// required tweet, an optional list of tags and an optional tone.
+const tweetSchema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["tweet"],
+  "properties": {
+    "tweet": {"type": "string", "minLength": 1, "maxLength": 10},
+    "tags": {"type": "array", "maxItems": 2, "items": {"type": "string"}},
+    "tone": {"type": "string", "enum": ["neutral", "upbeat"]},
+    "score": {"type": "integer", "minimum": 0, "maximum": 5}
+  }
+}`
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{name: "Valid", content: `{"tweet": "CPI rose", "tags": ["cpi"], "tone": "neutral", "score": 3}`},
+		{name: "Counts characters, not bytes", content: `{"tweet": "ééééééééé"}`},
+		{name: "Too long", content: `{"tweet": "CPI rose 0.4%"}`, want: []string{"$.tweet: longer than 10 characters"}},
+		{name: "Too short", content: `{"tweet": ""}`, want: []string{"$.tweet: shorter than 1 characters"}},
+		{name: "Missing required", content: `{}`, want: []string{`$: missing required property "tweet"`}},
+		{name: "Unexpected property", content: `{"tweet": "hi", "extra": 1}`, want: []string{"$.extra: unexpected property"}},
+		{name: "Wrong type", content: `{"tweet": 5}`, want: []string{"$.tweet: expected string, got number"}},
+		{name: "Not an integer", content: `{"tweet": "hi", "score": 2.5}`, want: []string{"$.score: expected integer, got number"}},
+		{name: "Out of range", content: `{"tweet": "hi", "score": 9}`, want: []string{"$.score: more than the maximum of 5"}},
+		{name: "Enum", content: `{"tweet": "hi", "tone": "angry"}`, want: []string{`$.tone: must be one of "neutral", "upbeat"`}},
+		{
+			name:    "Array items and length",
+			content: `{"tweet": "hi", "tags": ["a", 1, "c"]}`,
+			want:    []string{"$.tags: more than 2 items", "$.tags[1]: expected string, got number"},
+		},
+		{
+			name:    "Several problems",
+			content: `{"tone": "angry", "extra": true}`,
+			want:    []string{`$: missing required property "tweet"`, "$.extra: unexpected property", `$.tone: must be one of "neutral", "upbeat"`},
+		},
+		{name: "Not JSON", content: `{"tweet": "hi"`, want: []string{"$: not valid JSON"}},
+		{name: "Trailing data", content: `{"tweet": "hi"} {}`, want: []string{"$: unexpected data after the JSON value"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tweetSchema, tt.content)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Expected no problems, got %v", err)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected a *ValidationError, got %v", err)
+			}
+			if len(validationErr.Problems) != len(tt.want) {
+				t.Fatalf("Got problems %q, want %q", validationErr.Problems, tt.want)
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(validationErr.Problems[i], want) {
+					t.Errorf("Problem %d = %q, want %q", i, validationErr.Problems[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateGeneratedSchema(t *testing.T) {
+	type tweet struct {
+		Tweet string `json:"tweet" jsonschema:"required,minLength=1,maxLength=280"`
+	}
+	schema, err := SchemaFor[tweet]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(schema, `{"tweet": "CPI rose 0.4% in December."}`); err != nil {
+		t.Errorf("Expected a valid tweet, got %v", err)
+	}
+	if err := Validate(schema, `{"tweet": "`+strings.Repeat("a", 281)+`"}`); err == nil {
+		t.Error("Expected an over-length tweet to be rejected")
+	}
+}
+
+func TestValidatingCompleter(t *testing.T) {
+	ctx := context.Background()
+	req := Request{Schema: tweetSchema, SystemPrompt: "system", UserPrompt: "Write a tweet."}
+
+	t.Run("Valid on the first try", func(t *testing.T) {
+		fake := NewFakeCompleter().Reply(`{"tweet": "CPI rose"}`)
+		resp, err := NewValidatingCompleter(fake).Complete(ctx, req)
+		if err != nil {
+			t.Fatalf("Complete returned an error: %v", err)
+		}
+		if resp.Content != `{"tweet": "CPI rose"}` || len(fake.Requests()) != 1 {
+			t.Errorf("Got %q after %d requests", resp.Content, len(fake.Requests()))
+		}
+	})
+
+	t.Run("Repairs an invalid response", func(t *testing.T) {
+		fake := NewFakeCompleter().Reply(`{"tweet": "CPI rose 0.4%"}`).Reply(`{"tweet": "CPI +0.4%"}`)
+		resp, err := NewValidatingCompleter(fake).Complete(ctx, req)
+		if err != nil {
+			t.Fatalf("Complete returned an error: %v", err)
+		}
+		if resp.Content != `{"tweet": "CPI +0.4%"}` {
+			t.Errorf("Got %q, want the repaired response", resp.Content)
+		}
+		repair := fake.Requests()[1]
+		if !strings.HasPrefix(repair.UserPrompt, req.UserPrompt) ||
+			!strings.Contains(repair.UserPrompt, `{"tweet": "CPI rose 0.4%"}`) ||
+			!strings.Contains(repair.UserPrompt, "$.tweet: longer than 10 characters") {
+			t.Errorf("Repair prompt doesn't carry the response and problems:\n%s", repair.UserPrompt)
+		}
+		if repair.SystemPrompt != req.SystemPrompt || repair.Schema != req.Schema {
+			t.Error("Expected the repair to keep the system prompt and schema")
+		}
+	})
+
+	t.Run("Gives up after the repair attempts", func(t *testing.T) {
+		fake := NewFakeCompleter()
+		for i := 0; i < 3; i++ {
+			fake.Reply(`{"tweet": "far too long to fit"}`)
+		}
+		_, err := (&ValidatingCompleter{Completer: fake, MaxRepairs: 2}).Complete(ctx, req)
+		var schemaErr *SchemaError
+		if !errors.As(err, &schemaErr) || !errors.Is(err, LLMResponseError) {
+			t.Fatalf("Expected a *SchemaError matching LLMResponseError, got %v", err)
+		}
+		if n := len(fake.Requests()); n != 3 {
+			t.Errorf("Expected 3 requests, got %d", n)
+		}
+	})
+
+	t.Run("Completer errors aren't repaired", func(t *testing.T) {
+		fake := NewFakeCompleter().Fail(&RefusalError{Refusal: "no"})
+		_, err := NewValidatingCompleter(fake).Complete(ctx, req)
+		var refusalErr *RefusalError
+		if !errors.As(err, &refusalErr) {
+			t.Errorf("Expected the refusal, got %v", err)
+		}
+		if n := len(fake.Requests()); n != 1 {
+			t.Errorf("Expected 1 request, got %d", n)
+		}
+	})
+}
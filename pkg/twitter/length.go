@@ -0,0 +1,84 @@
+package twitter
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// MaxTweetLength is the longest tweet X accepts, in weighted characters.
+	MaxTweetLength = 280
+	// urlLength is what every URL counts for, since X shortens them all with t.co.
+	urlLength = 23
+)
+
+// urlPattern matches the links X shortens: anything with an http(s) scheme and
+// bare domains under the common top-level domains, e.g. "bls.gov/cpi".
+var urlPattern = regexp.MustCompile(`(?i)\bhttps?://\S+|\b(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+(?:com|org|net|gov|edu|int|mil|io|co|us|uk|ca|eu|de|fr|jp|info|biz|ly|me|ai)\b(?:[/?#]\S*)?`)
+
+// Length returns the length of a tweet the way X counts it. Latin, Greek,
+// Cyrillic and most punctuation count 1, while other characters, such as CJK
+// and emoji, count 2. An emoji with variation selectors, skin tone modifiers
+// or zero width joiners to further emoji counts as one emoji. Every URL counts
+// 23, however long it is.
+func Length(text string) int {
+	length := 0
+	for _, loc := range urlPattern.FindAllStringIndex(text, -1) {
+		url := text[loc[0]:loc[1]]
+		// Closing punctuation is left out of the link
+		trimmed := strings.TrimRight(url, `.,:;!?'")]}`)
+		length += urlLength + weightedLength(url[len(trimmed):])
+	}
+	return length + weightedLength(urlPattern.ReplaceAllString(text, ""))
+}
+
+// weightedLength counts text without URLs in weighted characters.
+func weightedLength(text string) int {
+	length := 0
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		weight := charWeight(runes[i])
+		length += weight
+		if weight > 1 {
+			i = emojiEnd(runes, i)
+		}
+	}
+	return length
+}
+
+// emojiEnd returns the index of the last rune of the emoji sequence starting at
+// runes[i], which is i for anything that isn't continued.
+func emojiEnd(runes []rune, i int) int {
+	if isRegionalIndicator(runes[i]) && i+1 < len(runes) && isRegionalIndicator(runes[i+1]) {
+		return i + 1
+	}
+	for i+1 < len(runes) {
+		switch next := runes[i+1]; {
+		case next == '\uFE0F', next >= 0x1F3FB && next <= 0x1F3FF, next >= 0xE0020 && next <= 0xE007F:
+			i++
+		case next == '\u200D' && i+2 < len(runes):
+			i += 2
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// charWeight returns the weight of a single character, following the ranges
+// in X's twitter-text configuration.
+func charWeight(r rune) int {
+	switch {
+	case r <= 0x10FF,
+		r >= 0x2000 && r <= 0x200D,
+		r >= 0x2010 && r <= 0x201F,
+		r >= 0x2032 && r <= 0x2037:
+		return 1
+	}
+	return 2
+}
+
+// isRegionalIndicator reports whether r is half of a flag emoji.
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
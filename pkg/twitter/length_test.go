@@ -0,0 +1,38 @@
+package twitter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLength(t *testing.T) {
+	testCases := []struct {
+		name string
+		text string
+		want int
+	}{
+		{"Empty", "", 0},
+		{"ASCII", "CPI rose 0.4% in December.", 26},
+		{"Latin accents and dashes", "Café prices — up 2%", 19},
+		{"Emoji count 2", "CPI 📈", 6},
+		{"CJK counts 2", "物价上涨", 8},
+		{"Emoji with a variation selector", "☀️", 2},
+		{"Emoji with a skin tone", "👍🏽", 2},
+		{"Joined emoji", "👩‍💻", 2},
+		{"Flag", "🇺🇸", 2},
+		{"Two flags", "🇺🇸🇨🇦", 4},
+		{"URL counts 23", "See https://www.bls.gov/news.release/cpi.nr0.htm", 4 + 23},
+		{"Short URL counts 23", "https://x.co", 23},
+		{"Bare domain counts 23", "More at bls.gov/cpi", 8 + 23},
+		{"Trailing punctuation isn't part of the URL", "More at bls.gov.", 8 + 23 + 1},
+		{"Decimals aren't URLs", "Rates at 4.25-4.50%", 19},
+		{"Long emoji run", strings.Repeat("📈", 140), 280},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Length(tc.text); got != tc.want {
+				t.Errorf("Length(%q) = %d, want %d", tc.text, got, tc.want)
+			}
+		})
+	}
+}